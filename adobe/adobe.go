@@ -29,6 +29,7 @@ var ignoreProperties = []cmp.Option{
 	cmpopts.IgnoreFields(envoy_api_v2_route.VirtualHost{}, "RetryPolicy"),
 	cmpopts.IgnoreFields(http.HttpConnectionManager{}, "HttpFilters"),
 	cmpopts.IgnoreFields(http.Rds{}, "RouteConfigName"),
+	cmpopts.IgnoreTypes([]projcontour.StatusCondition{}),
 }
 
 // list of tests to ignore (assuming names are unique across suites)
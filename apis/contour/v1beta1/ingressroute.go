@@ -42,6 +42,70 @@ type VirtualHost struct {
 	// matching certificate
 	// +optional
 	TLS *TLS `json:"tls,omitempty"`
+	// Aliases are the additional domain names the virtual host is
+	// served on. They are matched by SNI as well when TLS is
+	// terminated, in which case the certificate must cover them, or
+	// passed through, in which case they may be wildcards of the form
+	// *.example.com.
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
+	// Port selects, by its port, the additional listener configured in
+	// Contour serving the virtual host rather than the HTTP and HTTPS
+	// listeners. An https listener requires TLS, an http one rules it out.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port,omitempty"`
+	// AccessLog overrides the access log of the virtual host.
+	// +optional
+	AccessLog *AccessLog `json:"accessLog,omitempty"`
+}
+
+// AccessLog defines the access log of a virtual host.
+type AccessLog struct {
+	// Filter selects the requests logged, overriding the filter
+	// configured globally. It does not apply to tcpproxy connections.
+	// +optional
+	Filter *AccessLogFilter `json:"filter,omitempty"`
+	// Path is the absolute path of the file the access log is written to,
+	// overriding the access log path of the listener.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Format overrides the access log format configured globally.
+	// Defaults to json if fields are set.
+	// +optional
+	// +kubebuilder:validation:Enum=envoy;json
+	Format string `json:"format,omitempty"`
+	// Fields are the JSON fields logged, overriding the json-fields
+	// configured globally.
+	// +optional
+	Fields []string `json:"fields,omitempty"`
+	// Headers are request headers logged in addition to the fields,
+	// such as x-adobe-tenant.
+	// +optional
+	Headers []string `json:"headers,omitempty"`
+}
+
+// AccessLogFilter selects the requests logged, which must satisfy all the
+// conditions set.
+type AccessLogFilter struct {
+	// StatusCodes are the response codes logged, either single codes such
+	// as 503, or ranges such as 500-599.
+	// +optional
+	StatusCodes []string `json:"statusCodes,omitempty"`
+	// MinDuration logs only the requests lasting at least this duration.
+	// +optional
+	MinDuration *Duration `json:"minDuration,omitempty"`
+	// SamplePercent logs only this percentage of the requests, which the
+	// access_log.sample_percent runtime key overrides. Defaults to 100.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	SamplePercent uint32 `json:"samplePercent,omitempty"`
+	// Headers are conditions on the request headers, such as a user-agent
+	// header which notcontains kube-probe.
+	// +optional
+	Headers []projcontour.HeaderCondition `json:"headers,omitempty"`
 }
 
 // TLS describes tls properties. The SNI names that will be matched on
@@ -61,6 +125,19 @@ type TLS struct {
 	// backing cluster.
 	// +optional
 	Passthrough bool `json:"passthrough,omitempty"`
+	// SecondarySecretName is the name of a second secret holding a
+	// certificate of the other key type, RSA or ECDSA, than the one
+	// of SecretName, which is presented to the clients supporting it.
+	// +optional
+	SecondarySecretName string `json:"secondarySecretName,omitempty"`
+	// CipherSuites lists, in order of preference, the cipher suites
+	// this vhost offers for TLS 1.2, overriding those configured globally.
+	// +optional
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+	// ECDHCurves lists the ECDH curves this vhost offers, overriding
+	// those configured globally.
+	// +optional
+	ECDHCurves []string `json:"ecdhCurves,omitempty"`
 }
 
 // Route contains the set of routes for a virtual host
@@ -125,6 +202,19 @@ type TCPProxy struct {
 	// Delegate specifies that this tcpproxy should be delegated to another IngressRoute
 	// +optional
 	Delegate *Delegate `json:"delegate,omitempty"`
+	// IdleTimeout is the duration after which the proxied connections with
+	// no traffic are closed, 0s disabling the timeout. Defaults to 9001s.
+	// +optional
+	IdleTimeout *Duration `json:"idleTimeout,omitempty"`
+	// MaxConnectAttempts is the number of attempts to connect to the
+	// upstream before the connection is closed. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConnectAttempts uint32 `json:"maxConnectAttempts,omitempty"`
+	// DisableAccessLog, if set, leaves the proxied connections out of the
+	// access log.
+	// +optional
+	DisableAccessLog bool `json:"disableAccessLog,omitempty"`
 }
 
 // Service defines an upstream to proxy traffic to
@@ -138,17 +228,43 @@ type Service struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	Weight int64 `json:"weight,omitempty"`
-	// HealthCheck defines optional healthchecks on the upstream service
+	// HealthCheck defines optional healthchecks on the upstream service.
+	// The services of a tcpproxy are checked by connecting only, the
+	// settings specific to HTTP being ignored.
 	// +optional
 	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
 	// LB Algorithm to apply (see https://github.com/projectcontour/contour/blob/master/design/ingressroute-design.md#load-balancing)
 	// +optional
 	Strategy string `json:"strategy,omitempty"`
+	// LoadBalancerPolicy is the structured form of Strategy, which
+	// allows the strategy to be tuned. If both are set their strategy
+	// must match.
+	// +optional
+	LoadBalancerPolicy *projcontour.LoadBalancerPolicy `json:"loadBalancerPolicy,omitempty"`
 	// UpstreamValidation defines how to verify the backend service's certificate
 	// +optional
 	UpstreamValidation *projcontour.UpstreamValidation `json:"validation,omitempty"`
+	// ClientCertificate is the name of the Kubernetes secret of type
+	// kubernetes.io/tls presented to the backend service when speaking
+	// tls or h2, overriding the client certificate configured globally.
+	// A secret of another namespace, referenced as namespace/name, must
+	// be delegated with a TLSCertificateDelegation.
+	// +optional
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+	// UpstreamTLS defines the TLS parameters of the connections to the
+	// backend service when speaking tls or h2, overriding those configured
+	// globally.
+	// +optional
+	UpstreamTLS *projcontour.UpstreamTLS `json:"upstreamTLS,omitempty"`
 
 	IdleTimeout *Duration `json:"idleTimeout,omitempty"`
+	// Priority of the service within the route. Only the service with the
+	// lowest priority receives traffic; the next one is failed over to
+	// when it has no healthy hosts left. Services of a route using
+	// priorities must each have a distinct priority.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Priority int `json:"priority,omitempty"`
 }
 
 // HealthCheck defines health checks on the upstream service.
@@ -174,6 +290,33 @@ type HealthCheck struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	HealthyThresholdCount int64 `json:"healthyThresholdCount"`
+	// The ranges of HTTP status codes considered healthy.
+	// If left empty (default value), 200-399 will be used.
+	// +optional
+	ExpectedStatuses []projcontour.HTTPStatusRange `json:"expectedStatuses,omitempty"`
+	// Headers added to the health check request
+	// +optional
+	RequestHeaders []projcontour.HeaderValue `json:"requestHeaders,omitempty"`
+	// The maximum jitter (seconds) added to the first health check
+	// of a newly discovered host. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	InitialJitterSeconds *int64 `json:"initialJitterSeconds,omitempty"`
+	// The maximum jitter, as a percentage of the interval, added to each
+	// health check. Defaults to 100.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	IntervalJitterPercent *int64 `json:"intervalJitterPercent,omitempty"`
+	// The interval (seconds) between health checks of a cluster
+	// which has not received traffic yet.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	NoTrafficIntervalSeconds int64 `json:"noTrafficIntervalSeconds,omitempty"`
+	// GRPC, if set, checks the health of the upstream using the
+	// grpc.health.v1 protocol instead of HTTP; Path and ExpectedStatuses
+	// are ignored. The upstream must use the h2 or h2c protocol.
+	// +optional
+	GRPC *projcontour.GRPCHealthCheckPolicy `json:"grpc,omitempty"`
 }
 
 // Delegate allows for delegating VHosts to other IngressRoutes
@@ -105,6 +105,20 @@ type VirtualHost struct {
 	// matching certificate
 	// +optional
 	TLS *TLS `json:"tls,omitempty"`
+	// Aliases are the additional domain names the virtual host is
+	// served on. They are matched by SNI as well when TLS is
+	// terminated, in which case the certificate must cover them, or
+	// passed through, in which case they may be wildcards of the form
+	// *.example.com.
+	// +optional
+	Aliases []string `json:"aliases,omitempty"`
+	// Port selects, by its port, the additional listener configured in
+	// Contour serving the virtual host rather than the HTTP and HTTPS
+	// listeners. An https listener requires TLS, an http one rules it out.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port,omitempty"`
 }
 
 // TLS describes tls properties. The SNI names that will be matched on
@@ -165,6 +179,11 @@ type Route struct {
 	// The load balancing policy for this route.
 	// +optional
 	LoadBalancerPolicy *LoadBalancerPolicy `json:"loadBalancerPolicy,omitempty"`
+	// The hash policies used to select the upstream endpoint of a
+	// request. Requires the `RingHash` or `Maglev` load balancing
+	// strategy, `RingHash` is used if another strategy is specified.
+	// +optional
+	HashPolicy []HashPolicy `json:"hashPolicy,omitempty"`
 	// The policy for rewriting the path of the request URL
 	// after the request has been routed to a Service.
 	//
@@ -206,6 +225,20 @@ type TCPProxy struct {
 	// The health check policy for this tcp proxy
 	// +optional
 	HealthCheckPolicy *TCPHealthCheckPolicy `json:"healthCheckPolicy,omitempty"`
+	// IdleTimeout is the duration, such as "30m", after which the proxied
+	// connections with no traffic are closed. "infinity" disables the
+	// timeout. Defaults to 9001s.
+	// +optional
+	IdleTimeout string `json:"idleTimeout,omitempty"`
+	// MaxConnectAttempts is the number of attempts to connect to the
+	// upstream before the connection is closed. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConnectAttempts uint32 `json:"maxConnectAttempts,omitempty"`
+	// DisableAccessLog, if set, leaves the proxied connections out of the
+	// access log.
+	// +optional
+	DisableAccessLog bool `json:"disableAccessLog,omitempty"`
 }
 
 // TCPProxyInclude describes a target HTTPProxy document which contains the TCPProxy details.
@@ -233,9 +266,26 @@ type Service struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	Weight int64 `json:"weight,omitempty"`
+	// HealthCheckPolicy defines the health check of this service of a
+	// tcpproxy, overriding the health check of the tcpproxy. It is
+	// ignored on the services of a route.
+	// +optional
+	HealthCheckPolicy *TCPHealthCheckPolicy `json:"healthCheckPolicy,omitempty"`
 	// UpstreamValidation defines how to verify the backend service's certificate
 	// +optional
 	UpstreamValidation *UpstreamValidation `json:"validation,omitempty"`
+	// ClientCertificate is the name of the Kubernetes secret of type
+	// kubernetes.io/tls presented to the backend service when speaking
+	// tls or h2, overriding the client certificate configured globally.
+	// A secret of another namespace, referenced as namespace/name, must
+	// be delegated with a TLSCertificateDelegation.
+	// +optional
+	ClientCertificate string `json:"clientCertificate,omitempty"`
+	// UpstreamTLS defines the TLS parameters of the connections to the
+	// backend service when speaking tls or h2, overriding those configured
+	// globally.
+	// +optional
+	UpstreamTLS *UpstreamTLS `json:"upstreamTLS,omitempty"`
 	// If Mirror is true the Service will receive a read only mirror of the traffic for this route.
 	Mirror bool `json:"mirror,omitempty"`
 	// The policy for managing request headers during proxying
@@ -244,6 +294,13 @@ type Service struct {
 	// The policy for managing response headers during proxying
 	// +optional
 	ResponseHeadersPolicy *HeadersPolicy `json:"responseHeadersPolicy,omitempty"`
+	// Priority of the service within the route. Only the service with the
+	// lowest priority receives traffic; the next one is failed over to
+	// when it has no healthy hosts left. Services of a route using
+	// priorities must each have a distinct priority.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Priority int `json:"priority,omitempty"`
 }
 
 // HTTPHealthCheckPolicy defines health checks on the upstream service.
@@ -268,6 +325,51 @@ type HTTPHealthCheckPolicy struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=0
 	HealthyThresholdCount int64 `json:"healthyThresholdCount"`
+	// The ranges of HTTP status codes considered healthy.
+	// If left empty (default value), 200-399 will be used.
+	// +optional
+	ExpectedStatuses []HTTPStatusRange `json:"expectedStatuses,omitempty"`
+	// Headers added to the health check request
+	// +optional
+	RequestHeaders []HeaderValue `json:"requestHeaders,omitempty"`
+	// The maximum jitter (seconds) added to the first health check
+	// of a newly discovered host. Defaults to 1.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	InitialJitterSeconds *int64 `json:"initialJitterSeconds,omitempty"`
+	// The maximum jitter, as a percentage of the interval, added to each
+	// health check. Defaults to 100.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	IntervalJitterPercent *int64 `json:"intervalJitterPercent,omitempty"`
+	// The interval (seconds) between health checks of a cluster
+	// which has not received traffic yet.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	NoTrafficIntervalSeconds int64 `json:"noTrafficIntervalSeconds,omitempty"`
+	// GRPC, if set, checks the health of the upstream using the
+	// grpc.health.v1 protocol instead of HTTP; Path and ExpectedStatuses
+	// are ignored. The upstream must use the h2 or h2c protocol.
+	// +optional
+	GRPC *GRPCHealthCheckPolicy `json:"grpc,omitempty"`
+}
+
+// HTTPStatusRange is a range of HTTP status codes.
+type HTTPStatusRange struct {
+	// Start of the range, inclusive
+	// +kubebuilder:validation:Minimum=100
+	Start int64 `json:"start"`
+	// End of the range, exclusive
+	// +kubebuilder:validation:Maximum=600
+	End int64 `json:"end"`
+}
+
+// GRPCHealthCheckPolicy defines a grpc.health.v1 health check.
+type GRPCHealthCheckPolicy struct {
+	// The service name sent in the health check request.
+	// If left empty (default value), the overall health of the server is checked.
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
 }
 
 // TCPHealthCheckPolicy defines health checks on the upstream service.
@@ -360,11 +462,89 @@ type PathRewritePolicy struct {
 type LoadBalancerPolicy struct {
 	// Strategy specifies the policy used to balance requests
 	// across the pool of backend pods. Valid policy names are
-	// `Random`, `RoundRobin`, `WeightedLeastRequest`, `Random`
-	// and `Cookie`. If an unknown strategy name is specified
-	// or no policy is supplied, the default `RoundRobin` policy
-	// is used.
+	// `Random`, `RoundRobin`, `WeightedLeastRequest`, `Random`,
+	// `Cookie`, `RingHash` and `Maglev`. If an unknown strategy
+	// name is specified or no policy is supplied, the default
+	// `RoundRobin` policy is used.
 	Strategy string `json:"strategy,omitempty"`
+	// RingHash tunes the `RingHash` strategy.
+	// +optional
+	RingHash *RingHashLoadBalancerOptions `json:"ringHash,omitempty"`
+	// LeastRequest tunes the `WeightedLeastRequest` strategy.
+	// +optional
+	LeastRequest *LeastRequestLoadBalancerOptions `json:"leastRequest,omitempty"`
+}
+
+// RingHashLoadBalancerOptions defines the size of the hash ring.
+// Larger rings better reflect the weights of the endpoints at the
+// cost of memory and of the time to build the ring.
+type RingHashLoadBalancerOptions struct {
+	// MinimumRingSize is the minimum number of entries of the ring.
+	// Defaults to 1024.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinimumRingSize uint64 `json:"minimumRingSize,omitempty"`
+	// MaximumRingSize is the maximum number of entries of the ring.
+	// Defaults to 8M, which is also the upper bound.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=8388608
+	MaximumRingSize uint64 `json:"maximumRingSize,omitempty"`
+}
+
+// LeastRequestLoadBalancerOptions defines how hosts are chosen by the
+// `WeightedLeastRequest` strategy.
+type LeastRequestLoadBalancerOptions struct {
+	// ChoiceCount is the number of random hosts among which the host
+	// with the fewest active requests is chosen. Defaults to 2.
+	// +optional
+	// +kubebuilder:validation:Minimum=2
+	ChoiceCount uint32 `json:"choiceCount,omitempty"`
+}
+
+// HashPolicy defines an input of the hash used by the `RingHash`
+// and `Maglev` load balancing strategies.
+//
+// Exactly one of Header, Cookie or ConnectionProperties must be specified.
+type HashPolicy struct {
+	// Header hashes the value of a request header.
+	// +optional
+	Header *HashPolicyHeader `json:"header,omitempty"`
+	// Cookie hashes the value of a request cookie.
+	// +optional
+	Cookie *HashPolicyCookie `json:"cookie,omitempty"`
+	// ConnectionProperties hashes the properties of the downstream connection.
+	// +optional
+	ConnectionProperties *HashPolicyConnectionProperties `json:"connectionProperties,omitempty"`
+	// Terminal short circuits the evaluation of the remaining hash
+	// policies if this one produced a hash.
+	// +optional
+	Terminal bool `json:"terminal,omitempty"`
+}
+
+// HashPolicyHeader hashes the value of a request header.
+type HashPolicyHeader struct {
+	// HeaderName is the name of the request header to hash.
+	HeaderName string `json:"headerName"`
+}
+
+// HashPolicyCookie hashes the value of a request cookie.
+type HashPolicyCookie struct {
+	// Name of the cookie to hash.
+	Name string `json:"name"`
+	// TTL, if set, generates the cookie with this time to live
+	// when it is missing from the request.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+	// Path of the generated cookie.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// HashPolicyConnectionProperties hashes the properties of the downstream connection.
+type HashPolicyConnectionProperties struct {
+	// SourceIP hashes the source IP address of the connection.
+	SourceIP bool `json:"sourceIp"`
 }
 
 // HeadersPolicy defines how headers are managed during forwarding.
@@ -401,6 +581,28 @@ type UpstreamValidation struct {
 	SubjectName string `json:"subjectName"`
 }
 
+// UpstreamTLS defines the TLS parameters of the connections to the backend service.
+type UpstreamTLS struct {
+	// MinimumProtocolVersion is the minimum TLS version negotiated with
+	// the backend service. Values may be 1.2 or 1.3.
+	// +kubebuilder:validation:Enum="1.2";"1.3"
+	// +optional
+	MinimumProtocolVersion string `json:"minimumProtocolVersion,omitempty"`
+	// MaximumProtocolVersion is the maximum TLS version negotiated with
+	// the backend service. Values may be 1.2 or 1.3.
+	// +kubebuilder:validation:Enum="1.2";"1.3"
+	// +optional
+	MaximumProtocolVersion string `json:"maximumProtocolVersion,omitempty"`
+	// CipherSuites lists, in order of preference, the cipher suites offered
+	// to the backend service when negotiating TLS 1.2.
+	// +optional
+	CipherSuites []string `json:"cipherSuites,omitempty"`
+	// ALPNProtocols lists the protocols offered to the backend service
+	// during ALPN, replacing the default "h2" of the h2 protocol.
+	// +optional
+	ALPNProtocols []string `json:"alpnProtocols,omitempty"`
+}
+
 // DownstreamValidation defines how to verify the client certificate.
 type DownstreamValidation struct {
 	// Name of a Kubernetes secret that contains a CA certificate bundle.
@@ -419,6 +621,40 @@ type Status struct {
 	// +optional
 	// LoadBalancer contains the current status of the load balancer.
 	LoadBalancer corev1.LoadBalancerStatus `json:"loadBalancer,omitempty"`
+	// Conditions holds the Valid condition of the object, followed by
+	// an Error or Warning condition for each problem found about it.
+	// +optional
+	Conditions []StatusCondition `json:"conditions,omitempty"`
+}
+
+// StatusConditionType is the type of a StatusCondition.
+type StatusConditionType string
+
+const (
+	// ConditionValid reports whether the object is valid.
+	ConditionValid StatusConditionType = "Valid"
+	// ConditionError reports an error which invalidates the object.
+	ConditionError StatusConditionType = "Error"
+	// ConditionWarning reports a problem which does not invalidate the object.
+	ConditionWarning StatusConditionType = "Warning"
+)
+
+// StatusCondition is an observation about the state of an object.
+type StatusCondition struct {
+	// Type of the condition, one of Valid, Error or Warning.
+	Type StatusConditionType `json:"type"`
+	// Status of the condition, one of True, False or Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// Reason is a CamelCase identifier of the cause of the condition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable description of the condition.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the object the condition
+	// was computed from.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +genclient
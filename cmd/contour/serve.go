@@ -138,17 +138,94 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 	// Create a set of SharedInformerFactories for each root-ingressroute namespace (if defined)
 	namespacedInformerFactories := map[string]coreinformers.SharedInformerFactory{}
 
-	// Validate fallback certificate parameters
-	fallbackCert, err := ctx.fallbackCertificate()
+	// Adobe - Select the default certificate served to the clients
+	// without SNI, which replaces the fallback certificate or the
+	// DEFAULT_CERTIFICATE environment variable
+	defaultCert, err := ctx.defaultCertificate()
+	if err != nil {
+		log.WithField("context", "default-certificates").Fatalf("invalid default certificate configuration: %q", err)
+	}
+	if err := checkDefaultCertificate(clients.ClientSet(), defaultCert); err != nil {
+		log.WithField("context", "default-certificates").Fatalf("invalid default certificate: %q", err)
+	}
+	fallbackDC, allVirtualHostsCert, err := ctx.noSNICertificates(defaultCert)
 	if err != nil {
 		log.WithField("context", "fallback-certificate").Fatalf("invalid fallback certificate configuration: %q", err)
 	}
+	var fallbackCert *k8s.FullName
+	if fallbackDC != nil {
+		fallbackCert = &k8s.FullName{
+			Name:      fallbackDC.Name,
+			Namespace: fallbackDC.Namespace,
+		}
+	}
+
+	// Adobe - Validate upstream client certificate parameters
+	clientCert, err := ctx.upstreamClientCertificate()
+	if err != nil {
+		log.WithField("context", "upstream-client-certificate").Fatalf("invalid upstream client certificate configuration: %q", err)
+	}
+
+	// Adobe - Validate downstream cipher suites and ECDH curves
+	if err := ctx.downstreamTLSParams(); err != nil {
+		log.WithField("context", "tls").Fatalf("invalid TLS configuration: %q", err)
+	}
+
+	// Adobe - Validate upstream TLS parameters
+	upstreamTLS, err := ctx.upstreamTLS()
+	if err != nil {
+		log.WithField("context", "upstream-tls").Fatalf("invalid upstream TLS configuration: %q", err)
+	}
+
+	// Adobe - Validate the additional listeners
+	listeners, err := ctx.additionalListeners()
+	if err != nil {
+		log.WithField("context", "listeners").Fatalf("invalid listeners configuration: %q", err)
+	}
+
+	// Adobe - Validate the access log service
+	accessLogSink, err := ctx.accessLogSink()
+	if err != nil {
+		log.WithField("context", "accesslog-sink").Fatalf("invalid access log sink configuration: %q", err)
+	}
+
+	// Adobe - Validate the access log fields
+	unknownFields, err := ctx.accessLogFields()
+	if err != nil {
+		log.WithField("context", "json-fields").Fatalf("invalid access log fields configuration: %q", err)
+	}
+	for _, f := range unknownFields {
+		log.WithField("context", "json-fields").Warnf("ignoring unknown access log field %q", f)
+	}
+
+	// Adobe - Validate the access log paths of the virtual hosts
+	if err := ctx.accessLogPaths(); err != nil {
+		log.WithField("context", "accesslog-paths").Fatalf("invalid access log paths configuration: %q", err)
+	}
+
+	// Adobe - Validate the access log filter
+	accessLogFilter, err := ctx.accessLogFilter()
+	if err != nil {
+		log.WithField("context", "accesslog-filter").Fatalf("invalid access log filter configuration: %q", err)
+	}
 
 	if rootNamespaces := ctx.ingressRouteRootNamespaces(); len(rootNamespaces) > 0 {
 		// Add the FallbackCertificateNamespace to the root-namespaces if not already
-		if !contains(rootNamespaces, ctx.TLSConfig.FallbackCertificate.Namespace) && fallbackCert != nil {
-			rootNamespaces = append(rootNamespaces, ctx.FallbackCertificate.Namespace)
-			log.WithField("context", "fallback-certificate").Infof("fallback certificate namespace %q not defined in 'root-namespaces', adding namespace to watch", ctx.FallbackCertificate.Namespace)
+		if fallbackCert != nil && !contains(rootNamespaces, fallbackCert.Namespace) {
+			rootNamespaces = append(rootNamespaces, fallbackCert.Namespace)
+			log.WithField("context", "fallback-certificate").Infof("fallback certificate namespace %q not defined in 'root-namespaces', adding namespace to watch", fallbackCert.Namespace)
+		}
+
+		// Adobe - likewise for the default certificate of all the virtual hosts
+		if allVirtualHostsCert != nil && !contains(rootNamespaces, allVirtualHostsCert.Namespace) {
+			rootNamespaces = append(rootNamespaces, allVirtualHostsCert.Namespace)
+			log.WithField("context", "default-certificates").Infof("default certificate namespace %q not defined in 'root-namespaces', adding namespace to watch", allVirtualHostsCert.Namespace)
+		}
+
+		// Adobe - likewise for the upstream client certificate namespace
+		if clientCert != nil && !contains(rootNamespaces, clientCert.Namespace) {
+			rootNamespaces = append(rootNamespaces, clientCert.Namespace)
+			log.WithField("context", "upstream-client-certificate").Infof("upstream client certificate namespace %q not defined in 'root-namespaces', adding namespace to watch", clientCert.Namespace)
 		}
 
 		for _, namespace := range rootNamespaces {
@@ -171,6 +248,20 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 		return err
 	}
 
+	// Adobe - the status is written asynchronously
+	contourMetrics := metrics.NewMetrics(registry)
+	statusQueue := k8s.NewStatusQueue(&k8s.StatusWriter{
+		Client:    clients.DynamicClient(),
+		Converter: converter,
+	}, contourMetrics, log.WithField("context", "statusQueue"))
+
+	// Adobe - events are recorded by the leader, at a limited rate
+	eventRecorder, stopEvents, err := clients.NewEventRecorder(log.WithField("context", "events"))
+	if err != nil {
+		return err
+	}
+	defer stopEvents()
+
 	// step 3. build our mammoth Kubernetes event handler.
 	eventHandler := &contour.EventHandler{
 		CacheHandler: &contour.CacheHandler{
@@ -185,19 +276,21 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 				AccessLogType:          ctx.AccessLogFormat,
 				AccessLogFields:        ctx.AccessLogFields,
 				MinimumProtocolVersion: annotation.MinProtoVersion(ctx.TLSConfig.MinimumProtocolVersion),
-				DefaultCertificate:     defaultCertificate(),
+				DefaultCertificate:     allVirtualHostsCert.allVirtualHosts(),
 				RequestTimeout:         ctx.RequestTimeout,
+				CipherSuites:           ctx.TLSConfig.CipherSuites,
+				ECDHCurves:             ctx.TLSConfig.ECDHCurves,
+				AccessLogSink:          accessLogSink,
+				AccessLogFilter:        accessLogFilter,
 			},
 			ListenerCache: contour.NewListenerCache(ctx.statsAddr, ctx.statsPort),
 			FieldLogger:   log.WithField("context", "CacheHandler"),
-			Metrics:       metrics.NewMetrics(registry),
+			Metrics:       contourMetrics,
 		},
 		HoldoffDelay:    100 * time.Millisecond,
 		HoldoffMaxDelay: 500 * time.Millisecond,
-		StatusClient: &k8s.StatusWriter{
-			Client:    clients.DynamicClient(),
-			Converter: converter,
-		},
+		StatusClient:    statusQueue,
+		StatusEvents:    &contour.StatusEvents{Recorder: eventRecorder},
 		Builder: dag.Builder{
 			Source: dag.KubernetesCache{
 				RootNamespaces: ctx.ingressRouteRootNamespaces(),
@@ -205,6 +298,9 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 				FieldLogger:    log.WithField("context", "KubernetesCache"),
 			},
 			DisablePermitInsecure: ctx.DisablePermitInsecure,
+			Listeners:             listeners,
+			AccessLogPaths:        ctx.AccessLogPaths,
+			ValidAccessLogFields:  validAccessLogFields,
 		},
 		FieldLogger: log.WithField("context", "contourEventHandler"),
 	}
@@ -215,6 +311,30 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 
 		eventHandler.FallbackCertificate = fallbackCert
 	}
+	if allVirtualHostsCert != nil {
+		log.WithField("context", "default-certificates").Infof("enabled default certificate for all virtual hosts with secret: %q", allVirtualHostsCert)
+	}
+
+	// Adobe - Set the upstream client certificate if configured
+	if clientCert != nil {
+		log.WithField("context", "upstream-client-certificate").Infof("enabled upstream client certificate with secret: %q", clientCert)
+
+		eventHandler.UpstreamClientCertificate = clientCert
+	}
+
+	// Adobe - Set the certificate expiry handling
+	if ctx.CertificateExpiry.WarningPeriod < 0 {
+		log.WithField("context", "certificate-expiry").Fatalf("invalid certificate expiry configuration: warning period %v must be >= 0", ctx.CertificateExpiry.WarningPeriod)
+	}
+	eventHandler.CertificateExpiryWarning = ctx.CertificateExpiry.WarningPeriod
+	eventHandler.RefuseExpiredCertificates = ctx.CertificateExpiry.RefuseExpired
+
+	// Adobe - Set the upstream TLS parameters if configured
+	if upstreamTLS != nil {
+		log.WithField("context", "upstream-tls").Infof("enabled upstream TLS parameters: %+v", *upstreamTLS)
+
+		eventHandler.UpstreamTLS = upstreamTLS
+	}
 
 	// wrap eventHandler in a converter for objects from the dynamic client.
 	// and an EventRecorder which tracks API server events.
@@ -253,10 +373,16 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 	// step 5. endpoints updates are handled directly by the EndpointsTranslator
 	// due to their high update rate and their orthogonal nature.
 	et := &contour.EndpointsTranslator{
-		FieldLogger: log.WithField("context", "endpointstranslator"),
+		FieldLogger:              log.WithField("context", "endpointstranslator"),
+		PublishNotReadyAddresses: ctx.EndpointsConfig.PublishNotReady, // Adobe
+		DrainingPeriod:           ctx.EndpointsConfig.DrainingPeriod,  // Adobe
 	}
 
 	informerSyncList.RegisterInformer(informerFactory.Core().V1().Endpoints().Informer(), et)
+	// Adobe - nodes provide the locality of the endpoints
+	informerSyncList.RegisterInformer(informerFactory.Core().V1().Nodes().Informer(), et)
+	// Adobe - services provide the slow start window of the endpoints
+	informerSyncList.RegisterInformer(informerFactory.Core().V1().Services().Informer(), et)
 
 	// step 6. setup workgroup runner and register informers.
 	var g workgroup.Group
@@ -269,6 +395,11 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 
 	// step 7. register our event handler with the workgroup
 	g.Add(eventHandler.Start())
+	g.Add(statusQueue.Start)
+
+	// Adobe - raise the weights of the endpoints in their slow start window
+	// and expire the draining endpoints
+	g.Add(et.Start)
 
 	// step 8. create metrics service and register with workgroup.
 	metricsvc := httpsvc.Service{
@@ -376,6 +507,7 @@ func doServe(log logrus.FieldLogger, ctx *serveContext) error {
 		}
 		opts := ctx.grpcOptions()
 		s := cgrpc.NewAPI(log, resources, registry, opts...)
+		ctx.registerAccessLogReceiver(s, log) // Adobe - builtin access log service
 		addr := net.JoinHostPort(ctx.xdsAddr, strconv.Itoa(ctx.xdsPort))
 		l, err := net.Listen("tcp", addr)
 		if err != nil {
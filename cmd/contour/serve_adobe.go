@@ -2,13 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"os"
+	"path"
+	"strconv"
+	"strings"
 
+	als "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v2"
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/annotation"
 	"github.com/projectcontour/contour/internal/contour"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/envoy"
+	cgrpc "github.com/projectcontour/contour/internal/grpc"
 	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
 )
 
 func initCache(clients *k8s.Clients, eh *contour.EventHandler, et *contour.EndpointsTranslator) error {
@@ -61,6 +77,7 @@ func initCache(clients *k8s.Clients, eh *contour.EventHandler, et *contour.Endpo
 		if eh.Builder.Source.Insert(&services.Items[i]) {
 			svcCached++
 		}
+		et.OnAdd(&services.Items[i])
 	}
 	eh.WithField("count", svcCached).WithField("found", len(services.Items)).Info("services")
 
@@ -97,6 +114,17 @@ func initCache(clients *k8s.Clients, eh *contour.EventHandler, et *contour.Endpo
 	}
 	eh.WithField("count", sCached).WithField("found", len(secrets.Items)).WithField("type", v1.SecretTypeTLS).Info("secrets")
 
+	// Nodes
+	// loaded before the endpoints so their locality is known on first computation
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range nodes.Items {
+		et.OnAdd(&nodes.Items[i])
+	}
+	eh.WithField("count", len(nodes.Items)).Info("nodes")
+
 	// Endpoints
 	endpoints, err := client.CoreV1().Endpoints("").List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
@@ -114,6 +142,311 @@ func initCache(clients *k8s.Clients, eh *contour.EventHandler, et *contour.Endpo
 	return nil
 }
 
-func defaultCertificate() string {
-	return os.Getenv("DEFAULT_CERTIFICATE")
+// defaultCertificate selects the certificate served by this Contour to the
+// clients without SNI among the default-certificates. The most specific of
+// the certificates whose scope matches the ingress class and root namespaces
+// of this Contour wins, a tie being an error as a listener only holds a
+// single filter chain for the clients without SNI.
+func (ctx *serveContext) defaultCertificate() (*DefaultCertificate, error) {
+	scopes := make(map[[2]string]DefaultCertificate)
+	var selected []DefaultCertificate
+	best := -1
+	for _, dc := range ctx.TLSConfig.DefaultCertificates {
+		dc.Name, dc.Namespace = strings.TrimSpace(dc.Name), strings.TrimSpace(dc.Namespace)
+		switch {
+		case dc.Namespace == "":
+			return nil, errors.New("namespace must be defined")
+		case dc.Name == "":
+			return nil, errors.New("name must be defined")
+		}
+
+		scope := [2]string{dc.IngressClass, dc.RootNamespace}
+		if other, ok := scopes[scope]; ok {
+			return nil, fmt.Errorf("default certificates %q and %q have the same scope", other, dc)
+		}
+		scopes[scope] = dc
+
+		specificity, ok := ctx.matchDefaultCertificate(dc)
+		switch {
+		case !ok || specificity < best:
+			continue
+		case specificity > best:
+			best = specificity
+			selected = selected[:0]
+		}
+		selected = append(selected, dc)
+	}
+
+	switch len(selected) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &selected[0], nil
+	default:
+		return nil, fmt.Errorf("default certificates %q and %q both apply", selected[0], selected[1])
+	}
+}
+
+// noSNICertificates returns the certificates served to the clients without
+// SNI: the fallback certificate of the virtual hosts enabling it, from the
+// fallback-certificate, and the certificate of all the other virtual hosts,
+// from the DEFAULT_CERTIFICATE environment variable. The default certificate
+// dc, selected among the default-certificates, replaces either of them
+// depending on its all-virtual-hosts field.
+func (ctx *serveContext) noSNICertificates(dc *DefaultCertificate) (fallback, allVirtualHosts *DefaultCertificate, err error) {
+	fallbackCert, err := ctx.fallbackCertificate()
+	if err != nil {
+		return nil, nil, err
+	}
+	if fallbackCert != nil {
+		fallback = &DefaultCertificate{
+			Name:      fallbackCert.Name,
+			Namespace: fallbackCert.Namespace,
+		}
+	}
+
+	if env := os.Getenv("DEFAULT_CERTIFICATE"); env != "" {
+		parts := strings.Split(env, "/")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, nil, fmt.Errorf("DEFAULT_CERTIFICATE %q is not of the form namespace/name", env)
+		}
+		allVirtualHosts = &DefaultCertificate{
+			Name:            parts[1],
+			Namespace:       parts[0],
+			AllVirtualHosts: true,
+		}
+	}
+
+	switch {
+	case dc == nil:
+	case dc.AllVirtualHosts:
+		allVirtualHosts = dc
+	default:
+		fallback = dc
+	}
+	return fallback, allVirtualHosts, nil
+}
+
+// matchDefaultCertificate returns whether the scope of the certificate
+// matches this Contour, and the number of scoping fields it defines.
+func (ctx *serveContext) matchDefaultCertificate(dc DefaultCertificate) (int, bool) {
+	specificity := 0
+	if dc.IngressClass != "" {
+		ingressClass := ctx.ingressClass
+		if ingressClass == "" {
+			ingressClass = annotation.DEFAULT_INGRESS_CLASS
+		}
+		if dc.IngressClass != ingressClass {
+			return 0, false
+		}
+		specificity++
+	}
+	if dc.RootNamespace != "" {
+		if !contains(ctx.ingressRouteRootNamespaces(), dc.RootNamespace) {
+			return 0, false
+		}
+		specificity++
+	}
+	return specificity, true
+}
+
+func (ctx *serveContext) upstreamClientCertificate() (*k8s.FullName, error) {
+	cc := ctx.TLSConfig.UpstreamClientCertificate
+	name, namespace := strings.TrimSpace(cc.Name), strings.TrimSpace(cc.Namespace)
+	switch {
+	case name == "" && namespace == "":
+		return nil, nil
+	case namespace == "":
+		return nil, errors.New("namespace must be defined")
+	case name == "":
+		return nil, errors.New("name must be defined")
+	}
+	return &k8s.FullName{
+		Name:      name,
+		Namespace: namespace,
+	}, nil
+}
+
+func (ctx *serveContext) upstreamTLS() (*projcontour.UpstreamTLS, error) {
+	u := ctx.TLSConfig.UpstreamTLS
+	if u.MinimumProtocolVersion == "" && u.MaximumProtocolVersion == "" && len(u.CipherSuites) == 0 {
+		return nil, nil
+	}
+	p := &projcontour.UpstreamTLS{
+		MinimumProtocolVersion: u.MinimumProtocolVersion,
+		MaximumProtocolVersion: u.MaximumProtocolVersion,
+		CipherSuites:           u.CipherSuites,
+	}
+	if err := dag.ValidUpstreamTLS(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (ctx *serveContext) downstreamTLSParams() error {
+	if err := dag.ValidCipherSuites(ctx.TLSConfig.CipherSuites); err != nil {
+		return err
+	}
+	return dag.ValidECDHCurves(ctx.TLSConfig.ECDHCurves)
+}
+
+// allVirtualHosts returns the namespace/name of the certificate when it is
+// served to all the clients without SNI, and an empty string otherwise.
+func (dc *DefaultCertificate) allVirtualHosts() string {
+	if dc == nil || !dc.AllVirtualHosts {
+		return ""
+	}
+	return dc.String()
+}
+
+// checkDefaultCertificate returns an error when the Secret of the default
+// certificate selected among the default-certificates does not exist, as
+// the clients without SNI would otherwise be silently refused. The Secret
+// is listed, as Contour is only allowed to list and watch Secrets.
+func checkDefaultCertificate(client kubernetes.Interface, dc *DefaultCertificate) error {
+	if dc == nil {
+		return nil
+	}
+	secrets, err := client.CoreV1().Secrets(dc.Namespace).List(context.TODO(), metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", dc.Name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets.Items {
+		if secret.Name == dc.Name {
+			return nil
+		}
+	}
+	return fmt.Errorf("secret %q not found", dc)
+}
+
+// additionalListeners returns the additional listeners, ensuring their names
+// and ports don't collide with each other or with the other listeners.
+func (ctx *serveContext) additionalListeners() ([]dag.AdditionalListener, error) {
+	var listeners []dag.AdditionalListener
+	for _, l := range ctx.Listeners {
+		switch l.Name {
+		case contour.ENVOY_HTTP_LISTENER, contour.ENVOY_HTTPS_LISTENER, contour.ENVOY_FALLBACK_ROUTECONFIG:
+			return nil, fmt.Errorf("listener %q: name is reserved", l.Name)
+		}
+		address := l.Address
+		if address == "" {
+			address = "0.0.0.0"
+		}
+		listeners = append(listeners, dag.AdditionalListener{
+			Name:     l.Name,
+			Address:  address,
+			Port:     l.Port,
+			Protocol: l.Protocol,
+		})
+	}
+	if err := dag.ValidListeners(listeners, ctx.httpPort, ctx.httpsPort, ctx.statsPort); err != nil {
+		return nil, err
+	}
+	return listeners, nil
+}
+
+// accessLogSink returns the gRPC access log service the access logs are
+// sent to, or nil if they are written to the access log files.
+func (ctx *serveContext) accessLogSink() (*contour.AccessLogSink, error) {
+	cfg := ctx.AccessLogSink
+	if cfg.Address == "" && !cfg.Builtin {
+		if cfg.LogName != "" {
+			return nil, errors.New("log-name requires an address or builtin")
+		}
+		return nil, nil
+	}
+	if cfg.Address != "" && cfg.Builtin {
+		return nil, errors.New("address and builtin are mutually exclusive")
+	}
+	sink := &contour.AccessLogSink{
+		LogName: cfg.LogName,
+	}
+	if sink.LogName == "" {
+		sink.LogName = "contour"
+	}
+	if cfg.Builtin {
+		return sink, nil
+	}
+	host, port, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("address %q: %v", cfg.Address, err)
+	}
+	sink.Port, err = strconv.Atoi(port)
+	if err != nil || sink.Port < 1 || sink.Port > 65535 {
+		return nil, fmt.Errorf("address %q: invalid port %q", cfg.Address, port)
+	}
+	if host == "" {
+		return nil, fmt.Errorf("address %q: missing host", cfg.Address)
+	}
+	sink.Address = host
+	return sink, nil
+}
+
+// accessLogFilter returns the filter of the requests logged, or nil if they
+// are all logged.
+func (ctx *serveContext) accessLogFilter() (*dag.AccessLogFilter, error) {
+	f := ctx.AccessLogFilter
+	return dag.NewAccessLogFilter(f.StatusCodes, f.MinDuration, f.SamplePercent, f.Headers)
+}
+
+// accessLogFields ensures the JSON fields of the access log are either known
+// fields or valid custom fields, with distinct names. The fields which are
+// neither known nor custom are returned rather than refused, as the access
+// log has always ignored them.
+func (ctx *serveContext) accessLogFields() (unknown []string, err error) {
+	var fields []string
+	for _, f := range ctx.AccessLogFields {
+		if _, ok := envoy.JSONFields[f]; !ok && !strings.Contains(f, "=") {
+			unknown = append(unknown, f)
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return unknown, validAccessLogFields(fields)
+}
+
+// accessLogPaths ensures the files and directories the virtual hosts may
+// write their access log to are absolute paths.
+func (ctx *serveContext) accessLogPaths() error {
+	for _, p := range ctx.AccessLogPaths {
+		if !path.IsAbs(p) {
+			return fmt.Errorf("path %q must be absolute", p)
+		}
+		if dir := strings.TrimSuffix(p, "/"); dir != "" && path.Clean(dir) != dir {
+			return fmt.Errorf("path %q must be clean", p)
+		}
+	}
+	return nil
+}
+
+// validAccessLogFields ensures the JSON fields are either known fields or
+// valid custom fields, with distinct names.
+func validAccessLogFields(fields []string) error {
+	names := make(map[string]bool)
+	for _, f := range fields {
+		name, _, err := envoy.ParseAccessLogField(f)
+		if err != nil {
+			return err
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate field %q", name)
+		}
+		names[name] = true
+	}
+	return nil
+}
+
+// registerAccessLogReceiver registers the builtin access log service on the
+// xDS server if the access logs are sent to Contour.
+func (ctx *serveContext) registerAccessLogReceiver(s *grpc.Server, log logrus.FieldLogger) {
+	if !ctx.AccessLogSink.Builtin {
+		return
+	}
+	fields := ctx.AccessLogFields
+	if fields == nil {
+		fields = envoy.DefaultFields
+	}
+	als.RegisterAccessLogServiceServer(s, cgrpc.NewAccessLogReceiver(fields, os.Stdout, log.WithField("context", "accesslog")))
 }
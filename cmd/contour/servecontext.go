@@ -25,6 +25,7 @@ import (
 	"strings"
 	"time"
 
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/k8s"
 
 	"github.com/projectcontour/contour/internal/contour"
@@ -97,8 +98,23 @@ type serveContext struct {
 
 	// AccessLogFields sets the fields that JSON logging will
 	// output when AccessLogFormat is json.
+	// Adobe - custom fields of the form name=%COMMAND(param)% log
+	// any format operator of Envoy.
 	AccessLogFields []string `yaml:"json-fields,omitempty"`
 
+	// Adobe - AccessLogSink sends the access logs to a gRPC access log
+	// service in place of the access log files.
+	AccessLogSink AccessLogSinkConfig `yaml:"accesslog-sink,omitempty"`
+
+	// Adobe - AccessLogFilter selects the requests logged, unless the
+	// virtual hosts override it.
+	AccessLogFilter AccessLogFilterConfig `yaml:"accesslog-filter,omitempty"`
+
+	// Adobe - AccessLogPaths are the files the IngressRoute virtual hosts
+	// may write their access log to, the paths ending with a slash
+	// allowing any file under the directory.
+	AccessLogPaths []string `yaml:"accesslog-paths,omitempty"`
+
 	// PermitInsecureGRPC disables TLS on Contour's gRPC listener.
 	PermitInsecureGRPC bool `yaml:"-"`
 
@@ -131,6 +147,13 @@ type serveContext struct {
 
 	// Name of the envoy service to inspect for Ingress status details.
 	EnvoyServiceName string `yaml:"envoy-service-name,omitempty"`
+
+	// Adobe - EndpointsConfig can be set in the config file.
+	EndpointsConfig `yaml:"endpoints,omitempty"`
+
+	// Adobe - Listeners defines the Envoy listeners, besides the HTTP and
+	// HTTPS ones, which the roots select by port.
+	Listeners []ListenerConfig `yaml:"listeners,omitempty"`
 }
 
 // newServeContext returns a serveContext initialized to defaults.
@@ -175,9 +198,76 @@ func newServeContext() *serveContext {
 type TLSConfig struct {
 	MinimumProtocolVersion string `yaml:"minimum-protocol-version"`
 
+	// Adobe - CipherSuites and ECDHCurves offered by the HTTPS listener.
+	// IngressRoutes may override them.
+	CipherSuites []string `yaml:"cipher-suites,omitempty"`
+	ECDHCurves   []string `yaml:"ecdh-curves,omitempty"`
+
 	// FallbackCertificate defines the namespace/name of the Kubernetes secret to
 	// use as fallback when a non-SNI request is received.
 	FallbackCertificate FallbackCertificate `yaml:"fallback-certificate,omitempty"`
+
+	// Adobe - DefaultCertificates defines the Kubernetes secrets served to
+	// the clients which don't send SNI, scoped per ingress class or root
+	// namespace.
+	DefaultCertificates []DefaultCertificate `yaml:"default-certificates,omitempty"`
+
+	// Adobe - UpstreamClientCertificate defines the namespace/name of the
+	// Kubernetes secret Envoy presents to the upstreams it speaks TLS to.
+	UpstreamClientCertificate FallbackCertificate `yaml:"upstream-client-certificate,omitempty"`
+
+	// Adobe - UpstreamTLS defines the TLS parameters of the connections
+	// to the upstreams Envoy speaks TLS to.
+	UpstreamTLS UpstreamTLSConfig `yaml:"upstream-tls,omitempty"`
+
+	// Adobe - CertificateExpiry defines how the expiry of the certificates
+	// of secure vhosts is handled.
+	CertificateExpiry CertificateExpiryConfig `yaml:"certificate-expiry,omitempty"`
+}
+
+// CertificateExpiryConfig defines how the expiry of the certificates of
+// secure vhosts is handled.
+type CertificateExpiryConfig struct {
+	// WarningPeriod is the period before the expiry of a certificate
+	// during which the status of the objects presenting it carries a
+	// warning. Zero disables the warning.
+	WarningPeriod time.Duration `yaml:"warning-period,omitempty"`
+
+	// RefuseExpired invalidates the objects presenting an expired
+	// certificate rather than serving it.
+	RefuseExpired bool `yaml:"refuse-expired,omitempty"`
+}
+
+// DefaultCertificate defines the namespace/name of the Kubernetes secret
+// served to the clients which don't send SNI, and the Contours it applies to.
+type DefaultCertificate struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace"`
+
+	// IngressClass restricts the certificate to the Contour serving
+	// this ingress class.
+	IngressClass string `yaml:"ingress-class,omitempty"`
+
+	// RootNamespace restricts the certificate to the Contour whose root
+	// namespaces include this namespace.
+	RootNamespace string `yaml:"root-namespace,omitempty"`
+
+	// AllVirtualHosts serves the certificate to all the clients without
+	// SNI rather than only to those of the virtual hosts enabling the
+	// fallback certificate.
+	AllVirtualHosts bool `yaml:"all-virtual-hosts,omitempty"`
+}
+
+func (dc DefaultCertificate) String() string {
+	return dc.Namespace + "/" + dc.Name
+}
+
+// UpstreamTLSConfig defines the TLS parameters of the connections to
+// upstreams. Services may override each of them.
+type UpstreamTLSConfig struct {
+	MinimumProtocolVersion string   `yaml:"minimum-protocol-version,omitempty"`
+	MaximumProtocolVersion string   `yaml:"maximum-protocol-version,omitempty"`
+	CipherSuites           []string `yaml:"cipher-suites,omitempty"`
 }
 
 // FallbackCertificate defines the namespace/name of the Kubernetes secret to
@@ -218,6 +308,67 @@ type LeaderElectionConfig struct {
 	Name          string        `yaml:"configmap-name,omitempty"`
 }
 
+// EndpointsConfig holds the configuration of the endpoints published over
+// EDS inside the configuration file.
+type EndpointsConfig struct {
+	// PublishNotReady publishes the not ready addresses as unhealthy.
+	PublishNotReady bool `yaml:"publish-not-ready,omitempty"`
+
+	// DrainingPeriod is the period during which the addresses removed
+	// from an Endpoints are published as draining.
+	DrainingPeriod time.Duration `yaml:"draining-period,omitempty"`
+}
+
+// ListenerConfig defines an additional Envoy listener inside the
+// configuration file.
+type ListenerConfig struct {
+	// Name of the Envoy listener and of its route configuration.
+	Name string `yaml:"name"`
+
+	// Address to listen on. Defaults to 0.0.0.0.
+	Address string `yaml:"address,omitempty"`
+
+	// Port to listen on, which the roots select.
+	Port int `yaml:"port"`
+
+	// Protocol is either http, serving plain HTTP, or https, terminating
+	// or passing TLS through per SNI like the HTTPS listener. TCP services
+	// are served by an https listener, as plain TCP is not supported.
+	Protocol string `yaml:"protocol"`
+}
+
+// AccessLogSinkConfig defines the gRPC access log service Envoy sends the
+// access logs to inside the configuration file.
+type AccessLogSinkConfig struct {
+	// Address of the access log service, as host:port.
+	Address string `yaml:"address,omitempty"`
+
+	// Builtin sends the access logs to Contour, which writes them to its
+	// standard output as JSON lines with the json-fields.
+	Builtin bool `yaml:"builtin,omitempty"`
+
+	// LogName identifies the log stream of Envoy. Defaults to contour.
+	LogName string `yaml:"log-name,omitempty"`
+}
+
+// AccessLogFilterConfig defines the requests logged inside the
+// configuration file, which must satisfy all the conditions set.
+type AccessLogFilterConfig struct {
+	// StatusCodes are the response codes logged, either single codes
+	// such as 503, or ranges such as 500-599.
+	StatusCodes []string `yaml:"status-codes,omitempty"`
+
+	// MinDuration is the duration a request lasts at least to be logged.
+	MinDuration time.Duration `yaml:"min-duration,omitempty"`
+
+	// SamplePercent is the percentage of the requests logged.
+	SamplePercent uint32 `yaml:"sample-percent,omitempty"`
+
+	// Headers are conditions on the request headers, with the name,
+	// present, contains, notcontains, exact and notexact keys.
+	Headers []projcontour.HeaderCondition `yaml:"headers,omitempty"`
+}
+
 // grpcOptions returns a slice of grpc.ServerOptions.
 // if ctx.PermitInsecureGRPC is false, the option set will
 // include TLS configuration.
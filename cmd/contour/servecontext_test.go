@@ -25,12 +25,21 @@ import (
 	"testing"
 	"time"
 
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/contour"
+	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/k8s"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/projectcontour/contour/internal/assert"
 	"google.golang.org/grpc"
 	"gopkg.in/yaml.v2"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestServeContextIngressRouteRootNamespaces(t *testing.T) {
@@ -187,6 +196,33 @@ leaderelection:
 				return ctx
 			},
 		},
+		"endpoints all fields set": {
+			yamlIn: `
+endpoints:
+  publish-not-ready: true
+  draining-period: 30s
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.EndpointsConfig.PublishNotReady = true
+				ctx.EndpointsConfig.DrainingPeriod = 30 * time.Second
+				return ctx
+			},
+		},
+		"certificate expiry all fields set": {
+			yamlIn: `
+tls:
+  certificate-expiry:
+    warning-period: 720h
+    refuse-expired: true
+`,
+			want: func() *serveContext {
+				ctx := newServeContext()
+				ctx.TLSConfig.CertificateExpiry.WarningPeriod = 720 * time.Hour
+				ctx.TLSConfig.CertificateExpiry.RefuseExpired = true
+				return ctx
+			},
+		},
 	}
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
@@ -268,6 +304,57 @@ func TestFallbackCertificateParams(t *testing.T) {
 	}
 }
 
+func TestUpstreamClientCertificateParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn      string
+		want        *k8s.FullName
+		expecterror bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+			want:   nil,
+		},
+		"client cert params passed correctly": {
+			yamlIn: `
+tls:
+  upstream-client-certificate:
+    name: envoy-client
+    namespace: projectcontour
+`,
+			want: &k8s.FullName{
+				Name:      "envoy-client",
+				Namespace: "projectcontour",
+			},
+		},
+		"missing namespace": {
+			yamlIn: `
+tls:
+  upstream-client-certificate:
+    name: envoy-client
+`,
+			want:        nil,
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			got, err := ctx.upstreamClientCertificate()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Errorf("Expected Upstream Client Certificate error: %s", err)
+			}
+		})
+	}
+}
+
 // Testdata for this test case can be re-generated by running:
 // make gencerts
 // cp certs/*.pem cmd/contour/testdata/X/
@@ -439,3 +526,769 @@ func peekError(conn net.Conn) error {
 	}
 	return nil
 }
+
+func TestUpstreamTLSParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn      string
+		want        *projcontour.UpstreamTLS
+		expecterror bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+			want:   nil,
+		},
+		"upstream tls params passed correctly": {
+			yamlIn: `
+tls:
+  upstream-tls:
+    minimum-protocol-version: "1.2"
+    cipher-suites:
+    - "[ECDHE-ECDSA-AES128-GCM-SHA256|ECDHE-ECDSA-CHACHA20-POLY1305]"
+    - ECDHE-RSA-AES256-GCM-SHA384
+`,
+			want: &projcontour.UpstreamTLS{
+				MinimumProtocolVersion: "1.2",
+				CipherSuites: []string{
+					"[ECDHE-ECDSA-AES128-GCM-SHA256|ECDHE-ECDSA-CHACHA20-POLY1305]",
+					"ECDHE-RSA-AES256-GCM-SHA384",
+				},
+			},
+		},
+		"invalid protocol version": {
+			yamlIn: `
+tls:
+  upstream-tls:
+    minimum-protocol-version: "1.1"
+`,
+			want:        nil,
+			expecterror: true,
+		},
+		"unsupported cipher suite": {
+			yamlIn: `
+tls:
+  upstream-tls:
+    cipher-suites:
+    - DES-CBC3-SHA
+`,
+			want:        nil,
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			got, err := ctx.upstreamTLS()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Errorf("Expected Upstream TLS error: %s", err)
+			}
+		})
+	}
+}
+
+func TestDownstreamTLSParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn      string
+		expecterror bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+		},
+		"cipher suites and curves passed correctly": {
+			yamlIn: `
+tls:
+  cipher-suites:
+  - "[ECDHE-ECDSA-AES128-GCM-SHA256|ECDHE-ECDSA-CHACHA20-POLY1305]"
+  - ECDHE-RSA-AES256-GCM-SHA384
+  ecdh-curves:
+  - X25519
+  - P-256
+`,
+		},
+		"unsupported ecdh curve": {
+			yamlIn: `
+tls:
+  ecdh-curves:
+  - P-192
+`,
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			err := ctx.downstreamTLSParams()
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Errorf("Expected TLS error: %s", err)
+			}
+		})
+	}
+}
+
+func TestDefaultCertificateParams(t *testing.T) {
+	const defaultCertificates = `
+tls:
+  default-certificates:
+  - name: default
+    namespace: projectcontour
+  - name: internal
+    namespace: projectcontour
+    ingress-class: internal
+  - name: tenant
+    namespace: tenant
+    root-namespace: tenant
+  - name: tenant-internal
+    namespace: tenant
+    ingress-class: internal
+    root-namespace: tenant
+`
+
+	tests := map[string]struct {
+		yamlIn             string
+		ingressClass       string
+		rootNamespaces     string
+		defaultCertificate string
+		want               *DefaultCertificate
+		expecterror        bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+		},
+		"unscoped certificate": {
+			yamlIn: defaultCertificates,
+			want:   &DefaultCertificate{Name: "default", Namespace: "projectcontour"},
+		},
+		"ingress class certificate": {
+			yamlIn:       defaultCertificates,
+			ingressClass: "internal",
+			want:         &DefaultCertificate{Name: "internal", Namespace: "projectcontour", IngressClass: "internal"},
+		},
+		"root namespace certificate": {
+			yamlIn:         defaultCertificates,
+			rootNamespaces: "tenant,other",
+			want:           &DefaultCertificate{Name: "tenant", Namespace: "tenant", RootNamespace: "tenant"},
+		},
+		"ingress class and root namespace certificate": {
+			yamlIn:         defaultCertificates,
+			ingressClass:   "internal",
+			rootNamespaces: "tenant",
+			want:           &DefaultCertificate{Name: "tenant-internal", Namespace: "tenant", IngressClass: "internal", RootNamespace: "tenant"},
+		},
+		"default ingress class certificate": {
+			yamlIn: `
+tls:
+  default-certificates:
+  - name: default
+    namespace: projectcontour
+    ingress-class: contour
+`,
+			want: &DefaultCertificate{Name: "default", Namespace: "projectcontour", IngressClass: "contour"},
+		},
+		"fallback certificate and DEFAULT_CERTIFICATE environment variable": {
+			yamlIn: `
+tls:
+  fallback-certificate:
+    name: fallback
+    namespace: projectcontour
+`,
+			defaultCertificate: "projectcontour/default",
+		},
+		"missing name": {
+			yamlIn: `
+tls:
+  default-certificates:
+  - namespace: projectcontour
+`,
+			expecterror: true,
+		},
+		"same scope": {
+			yamlIn: `
+tls:
+  default-certificates:
+  - name: a
+    namespace: projectcontour
+    ingress-class: internal
+  - name: b
+    namespace: projectcontour
+    ingress-class: internal
+`,
+			expecterror: true,
+		},
+		"ambiguous root namespaces": {
+			yamlIn: `
+tls:
+  default-certificates:
+  - name: a
+    namespace: a
+    root-namespace: a
+  - name: b
+    namespace: b
+    root-namespace: b
+`,
+			rootNamespaces: "a,b",
+			expecterror:    true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.defaultCertificate != "" {
+				os.Setenv("DEFAULT_CERTIFICATE", tc.defaultCertificate)
+				defer os.Unsetenv("DEFAULT_CERTIFICATE")
+			}
+			ctx := newServeContext()
+			ctx.ingressClass = tc.ingressClass
+			ctx.rootNamespaces = tc.rootNamespaces
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			got, err := ctx.defaultCertificate()
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Errorf("Expected Default Certificate error: %s", err)
+			}
+		})
+	}
+}
+
+func TestNoSNICertificatesParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn             string
+		defaultCertificate string
+		want               [2]*DefaultCertificate
+		expecterror        bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+		},
+		"fallback certificate": {
+			yamlIn: `
+tls:
+  fallback-certificate:
+    name: fallback
+    namespace: projectcontour
+`,
+			want: [2]*DefaultCertificate{{Name: "fallback", Namespace: "projectcontour"}, nil},
+		},
+		"DEFAULT_CERTIFICATE environment variable": {
+			defaultCertificate: "projectcontour/default",
+			want:               [2]*DefaultCertificate{nil, {Name: "default", Namespace: "projectcontour", AllVirtualHosts: true}},
+		},
+		"malformed DEFAULT_CERTIFICATE environment variable": {
+			defaultCertificate: "default",
+			expecterror:        true,
+		},
+		"fallback certificate and DEFAULT_CERTIFICATE environment variable": {
+			yamlIn: `
+tls:
+  fallback-certificate:
+    name: fallback
+    namespace: projectcontour
+`,
+			defaultCertificate: "projectcontour/default",
+			want: [2]*DefaultCertificate{
+				{Name: "fallback", Namespace: "projectcontour"},
+				{Name: "default", Namespace: "projectcontour", AllVirtualHosts: true},
+			},
+		},
+		"default certificate replacing the fallback certificate": {
+			yamlIn: `
+tls:
+  fallback-certificate:
+    name: fallback
+    namespace: projectcontour
+  default-certificates:
+  - name: tenant
+    namespace: tenant
+`,
+			defaultCertificate: "projectcontour/default",
+			want: [2]*DefaultCertificate{
+				{Name: "tenant", Namespace: "tenant"},
+				{Name: "default", Namespace: "projectcontour", AllVirtualHosts: true},
+			},
+		},
+		"default certificate replacing the DEFAULT_CERTIFICATE environment variable": {
+			yamlIn: `
+tls:
+  fallback-certificate:
+    name: fallback
+    namespace: projectcontour
+  default-certificates:
+  - name: tenant
+    namespace: tenant
+    all-virtual-hosts: true
+`,
+			defaultCertificate: "projectcontour/default",
+			want: [2]*DefaultCertificate{
+				{Name: "fallback", Namespace: "projectcontour"},
+				{Name: "tenant", Namespace: "tenant", AllVirtualHosts: true},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tc.defaultCertificate != "" {
+				os.Setenv("DEFAULT_CERTIFICATE", tc.defaultCertificate)
+				defer os.Unsetenv("DEFAULT_CERTIFICATE")
+			}
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			dc, err := ctx.defaultCertificate()
+			checkFatalErr(t, err)
+			var got [2]*DefaultCertificate
+			got[0], got[1], err = ctx.noSNICertificates(dc)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Errorf("Expected no SNI certificates error: %s", err)
+			}
+		})
+	}
+}
+
+func TestAdditionalListenersParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn      string
+		want        []dag.AdditionalListener
+		expecterror bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+		},
+		"http and https listeners": {
+			yamlIn: `
+listeners:
+- name: admin
+  port: 9080
+  protocol: http
+- name: mqtt
+  address: 127.0.0.1
+  port: 8883
+  protocol: https
+`,
+			want: []dag.AdditionalListener{
+				{Name: "admin", Address: "0.0.0.0", Port: 9080, Protocol: "http"},
+				{Name: "mqtt", Address: "127.0.0.1", Port: 8883, Protocol: "https"},
+			},
+		},
+		"reserved name": {
+			yamlIn: `
+listeners:
+- name: ingress_https
+  port: 9443
+  protocol: https
+`,
+			expecterror: true,
+		},
+		"duplicate name": {
+			yamlIn: `
+listeners:
+- name: admin
+  port: 9080
+  protocol: http
+- name: admin
+  port: 9081
+  protocol: http
+`,
+			expecterror: true,
+		},
+		"port of the https listener": {
+			yamlIn: `
+listeners:
+- name: mqtt
+  port: 8443
+  protocol: https
+`,
+			expecterror: true,
+		},
+		"port of another listener": {
+			yamlIn: `
+listeners:
+- name: admin
+  port: 9080
+  protocol: http
+- name: mqtt
+  port: 9080
+  protocol: https
+`,
+			expecterror: true,
+		},
+		"unknown protocol": {
+			yamlIn: `
+listeners:
+- name: postgres
+  port: 5432
+  protocol: tcp
+`,
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			got, err := ctx.additionalListeners()
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("Expected listeners error: %s", err)
+			}
+			if !tc.expecterror {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAccessLogSinkParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn      string
+		want        *contour.AccessLogSink
+		expecterror bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+		},
+		"access log service": {
+			yamlIn: `
+accesslog-sink:
+  address: als.logging:9001
+`,
+			want: &contour.AccessLogSink{Address: "als.logging", Port: 9001, LogName: "contour"},
+		},
+		"builtin access log service": {
+			yamlIn: `
+accesslog-sink:
+  builtin: true
+  log-name: edge
+`,
+			want: &contour.AccessLogSink{LogName: "edge"},
+		},
+		"address and builtin": {
+			yamlIn: `
+accesslog-sink:
+  address: als.logging:9001
+  builtin: true
+`,
+			expecterror: true,
+		},
+		"address without port": {
+			yamlIn: `
+accesslog-sink:
+  address: als.logging
+`,
+			expecterror: true,
+		},
+		"invalid port": {
+			yamlIn: `
+accesslog-sink:
+  address: als.logging:grpc
+`,
+			expecterror: true,
+		},
+		"log name only": {
+			yamlIn: `
+accesslog-sink:
+  log-name: edge
+`,
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			got, err := ctx.accessLogSink()
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("Expected access log sink error: %s", err)
+			}
+			if !tc.expecterror {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAccessLogFilterParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn      string
+		want        *dag.AccessLogFilter
+		expecterror bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+		},
+		"all conditions": {
+			yamlIn: `
+accesslog-filter:
+  status-codes:
+  - "302"
+  - 400-599
+  min-duration: 500ms
+  sample-percent: 10
+  headers:
+  - name: user-agent
+    notcontains: kube-probe
+  - name: x-debug
+    present: true
+`,
+			want: &dag.AccessLogFilter{
+				StatusCodes:   []dag.StatusCodeRange{{Min: 302, Max: 302}, {Min: 400, Max: 599}},
+				MinDuration:   500 * time.Millisecond,
+				SamplePercent: 10,
+				HeaderConditions: []dag.HeaderCondition{
+					{Name: "user-agent", Value: "kube-probe", MatchType: "contains", Invert: true},
+					{Name: "x-debug", MatchType: "present"},
+				},
+			},
+		},
+		"invalid status code": {
+			yamlIn: `
+accesslog-filter:
+  status-codes:
+  - 2xx
+`,
+			expecterror: true,
+		},
+		"empty status code range": {
+			yamlIn: `
+accesslog-filter:
+  status-codes:
+  - 599-500
+`,
+			expecterror: true,
+		},
+		"sample percent over 100": {
+			yamlIn: `
+accesslog-filter:
+  sample-percent: 200
+`,
+			expecterror: true,
+		},
+		"header condition without match": {
+			yamlIn: `
+accesslog-filter:
+  headers:
+  - name: user-agent
+`,
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			got, err := ctx.accessLogFilter()
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("Expected access log filter error: %s", err)
+			}
+			if !tc.expecterror {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAccessLogFieldsParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn      string
+		expecterror bool
+		unknown     []string
+	}{
+		"not configured": {
+			yamlIn: ``,
+		},
+		"known and custom fields": {
+			yamlIn: `
+json-fields:
+  - "@timestamp"
+  - "request_id"
+  - "tenant_id=%REQ(X-Adobe-Tenant)%"
+  - "trace_id=%REQ(X-B3-TraceId?X-Trace-Id)%"
+  - "route=%ROUTE_NAME%"
+  - "authz=%DYNAMIC_METADATA(envoy.filters.http.ext_authz:user)%"
+`,
+		},
+		"unknown field": {
+			yamlIn: `
+json-fields:
+  - "@timestamp"
+  - "tenant_id"
+`,
+			unknown: []string{"tenant_id"},
+		},
+		"unnamed custom field": {
+			yamlIn: `
+json-fields:
+  - "=%REQ(X-Adobe-Tenant)%"
+`,
+			expecterror: true,
+		},
+		"unknown command": {
+			yamlIn: `
+json-fields:
+  - "tenant_id=%HEADER(X-Adobe-Tenant)%"
+`,
+			expecterror: true,
+		},
+		"missing header": {
+			yamlIn: `
+json-fields:
+  - "tenant_id=%REQ%"
+`,
+			expecterror: true,
+		},
+		"unexpected parameter": {
+			yamlIn: `
+json-fields:
+  - "duration=%DURATION(ms)%"
+`,
+			expecterror: true,
+		},
+		"not an operator": {
+			yamlIn: `
+json-fields:
+  - "tenant_id=X-Adobe-Tenant"
+`,
+			expecterror: true,
+		},
+		"duplicate field": {
+			yamlIn: `
+json-fields:
+  - "request_id"
+  - "request_id=%REQ(X-Correlation-Id)%"
+`,
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			unknown, err := ctx.accessLogFields()
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("Expected access log fields error: %s", err)
+			}
+			if !reflect.DeepEqual(unknown, tc.unknown) {
+				t.Fatalf("Expected unknown access log fields %q, got %q", tc.unknown, unknown)
+			}
+		})
+	}
+}
+
+func TestAccessLogPathsParams(t *testing.T) {
+	tests := map[string]struct {
+		yamlIn      string
+		expecterror bool
+	}{
+		"not configured": {
+			yamlIn: ``,
+		},
+		"files and directories": {
+			yamlIn: `
+accesslog-paths:
+  - /var/log/envoy/tenant.log
+  - /var/log/tenants/
+`,
+		},
+		"relative path": {
+			yamlIn: `
+accesslog-paths:
+  - var/log/envoy/
+`,
+			expecterror: true,
+		},
+		"unclean path": {
+			yamlIn: `
+accesslog-paths:
+  - /var/log/envoy/../tenants/
+`,
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := newServeContext()
+			checkFatalErr(t, yaml.Unmarshal([]byte(tc.yamlIn), ctx))
+			err := ctx.accessLogPaths()
+
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Fatalf("Expected access log paths error: %s", err)
+			}
+		})
+	}
+}
+
+func TestCheckDefaultCertificate(t *testing.T) {
+	client := fake.NewSimpleClientset(&v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "defaultcert",
+			Namespace: "default",
+		},
+	})
+	// Contour is only allowed to list and watch Secrets
+	client.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(v1.Resource("secrets"), "", nil)
+	})
+
+	tests := map[string]struct {
+		dc          *DefaultCertificate
+		expecterror bool
+	}{
+		"not configured": {
+			dc: nil,
+		},
+		"existing secret": {
+			dc: &DefaultCertificate{Name: "defaultcert", Namespace: "default"},
+		},
+		"missing secret": {
+			dc:          &DefaultCertificate{Name: "missing", Namespace: "default"},
+			expecterror: true,
+		},
+		"secret in another namespace": {
+			dc:          &DefaultCertificate{Name: "defaultcert", Namespace: "other"},
+			expecterror: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkDefaultCertificate(client, tc.dc)
+			goterror := err != nil
+			if goterror != tc.expecterror {
+				t.Errorf("Expected Default Certificate error: %v, got: %v", tc.expecterror, err)
+			}
+		})
+	}
+}
@@ -2,9 +2,11 @@ package annotation
 
 import (
 	"strings"
+	"time"
 
 	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // MaxProtoVersion - similar to MinProtoVersion, but for a max
@@ -21,9 +23,34 @@ func MaxProtoVersion(version string) envoy_api_v2_auth.TlsParameters_TlsProtocol
 }
 
 // ExtraVHosts returns extra VHosts from the IngressRoute annotation
+// Deprecated: superseded by the aliases of the virtual host.
 func ExtraVHosts(ir *ingressroutev1.IngressRoute) (vhosts []string) {
 	if annot := ir.Annotations["adobeplatform.adobe.io/hosts"]; annot != "" {
 		vhosts = strings.Split(annot, ",")
 	}
 	return
 }
+
+// LocalityLbPolicy returns the locality load balancing policy requested by the
+// adobeplatform.adobe.io/locality-lb-policy annotation on a Service.
+// Valid values are "ZoneAware" and "LocalityWeighted"; anything else yields "".
+func LocalityLbPolicy(o metav1.ObjectMetaAccessor) string {
+	switch policy := o.GetObjectMeta().GetAnnotations()["adobeplatform.adobe.io/locality-lb-policy"]; policy {
+	case "ZoneAware", "LocalityWeighted":
+		return policy
+	default:
+		return ""
+	}
+}
+
+// SlowStartWindow returns the duration of the adobeplatform.adobe.io/slow-start-window
+// annotation on a Service, during which the traffic sent to a newly ready endpoint
+// is progressively increased.
+// '0' is returned if the annotation is absent, unparseable or negative.
+func SlowStartWindow(o metav1.ObjectMetaAccessor) time.Duration {
+	d, err := time.ParseDuration(o.GetObjectMeta().GetAnnotations()["adobeplatform.adobe.io/slow-start-window"])
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
@@ -0,0 +1,159 @@
+package contour
+
+import (
+	"sort"
+	"strings"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_accesslog "github.com/envoyproxy/go-control-plane/envoy/config/filter/accesslog/v2"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/envoy"
+)
+
+// xdsCluster is the bootstrap cluster of Contour itself, used to send the
+// access logs to the builtin access log service.
+const xdsCluster = "contour"
+
+// AccessLogSink configures the gRPC access log service Envoy sends the
+// access logs to, in place of the access log files.
+type AccessLogSink struct {
+	// Address and Port of the access log service.
+	// If Address is not set, the access logs are sent to the builtin
+	// access log service of Contour.
+	Address string
+	Port    int
+
+	// LogName identifies the log stream of Envoy to the service.
+	LogName string
+}
+
+// cluster returns the name of the cluster of the access log service.
+func (s *AccessLogSink) cluster() string {
+	if s.Address == "" {
+		return xdsCluster
+	}
+	return envoy.AccessLogServiceCluster
+}
+
+// newAccessLog returns the access log written to path, unless accessLog
+// overrides it, selecting the requests logged with filter.
+func (lvc *ListenerVisitorConfig) newAccessLog(path string, accessLog *dag.AccessLog, filter *envoy_api_v2_accesslog.AccessLogFilter) []*envoy_api_v2_accesslog.AccessLog {
+	format, fields := lvc.accesslogType(), lvc.accesslogFields()
+	var headers []string
+	if al := accessLog; al != nil {
+		if al.Path != "" {
+			path = al.Path
+		}
+		if al.Format != "" {
+			format = al.Format
+		}
+		if al.Fields != nil {
+			fields = al.Fields
+		}
+		headers = al.Headers
+	}
+
+	if s := lvc.AccessLogSink; s != nil {
+		return envoy.FilterAccessLog(envoy.GRPCAccessLog(s.cluster(), s.LogName, fields, headers), filter)
+	}
+	switch format {
+	case "json":
+		return envoy.FilterAccessLog(envoy.FileAccessLogJSONHeaders(path, fields, headers), filter)
+	default:
+		return envoy.FilterAccessLog(envoy.FileAccessLogEnvoyHeaders(path, headers), filter)
+	}
+}
+
+// newTCPAccessLog returns the access log of the TCP proxies, which log
+// as the HTTPS listener does unless sent to an access log service.
+func (lvc *ListenerVisitorConfig) newTCPAccessLog(accessLog *dag.AccessLog) []*envoy_api_v2_accesslog.AccessLog {
+	if s := lvc.AccessLogSink; s != nil {
+		return envoy.TCPGRPCAccessLog(s.cluster(), s.LogName)
+	}
+	return lvc.newSecureAccessLog(accessLog, nil)
+}
+
+// accessLogClusters returns the cluster of the access log service, if it
+// is not the builtin one.
+func (lvc *ListenerVisitorConfig) accessLogClusters() map[string]*v2.Cluster {
+	s := lvc.AccessLogSink
+	if s == nil || s.Address == "" {
+		return nil
+	}
+	return map[string]*v2.Cluster{
+		envoy.AccessLogServiceCluster: envoy.GRPCAccessLogCluster(s.Address, s.Port),
+	}
+}
+
+// accessLogFilter returns the filter of the requests logged for the virtual
+// host, either its own or the one configured globally.
+func (lvc *ListenerVisitorConfig) accessLogFilter(vh *dag.VirtualHost) *envoy_api_v2_accesslog.AccessLogFilter {
+	if vh != nil && vh.AccessLog != nil && vh.AccessLog.Filter != nil {
+		return envoy.AccessLogFilter(vh.AccessLog.Filter, envoy.AccessLogVirtualHostScope)
+	}
+	return envoy.AccessLogFilter(lvc.AccessLogFilter, envoy.AccessLogListenerScope)
+}
+
+// newInsecureVirtualHostsAccessLog returns the access log of an insecure
+// listener, which all the virtual hosts share. The requests for the virtual
+// hosts overriding the access log are told apart by their authority, the
+// hosts of the other virtual hosts being excluded from a wildcard.
+func (lvc *ListenerVisitorConfig) newInsecureVirtualHostsAccessLog(virtualhosts []*dag.VirtualHost) []*envoy_api_v2_accesslog.AccessLog {
+	// sort the virtual hosts to ensure that the LDS entries are identical.
+	sort.Slice(virtualhosts, func(i, j int) bool {
+		return virtualhosts[i].Name < virtualhosts[j].Name
+	})
+
+	var hosts []string
+	var others []*envoy_api_v2_accesslog.AccessLogFilter
+	var overridden []*envoy_api_v2_accesslog.AccessLog
+	for _, vh := range virtualhosts {
+		// the catch-all host logs the requests for no other host, as
+		// the global access log does.
+		if vh.AccessLog == nil || vh.Name == "*" {
+			continue
+		}
+		names := append([]string{vh.Name}, vh.HostNames...)
+		excluded := wildcardExclusions(vh, names, virtualhosts)
+		overridden = append(overridden, lvc.newInsecureAccessLog(vh.AccessLog, envoy.AndAccessLogFilter(
+			envoy.AuthorityAccessLogFilter(names, false),
+			envoy.AuthorityAccessLogFilter(excluded, true),
+			lvc.accessLogFilter(vh),
+		))...)
+		if len(excluded) == 0 {
+			hosts = append(hosts, names...)
+			continue
+		}
+		// the global access log logs the requests for the excluded hosts.
+		others = append(others, envoy.OrAccessLogFilter(
+			envoy.AuthorityAccessLogFilter(names, true),
+			envoy.AuthorityAccessLogFilter(excluded, false),
+		))
+	}
+
+	filters := append([]*envoy_api_v2_accesslog.AccessLogFilter{envoy.AuthorityAccessLogFilter(hosts, true)}, others...)
+	return append(lvc.newInsecureAccessLog(nil, envoy.AndAccessLogFilter(
+		append(filters, lvc.accessLogFilter(nil))...,
+	)), overridden...)
+}
+
+// wildcardExclusions returns the hosts of the other virtual hosts matched by
+// the wildcards among names, the hosts of vh, which Envoy routes to those
+// other virtual hosts rather than to vh.
+func wildcardExclusions(vh *dag.VirtualHost, names []string, virtualhosts []*dag.VirtualHost) []string {
+	var excluded []string
+	for _, other := range virtualhosts {
+		if other == vh || other.Name == "*" {
+			continue
+		}
+		for _, host := range append([]string{other.Name}, other.HostNames...) {
+			for _, name := range names {
+				if strings.HasPrefix(name, "*.") && host != name && strings.HasSuffix(host, name[1:]) {
+					excluded = append(excluded, host)
+					break
+				}
+			}
+		}
+	}
+	return excluded
+}
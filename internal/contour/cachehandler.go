@@ -67,5 +67,9 @@ func (ch *CacheHandler) updateRoutes(root dag.Visitable) {
 
 func (ch *CacheHandler) updateClusters(root dag.Visitable) {
 	clusters := visitClusters(root)
+	// Adobe - cluster of the access log service
+	for name, c := range ch.ListenerVisitorConfig.accessLogClusters() {
+		clusters[name] = c
+	}
 	ch.ClusterCache.Update(clusters)
 }
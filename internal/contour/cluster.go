@@ -95,6 +95,10 @@ func (v *clusterVisitor) visit(vertex dag.Vertex) {
 			c := envoy.Cluster(cluster)
 			v.clusters[c.Name] = c
 		}
+		if len(cluster.Failover) > 0 {
+			c := envoy.AggregateCluster(cluster)
+			v.clusters[c.Name] = c
+		}
 	}
 
 	// recurse into children of v
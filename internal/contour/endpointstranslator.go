@@ -18,12 +18,12 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
-	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
 	resource "github.com/envoyproxy/go-control-plane/pkg/resource/v2"
 	"github.com/golang/protobuf/proto"
-	"github.com/projectcontour/contour/internal/envoy"
+	"github.com/projectcontour/contour/internal/k8s"
 	"github.com/projectcontour/contour/internal/protobuf"
 	"github.com/projectcontour/contour/internal/sorter"
 	"github.com/sirupsen/logrus"
@@ -37,12 +37,36 @@ import (
 type EndpointsTranslator struct {
 	logrus.FieldLogger
 	clusterLoadAssignmentCache
+
+	// PublishNotReadyAddresses publishes the not ready addresses
+	// of the Endpoints as unhealthy instead of omitting them.
+	PublishNotReadyAddresses bool
+
+	// DrainingPeriod, if positive, is the period during which the
+	// ready addresses removed from the Endpoints are published as
+	// draining.
+	DrainingPeriod time.Duration
+
+	// mu serialises the computation of ClusterLoadAssignments and guards
+	// the state below, see endpointstranslator_adobe.go
+	mu        sync.Mutex
+	endpoints map[k8s.FullName]*v1.Endpoints
+	topology  nodeTopology
+	slowStart slowStart
+	draining  map[k8s.FullName]map[string]drainingAddress
+
+	// now returns the current time, overridden in tests.
+	now func() time.Time
 }
 
 func (e *EndpointsTranslator) OnAdd(obj interface{}) {
 	switch obj := obj.(type) {
 	case *v1.Endpoints:
 		e.addEndpoints(obj)
+	case *v1.Node:
+		e.updateNode(obj)
+	case *v1.Service:
+		e.updateService(obj)
 	default:
 		e.Errorf("OnAdd unexpected type %T: %#v", obj, obj)
 	}
@@ -57,6 +81,10 @@ func (e *EndpointsTranslator) OnUpdate(oldObj, newObj interface{}) {
 			return
 		}
 		e.updateEndpoints(oldObj, newObj)
+	case *v1.Node:
+		e.updateNode(newObj)
+	case *v1.Service:
+		e.updateService(newObj)
 	default:
 		e.Errorf("OnUpdate unexpected type %T: %#v", newObj, newObj)
 	}
@@ -66,6 +94,10 @@ func (e *EndpointsTranslator) OnDelete(obj interface{}) {
 	switch obj := obj.(type) {
 	case *v1.Endpoints:
 		e.removeEndpoints(obj)
+	case *v1.Node:
+		e.removeNode(obj)
+	case *v1.Service:
+		e.removeService(obj)
 	case k8scache.DeletedFinalStateUnknown:
 		e.OnDelete(obj.Obj) // recurse into ourselves with the tombstoned value
 	default:
@@ -124,6 +156,15 @@ func (e *EndpointsTranslator) recomputeClusterLoadAssignment(oldep, newep *v1.En
 		return
 	}
 
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.track(oldep, newep)
+	e.computeClusterLoadAssignment(oldep, newep)
+}
+
+// computeClusterLoadAssignment updates the EDS cache from the old and new endpoints.
+// The caller must hold e.mu.
+func (e *EndpointsTranslator) computeClusterLoadAssignment(oldep, newep *v1.Endpoints) {
 	if oldep == nil {
 		oldep = &v1.Endpoints{
 			ObjectMeta: newep.ObjectMeta,
@@ -139,8 +180,8 @@ func (e *EndpointsTranslator) recomputeClusterLoadAssignment(oldep, newep *v1.En
 	seen := make(map[string]bool)
 	// add or update endpoints
 	for _, s := range newep.Subsets {
-		if len(s.Addresses) < 1 {
-			// skip subset without ready addresses.
+		if !e.hasAddresses(s) {
+			// skip subset without addresses to publish.
 			continue
 		}
 		for _, p := range s.Ports {
@@ -150,43 +191,37 @@ func (e *EndpointsTranslator) recomputeClusterLoadAssignment(oldep, newep *v1.En
 			}
 
 			addresses := append([]v1.EndpointAddress{}, s.Addresses...) // shallow copy
-			sort.SliceStable(addresses, func(a, b int) bool {
-				aIP := net.ParseIP(addresses[a].IP).To4()
-				bIP := net.ParseIP(addresses[b].IP).To4()
-				if len(aIP) != 4 || len(bIP) != 4 {
-					return false
-				}
-
-				// reverse the octets so IPs of the same subnet are not collocated
-				//
-				// this is important so host retries don't land on the same node when
-				// lb_policy is ROUND_ROBIN
-				aInt := uint32(aIP[0]) | uint32(aIP[1])<<8 | uint32(aIP[2])<<16 | uint32(aIP[3])<<24
-				bInt := uint32(bIP[0]) | uint32(bIP[1])<<8 | uint32(bIP[2])<<16 | uint32(bIP[3])<<24
-
-				return aInt < bInt
-			})
-
-			lbendpoints := make([]*envoy_api_v2_endpoint.LbEndpoint, 0, len(addresses))
-			for _, a := range addresses {
-				addr := envoy.SocketAddress(a.IP, int(p.Port))
-				lbendpoints = append(lbendpoints, envoy.LBEndpoint(addr))
-			}
+			sortAddresses(addresses)
 
 			cla := &v2.ClusterLoadAssignment{
 				ClusterName: servicename(newep.ObjectMeta, p.Name),
-				Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{{
-					LbEndpoints: lbendpoints,
-				}},
+				Endpoints: e.localityLbEndpoints(newep.ObjectMeta,
+					e.lbAddresses(newep.ObjectMeta, addresses, s.NotReadyAddresses, p), int(p.Port)),
 			}
 			seen[cla.ClusterName] = true
 			e.Add(cla)
 		}
 	}
 
+	// Adobe - publish the ports left with draining addresses only, such
+	// as those of deleted Endpoints.
+	for _, p := range e.drainingPorts(newep.ObjectMeta) {
+		name := servicename(newep.ObjectMeta, p.Name)
+		if seen[name] {
+			continue
+		}
+		cla := &v2.ClusterLoadAssignment{
+			ClusterName: name,
+			Endpoints: e.localityLbEndpoints(newep.ObjectMeta,
+				e.lbAddresses(newep.ObjectMeta, nil, nil, p), int(p.Port)),
+		}
+		seen[cla.ClusterName] = true
+		e.Add(cla)
+	}
+
 	// iterate over the ports in the old spec, remove any were not seen.
 	for _, s := range oldep.Subsets {
-		if len(s.Addresses) == 0 {
+		if !e.hasAddresses(s) {
 			continue
 		}
 		for _, p := range s.Ports {
@@ -200,6 +235,26 @@ func (e *EndpointsTranslator) recomputeClusterLoadAssignment(oldep, newep *v1.En
 
 }
 
+// sortAddresses sorts the addresses by their reversed IPv4 octets.
+func sortAddresses(addresses []v1.EndpointAddress) {
+	sort.SliceStable(addresses, func(a, b int) bool {
+		aIP := net.ParseIP(addresses[a].IP).To4()
+		bIP := net.ParseIP(addresses[b].IP).To4()
+		if len(aIP) != 4 || len(bIP) != 4 {
+			return false
+		}
+
+		// reverse the octets so IPs of the same subnet are not collocated
+		//
+		// this is important so host retries don't land on the same node when
+		// lb_policy is ROUND_ROBIN
+		aInt := uint32(aIP[0]) | uint32(aIP[1])<<8 | uint32(aIP[2])<<16 | uint32(aIP[3])<<24
+		bInt := uint32(bIP[0]) | uint32(bIP[1])<<8 | uint32(bIP[2])<<16 | uint32(bIP[3])<<24
+
+		return aInt < bInt
+	})
+}
+
 type clusterLoadAssignmentCache struct {
 	mu      sync.Mutex
 	entries map[string]*v2.ClusterLoadAssignment
@@ -0,0 +1,527 @@
+package contour
+
+import (
+	"sort"
+	"time"
+
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
+	"github.com/golang/protobuf/proto"
+	"github.com/projectcontour/contour/internal/annotation"
+	"github.com/projectcontour/contour/internal/envoy"
+	"github.com/projectcontour/contour/internal/k8s"
+	"github.com/projectcontour/contour/internal/protobuf"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Well known Node labels carrying the topology of the Node, the beta labels
+// are used as a fallback for clusters older than 1.17.
+const (
+	labelTopologyRegion      = "topology.kubernetes.io/region"
+	labelTopologyZone        = "topology.kubernetes.io/zone"
+	labelFailureDomainRegion = "failure-domain.beta.kubernetes.io/region"
+	labelFailureDomainZone   = "failure-domain.beta.kubernetes.io/zone"
+)
+
+const (
+	// slowStartMaxWeight is the load balancing weight of an endpoint
+	// which has completed its slow start window.
+	slowStartMaxWeight = 100
+
+	// slowStartInterval is the interval at which the weights of the
+	// endpoints in their slow start window are raised.
+	slowStartInterval = 5 * time.Second
+)
+
+// track records newep as the current version of the Endpoints, or forgets
+// oldep if newep is nil.
+func (e *EndpointsTranslator) track(oldep, newep *v1.Endpoints) {
+	if e.endpoints == nil {
+		e.endpoints = make(map[k8s.FullName]*v1.Endpoints)
+	}
+	if newep == nil {
+		name := k8s.FullName{Name: oldep.Name, Namespace: oldep.Namespace}
+		delete(e.endpoints, name)
+		e.slowStart.forget(name)
+		// the ready addresses of deleted Endpoints drain as if
+		// they were removed from them.
+		e.observeDraining(oldep, &v1.Endpoints{ObjectMeta: oldep.ObjectMeta})
+		return
+	}
+	if oldep != nil {
+		e.slowStart.observe(oldep, newep, e.clock())
+		e.observeDraining(oldep, newep)
+	} else {
+		// forget the addresses still draining from deleted Endpoints
+		// which reappeared.
+		e.observeDraining(&v1.Endpoints{ObjectMeta: newep.ObjectMeta}, newep)
+	}
+	e.endpoints[k8s.FullName{Name: newep.Name, Namespace: newep.Namespace}] = newep
+}
+
+// clock returns the current time.
+func (e *EndpointsTranslator) clock() time.Time {
+	if e.now == nil {
+		return time.Now()
+	}
+	return e.now()
+}
+
+// nodeTopology tracks the Locality of each Node.
+type nodeTopology struct {
+	localities map[string]*envoy_api_v2_core.Locality // keyed by Node name
+}
+
+// locality returns the Locality of the named Node, or nil if unknown.
+func (t *nodeTopology) locality(nodename string) *envoy_api_v2_core.Locality {
+	if nodename == "" {
+		return nil
+	}
+	return t.localities[nodename]
+}
+
+// nodeLocality returns the Locality described by the topology labels of
+// the Node, or nil if the Node carries neither a region nor a zone label.
+func nodeLocality(node *v1.Node) *envoy_api_v2_core.Locality {
+	label := func(key, fallback string) string {
+		if v, ok := node.Labels[key]; ok {
+			return v
+		}
+		return node.Labels[fallback]
+	}
+	region := label(labelTopologyRegion, labelFailureDomainRegion)
+	zone := label(labelTopologyZone, labelFailureDomainZone)
+	if region == "" && zone == "" {
+		return nil
+	}
+	return &envoy_api_v2_core.Locality{
+		Region: region,
+		Zone:   zone,
+	}
+}
+
+func (e *EndpointsTranslator) updateNode(node *v1.Node) {
+	e.setNodeLocality(node.Name, nodeLocality(node))
+}
+
+func (e *EndpointsTranslator) removeNode(node *v1.Node) {
+	e.setNodeLocality(node.Name, nil)
+}
+
+// setNodeLocality records the Locality of the Node and recomputes the
+// ClusterLoadAssignments of any Endpoints with addresses on that Node
+// if its Locality changed.
+func (e *EndpointsTranslator) setNodeLocality(nodename string, locality *envoy_api_v2_core.Locality) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if proto.Equal(e.topology.locality(nodename), locality) {
+		return
+	}
+	if e.topology.localities == nil {
+		e.topology.localities = make(map[string]*envoy_api_v2_core.Locality)
+	}
+	if locality == nil {
+		delete(e.topology.localities, nodename)
+	} else {
+		e.topology.localities[nodename] = locality
+	}
+
+	for _, ep := range e.endpoints {
+		if onNode(ep, nodename) {
+			e.computeClusterLoadAssignment(ep, ep)
+		}
+	}
+}
+
+// onNode returns true if any address of the Endpoints is on the named Node.
+func onNode(ep *v1.Endpoints, nodename string) bool {
+	for _, s := range ep.Subsets {
+		for _, addresses := range [][]v1.EndpointAddress{s.Addresses, s.NotReadyAddresses} {
+			for _, a := range addresses {
+				if a.NodeName != nil && *a.NodeName == nodename {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// slowStart tracks the addresses of the Endpoints of Services with a
+// slow start window. An address which becomes ready is given a load
+// balancing weight which grows linearly with its age until the end of the
+// window. Addresses known when the Endpoints are first seen, for example
+// at startup, are considered warm.
+type slowStart struct {
+	windows   map[k8s.FullName]time.Duration
+	firstSeen map[k8s.FullName]map[string]time.Time // keyed by address IP
+}
+
+// setWindow records the slow start window of the Service and returns
+// true if it changed.
+func (s *slowStart) setWindow(name k8s.FullName, window time.Duration) bool {
+	if s.windows[name] == window {
+		return false
+	}
+	if s.windows == nil {
+		s.windows = make(map[k8s.FullName]time.Duration)
+	}
+	if window == 0 {
+		delete(s.windows, name)
+		delete(s.firstSeen, name)
+	} else {
+		s.windows[name] = window
+	}
+	return true
+}
+
+// observe records the time at which the ready addresses of newep which
+// were not ready in oldep appeared.
+func (s *slowStart) observe(oldep, newep *v1.Endpoints, now time.Time) {
+	name := k8s.FullName{Name: newep.Name, Namespace: newep.Namespace}
+	if s.windows[name] == 0 {
+		return
+	}
+
+	ready := make(map[string]bool)
+	for _, ss := range oldep.Subsets {
+		for _, a := range ss.Addresses {
+			ready[a.IP] = true
+		}
+	}
+
+	seen := s.firstSeen[name]
+	current := make(map[string]time.Time)
+	for _, ss := range newep.Subsets {
+		for _, a := range ss.Addresses {
+			switch t, ok := seen[a.IP]; {
+			case ok:
+				current[a.IP] = t
+			case !ready[a.IP]:
+				current[a.IP] = now
+			}
+		}
+	}
+
+	if s.firstSeen == nil {
+		s.firstSeen = make(map[k8s.FullName]map[string]time.Time)
+	}
+	s.firstSeen[name] = current
+}
+
+func (s *slowStart) forget(name k8s.FullName) {
+	delete(s.firstSeen, name)
+}
+
+// weight returns the load balancing weight of the address, or nil if
+// the Service has no slow start window.
+func (s *slowStart) weight(name k8s.FullName, ip string, now time.Time) *uint32 {
+	window := s.windows[name]
+	if window == 0 {
+		return nil
+	}
+	weight := uint32(slowStartMaxWeight)
+	if t, ok := s.firstSeen[name][ip]; ok {
+		if age := now.Sub(t); age < window {
+			weight = uint32(slowStartMaxWeight * age / window)
+			if weight < 1 {
+				weight = 1
+			}
+		}
+	}
+	return &weight
+}
+
+// warming returns true if any address of the Service is still within
+// its slow start window, forgetting the addresses which are not.
+func (s *slowStart) warming(name k8s.FullName, now time.Time) bool {
+	window := s.windows[name]
+	warming := false
+	for ip, t := range s.firstSeen[name] {
+		if now.Sub(t) < window {
+			warming = true
+		} else {
+			delete(s.firstSeen[name], ip)
+		}
+	}
+	return warming
+}
+
+func (e *EndpointsTranslator) updateService(svc *v1.Service) {
+	e.setSlowStartWindow(svc.ObjectMeta, annotation.SlowStartWindow(svc))
+}
+
+func (e *EndpointsTranslator) removeService(svc *v1.Service) {
+	e.setSlowStartWindow(svc.ObjectMeta, 0)
+}
+
+// setSlowStartWindow records the slow start window of the Service and
+// recomputes its ClusterLoadAssignments if the window changed.
+func (e *EndpointsTranslator) setSlowStartWindow(meta metav1.ObjectMeta, window time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	name := k8s.FullName{Name: meta.Name, Namespace: meta.Namespace}
+	if !e.slowStart.setWindow(name, window) {
+		return
+	}
+	if ep, ok := e.endpoints[name]; ok {
+		e.computeClusterLoadAssignment(ep, ep)
+	}
+}
+
+// Start raises the weights of the endpoints in their slow start window
+// and expires the draining endpoints every slowStartInterval until stop
+// is closed.
+func (e *EndpointsTranslator) Start(stop <-chan struct{}) error {
+	ticker := time.NewTicker(slowStartInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.recomputeWarming()
+			e.expireDraining()
+		case <-stop:
+			return nil
+		}
+	}
+}
+
+// recomputeWarming recomputes the ClusterLoadAssignments of the Endpoints
+// with addresses in their slow start window. Addresses which completed
+// their window are recomputed one last time at their full weight.
+func (e *EndpointsTranslator) recomputeWarming() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for name := range e.slowStart.firstSeen {
+		ep, ok := e.endpoints[name]
+		if !ok {
+			continue
+		}
+		if !e.slowStart.warming(name, e.clock()) {
+			delete(e.slowStart.firstSeen, name)
+		}
+		e.computeClusterLoadAssignment(ep, ep)
+	}
+}
+
+// drainingAddress is a ready address removed from its Endpoints.
+type drainingAddress struct {
+	v1.EndpointAddress
+	ports []v1.EndpointPort
+	since time.Time
+}
+
+// observeDraining records the ready addresses of oldep which are no longer
+// part of newep as draining, and forgets those which reappeared.
+func (e *EndpointsTranslator) observeDraining(oldep, newep *v1.Endpoints) {
+	if e.DrainingPeriod <= 0 {
+		return
+	}
+	name := k8s.FullName{Name: newep.Name, Namespace: newep.Namespace}
+
+	present := make(map[string]bool)
+	for _, s := range newep.Subsets {
+		for _, a := range s.Addresses {
+			present[a.IP] = true
+		}
+		for _, a := range s.NotReadyAddresses {
+			present[a.IP] = true
+		}
+	}
+
+	draining := e.draining[name]
+	for ip := range draining {
+		if present[ip] {
+			delete(draining, ip)
+		}
+	}
+	for _, s := range oldep.Subsets {
+		for _, a := range s.Addresses {
+			if present[a.IP] {
+				continue
+			}
+			if draining == nil {
+				draining = make(map[string]drainingAddress)
+			}
+			draining[a.IP] = drainingAddress{
+				EndpointAddress: a,
+				ports:           s.Ports,
+				since:           e.clock(),
+			}
+		}
+	}
+
+	if len(draining) == 0 {
+		delete(e.draining, name)
+		return
+	}
+	if e.draining == nil {
+		e.draining = make(map[k8s.FullName]map[string]drainingAddress)
+	}
+	e.draining[name] = draining
+}
+
+// expireDraining forgets the addresses draining for longer than the
+// DrainingPeriod and recomputes the ClusterLoadAssignments they were part of,
+// removing those left without addresses, including those of deleted Endpoints.
+func (e *EndpointsTranslator) expireDraining() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for name, draining := range e.draining {
+		var expired []v1.EndpointSubset
+		for ip, a := range draining {
+			if e.clock().Sub(a.since) >= e.DrainingPeriod {
+				delete(draining, ip)
+				expired = append(expired, v1.EndpointSubset{
+					Addresses: []v1.EndpointAddress{a.EndpointAddress},
+					Ports:     a.ports,
+				})
+			}
+		}
+		if len(draining) == 0 {
+			delete(e.draining, name)
+		}
+		if len(expired) == 0 {
+			continue
+		}
+
+		ep, ok := e.endpoints[name]
+		if !ok {
+			ep = &v1.Endpoints{
+				ObjectMeta: metav1.ObjectMeta{Name: name.Name, Namespace: name.Namespace},
+			}
+		}
+		// the ports of the expired addresses are removed unless
+		// they are still published.
+		oldep := ep.DeepCopy()
+		oldep.Subsets = append(oldep.Subsets, expired...)
+		e.computeClusterLoadAssignment(oldep, ep)
+	}
+}
+
+// drainingPorts returns the TCP ports of the addresses still draining from
+// the Endpoints, sorted by name.
+func (e *EndpointsTranslator) drainingPorts(meta metav1.ObjectMeta) []v1.EndpointPort {
+	seen := make(map[v1.EndpointPort]bool)
+	var ports []v1.EndpointPort
+	for _, a := range e.draining[k8s.FullName{Name: meta.Name, Namespace: meta.Namespace}] {
+		for _, p := range a.ports {
+			if p.Protocol != "TCP" || seen[p] {
+				continue
+			}
+			seen[p] = true
+			ports = append(ports, p)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool {
+		return ports[i].Name < ports[j].Name
+	})
+	return ports
+}
+
+// hasAddresses returns true if the subset has addresses to publish.
+func (e *EndpointsTranslator) hasAddresses(s v1.EndpointSubset) bool {
+	return len(s.Addresses) > 0 || (e.PublishNotReadyAddresses && len(s.NotReadyAddresses) > 0)
+}
+
+// lbAddress is an address to publish along with its health status.
+type lbAddress struct {
+	v1.EndpointAddress
+	status envoy_api_v2_core.HealthStatus
+}
+
+// lbAddresses returns the sorted ready addresses followed by, if published,
+// the not ready addresses as unhealthy and the addresses of the port still
+// draining.
+func (e *EndpointsTranslator) lbAddresses(meta metav1.ObjectMeta, ready, notReady []v1.EndpointAddress, port v1.EndpointPort) []lbAddress {
+	addresses := make([]lbAddress, 0, len(ready))
+	for _, a := range ready {
+		addresses = append(addresses, lbAddress{EndpointAddress: a})
+	}
+
+	if e.PublishNotReadyAddresses {
+		unhealthy := append([]v1.EndpointAddress{}, notReady...) // shallow copy
+		sortAddresses(unhealthy)
+		for _, a := range unhealthy {
+			addresses = append(addresses, lbAddress{EndpointAddress: a, status: envoy_api_v2_core.HealthStatus_UNHEALTHY})
+		}
+	}
+
+	var draining []v1.EndpointAddress
+	for _, a := range e.draining[k8s.FullName{Name: meta.Name, Namespace: meta.Namespace}] {
+		for _, p := range a.ports {
+			if p.Name == port.Name && p.Port == port.Port {
+				draining = append(draining, a.EndpointAddress)
+			}
+		}
+	}
+	sortAddresses(draining)
+	for _, a := range draining {
+		addresses = append(addresses, lbAddress{EndpointAddress: a, status: envoy_api_v2_core.HealthStatus_DRAINING})
+	}
+	return addresses
+}
+
+// localityLbEndpoints groups the addresses by the Locality of the Node they
+// are scheduled on, preserving the order of the addresses within each group.
+// If no Locality is known a single group without Locality is returned.
+// Otherwise each group is weighted by its number of endpoints so Envoy can
+// perform locality weighted load balancing; addresses on Nodes of unknown
+// Locality are collected in a group without Locality.
+func (e *EndpointsTranslator) localityLbEndpoints(meta metav1.ObjectMeta, addresses []lbAddress, port int) []*envoy_api_v2_endpoint.LocalityLbEndpoints {
+	name := k8s.FullName{Name: meta.Name, Namespace: meta.Namespace}
+
+	var groups []*envoy_api_v2_endpoint.LocalityLbEndpoints
+	index := make(map[string]*envoy_api_v2_endpoint.LocalityLbEndpoints)
+	for _, a := range addresses {
+		var locality *envoy_api_v2_core.Locality
+		if a.NodeName != nil {
+			locality = e.topology.locality(*a.NodeName)
+		}
+		key := locality.GetRegion() + "/" + locality.GetZone()
+		if locality == nil {
+			key = ""
+		}
+		group, ok := index[key]
+		if !ok {
+			group = &envoy_api_v2_endpoint.LocalityLbEndpoints{
+				Locality: locality,
+			}
+			index[key] = group
+			groups = append(groups, group)
+		}
+		lbendpoint := envoy.LBEndpoint(envoy.SocketAddress(a.IP, port))
+		lbendpoint.HealthStatus = a.status
+		if weight := e.slowStart.weight(name, a.IP, e.clock()); weight != nil {
+			lbendpoint.LoadBalancingWeight = protobuf.UInt32(*weight)
+		}
+		group.LbEndpoints = append(group.LbEndpoints, lbendpoint)
+	}
+
+	if len(groups) == 1 && groups[0].Locality == nil {
+		return groups
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i].Locality, groups[j].Locality
+		switch {
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		case a.Region != b.Region:
+			return a.Region < b.Region
+		default:
+			return a.Zone < b.Zone
+		}
+	})
+	for _, g := range groups {
+		g.LoadBalancingWeight = protobuf.UInt32(uint32(len(g.LbEndpoints)))
+	}
+	return groups
+}
@@ -15,12 +15,17 @@ package contour
 
 import (
 	"testing"
+	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_endpoint "github.com/envoyproxy/go-control-plane/envoy/api/v2/endpoint"
 	"github.com/golang/protobuf/proto"
 	"github.com/projectcontour/contour/internal/assert"
 	"github.com/projectcontour/contour/internal/envoy"
+	"github.com/projectcontour/contour/internal/protobuf"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestEndpointsTranslatorContents(t *testing.T) {
@@ -436,6 +441,308 @@ func TestEndpointsTranslatorScaleToZeroEndpoints(t *testing.T) {
 	assert.Equal(t, want, got)
 }
 
+func TestEndpointsTranslatorLocality(t *testing.T) {
+	node := func(name, region, zone string) *v1.Node {
+		return &v1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"topology.kubernetes.io/region":          region,
+					"failure-domain.beta.kubernetes.io/zone": zone,
+				},
+			},
+		}
+	}
+	address := func(ip, nodename string) v1.EndpointAddress {
+		return v1.EndpointAddress{IP: ip, NodeName: &nodename}
+	}
+	locality := func(region, zone string, weight uint32, addrs ...*envoy_api_v2_core.Address) *envoy_api_v2_endpoint.LocalityLbEndpoints {
+		lle := envoy.Endpoints(addrs...)[0]
+		if region != "" || zone != "" {
+			lle.Locality = &envoy_api_v2_core.Locality{Region: region, Zone: zone}
+		}
+		lle.LoadBalancingWeight = protobuf.UInt32(weight)
+		return lle
+	}
+
+	et := &EndpointsTranslator{
+		FieldLogger: testLogger(t),
+	}
+	ep := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses: []v1.EndpointAddress{
+			address("10.0.0.1", "node-b"),
+			address("10.0.0.2", "node-a"),
+			address("10.0.0.3", "node-c"),
+			address("10.0.0.4", "node-b"),
+		},
+		Ports: ports(
+			port("", 8080),
+		),
+	})
+
+	// no node known yet, a single group without locality
+	et.OnAdd(ep)
+	assert.Equal(t, []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple",
+			envoy.SocketAddress("10.0.0.1", 8080),
+			envoy.SocketAddress("10.0.0.2", 8080),
+			envoy.SocketAddress("10.0.0.3", 8080),
+			envoy.SocketAddress("10.0.0.4", 8080),
+		),
+	}, et.Contents())
+
+	// adding the nodes recomputes the endpoints on them
+	et.OnAdd(node("node-a", "us-east-1", "us-east-1a"))
+	et.OnAdd(node("node-b", "us-east-1", "us-east-1b"))
+	assert.Equal(t, []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{
+				locality("us-east-1", "us-east-1a", 1, envoy.SocketAddress("10.0.0.2", 8080)),
+				locality("us-east-1", "us-east-1b", 2, envoy.SocketAddress("10.0.0.1", 8080), envoy.SocketAddress("10.0.0.4", 8080)),
+				locality("", "", 1, envoy.SocketAddress("10.0.0.3", 8080)),
+			},
+		},
+	}, et.Contents())
+
+	// deleting a node moves its endpoints to the group without locality
+	et.OnDelete(node("node-a", "us-east-1", "us-east-1a"))
+	assert.Equal(t, []proto.Message{
+		&v2.ClusterLoadAssignment{
+			ClusterName: "default/simple",
+			Endpoints: []*envoy_api_v2_endpoint.LocalityLbEndpoints{
+				locality("us-east-1", "us-east-1b", 2, envoy.SocketAddress("10.0.0.1", 8080), envoy.SocketAddress("10.0.0.4", 8080)),
+				locality("", "", 2, envoy.SocketAddress("10.0.0.2", 8080), envoy.SocketAddress("10.0.0.3", 8080)),
+			},
+		},
+	}, et.Contents())
+}
+
+func TestEndpointsTranslatorSlowStart(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	et := &EndpointsTranslator{
+		FieldLogger: testLogger(t),
+	}
+	et.now = func() time.Time { return now }
+
+	weighted := func(weights map[string]uint32, ips ...string) *v2.ClusterLoadAssignment {
+		var addrs []*envoy_api_v2_core.Address
+		for _, ip := range ips {
+			addrs = append(addrs, envoy.SocketAddress(ip, 8080))
+		}
+		cla := envoy.ClusterLoadAssignment("default/simple", addrs...)
+		for _, lbe := range cla.Endpoints[0].LbEndpoints {
+			ip := lbe.GetEndpoint().Address.GetSocketAddress().Address
+			lbe.LoadBalancingWeight = protobuf.UInt32(weights[ip])
+		}
+		return cla
+	}
+
+	et.OnAdd(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"adobeplatform.adobe.io/slow-start-window": "100s",
+			},
+		},
+	})
+
+	// addresses known when the endpoints are first seen are warm
+	e1 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses: addresses("10.0.0.1"),
+		Ports:     ports(port("", 8080)),
+	})
+	et.OnAdd(e1)
+	assert.Equal(t, []proto.Message{
+		weighted(map[string]uint32{"10.0.0.1": 100}, "10.0.0.1"),
+	}, et.Contents())
+
+	// a new address starts with the minimum weight
+	e2 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses: addresses("10.0.0.1", "10.0.0.2"),
+		Ports:     ports(port("", 8080)),
+	})
+	et.OnUpdate(e1, e2)
+	assert.Equal(t, []proto.Message{
+		weighted(map[string]uint32{"10.0.0.1": 100, "10.0.0.2": 1}, "10.0.0.1", "10.0.0.2"),
+	}, et.Contents())
+
+	// and ramps up with its age
+	now = now.Add(25 * time.Second)
+	et.recomputeWarming()
+	assert.Equal(t, []proto.Message{
+		weighted(map[string]uint32{"10.0.0.1": 100, "10.0.0.2": 25}, "10.0.0.1", "10.0.0.2"),
+	}, et.Contents())
+
+	// until the end of the window
+	now = now.Add(100 * time.Second)
+	et.recomputeWarming()
+	assert.Equal(t, []proto.Message{
+		weighted(map[string]uint32{"10.0.0.1": 100, "10.0.0.2": 100}, "10.0.0.1", "10.0.0.2"),
+	}, et.Contents())
+	assert.Equal(t, 0, len(et.slowStart.firstSeen))
+
+	// removing the annotation removes the weights
+	et.OnUpdate(nil, &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+	})
+	assert.Equal(t, []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple",
+			envoy.SocketAddress("10.0.0.1", 8080),
+			envoy.SocketAddress("10.0.0.2", 8080),
+		),
+	}, et.Contents())
+}
+
+func TestEndpointsTranslatorHealthStatus(t *testing.T) {
+	now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+	et := &EndpointsTranslator{
+		FieldLogger:              testLogger(t),
+		PublishNotReadyAddresses: true,
+		DrainingPeriod:           30 * time.Second,
+	}
+	et.now = func() time.Time { return now }
+
+	statuses := func(status map[string]envoy_api_v2_core.HealthStatus, ips ...string) *v2.ClusterLoadAssignment {
+		var addrs []*envoy_api_v2_core.Address
+		for _, ip := range ips {
+			addrs = append(addrs, envoy.SocketAddress(ip, 8080))
+		}
+		cla := envoy.ClusterLoadAssignment("default/simple", addrs...)
+		for _, lbe := range cla.Endpoints[0].LbEndpoints {
+			ip := lbe.GetEndpoint().Address.GetSocketAddress().Address
+			lbe.HealthStatus = status[ip]
+		}
+		return cla
+	}
+
+	// not ready addresses are published as unhealthy
+	e1 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses:         addresses("10.0.0.1", "10.0.0.2"),
+		NotReadyAddresses: addresses("10.0.0.3"),
+		Ports:             ports(port("", 8080)),
+	})
+	et.OnAdd(e1)
+	assert.Equal(t, []proto.Message{
+		statuses(map[string]envoy_api_v2_core.HealthStatus{
+			"10.0.0.3": envoy_api_v2_core.HealthStatus_UNHEALTHY,
+		}, "10.0.0.1", "10.0.0.2", "10.0.0.3"),
+	}, et.Contents())
+
+	// removed ready addresses are published as draining
+	e2 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses:         addresses("10.0.0.1"),
+		NotReadyAddresses: addresses("10.0.0.3"),
+		Ports:             ports(port("", 8080)),
+	})
+	et.OnUpdate(e1, e2)
+	assert.Equal(t, []proto.Message{
+		statuses(map[string]envoy_api_v2_core.HealthStatus{
+			"10.0.0.3": envoy_api_v2_core.HealthStatus_UNHEALTHY,
+			"10.0.0.2": envoy_api_v2_core.HealthStatus_DRAINING,
+		}, "10.0.0.1", "10.0.0.3", "10.0.0.2"),
+	}, et.Contents())
+
+	// during the draining period
+	now = now.Add(10 * time.Second)
+	et.expireDraining()
+	assert.Equal(t, 1, len(et.draining))
+
+	// after which they are removed
+	now = now.Add(20 * time.Second)
+	et.expireDraining()
+	assert.Equal(t, []proto.Message{
+		statuses(map[string]envoy_api_v2_core.HealthStatus{
+			"10.0.0.3": envoy_api_v2_core.HealthStatus_UNHEALTHY,
+		}, "10.0.0.1", "10.0.0.3"),
+	}, et.Contents())
+	assert.Equal(t, 0, len(et.draining))
+}
+
+func TestEndpointsTranslatorDrainingLastAddresses(t *testing.T) {
+	draining := func(ips ...string) *v2.ClusterLoadAssignment {
+		var addrs []*envoy_api_v2_core.Address
+		for _, ip := range ips {
+			addrs = append(addrs, envoy.SocketAddress(ip, 8080))
+		}
+		cla := envoy.ClusterLoadAssignment("default/simple", addrs...)
+		for _, lbe := range cla.Endpoints[0].LbEndpoints {
+			lbe.HealthStatus = envoy_api_v2_core.HealthStatus_DRAINING
+		}
+		return cla
+	}
+	e1 := endpoints("default", "simple", v1.EndpointSubset{
+		Addresses: addresses("10.0.0.1", "10.0.0.2"),
+		Ports:     ports(port("", 8080)),
+	})
+
+	tests := map[string]func(et *EndpointsTranslator){
+		"scale to zero": func(et *EndpointsTranslator) {
+			et.OnUpdate(e1, endpoints("default", "simple"))
+		},
+		"no ready addresses left": func(et *EndpointsTranslator) {
+			et.OnUpdate(e1, endpoints("default", "simple", v1.EndpointSubset{
+				NotReadyAddresses: addresses("10.0.0.3"),
+				Ports:             ports(port("", 8080)),
+			}))
+		},
+		"endpoints deleted": func(et *EndpointsTranslator) {
+			et.OnDelete(e1)
+		},
+	}
+	for name, remove := range tests {
+		t.Run(name, func(t *testing.T) {
+			now := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+			et := &EndpointsTranslator{
+				FieldLogger:    testLogger(t),
+				DrainingPeriod: 30 * time.Second,
+			}
+			et.now = func() time.Time { return now }
+
+			et.OnAdd(e1)
+			remove(et)
+
+			// the last addresses are published as draining
+			assert.Equal(t, []proto.Message{
+				draining("10.0.0.1", "10.0.0.2"),
+			}, et.Contents())
+
+			// during the draining period
+			now = now.Add(10 * time.Second)
+			et.expireDraining()
+			assert.Equal(t, []proto.Message{
+				draining("10.0.0.1", "10.0.0.2"),
+			}, et.Contents())
+
+			// after which the ClusterLoadAssignment is removed
+			now = now.Add(20 * time.Second)
+			et.expireDraining()
+			assert.Equal(t, []proto.Message(nil), et.Contents())
+			assert.Equal(t, 0, len(et.draining))
+		})
+	}
+
+	// addresses of deleted Endpoints which reappear are no longer draining
+	et := &EndpointsTranslator{
+		FieldLogger:    testLogger(t),
+		DrainingPeriod: 30 * time.Second,
+	}
+	et.OnAdd(e1)
+	et.OnDelete(e1)
+	et.OnAdd(e1)
+	assert.Equal(t, []proto.Message{
+		envoy.ClusterLoadAssignment("default/simple",
+			envoy.SocketAddress("10.0.0.1", 8080),
+			envoy.SocketAddress("10.0.0.2", 8080),
+		),
+	}, et.Contents())
+	assert.Equal(t, 0, len(et.draining))
+}
+
 func ports(eps ...v1.EndpointPort) []v1.EndpointPort {
 	return eps
 }
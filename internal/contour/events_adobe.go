@@ -0,0 +1,110 @@
+package contour
+
+import (
+	"fmt"
+
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the events not derived from the status conditions.
+const (
+	EventReasonValid    = "Valid"
+	EventReasonInvalid  = "Invalid"
+	EventReasonOrphaned = "Orphaned"
+	EventReasonConflict = "Conflict"
+)
+
+// StatusEvents records Kubernetes Events on the IngressRoutes and
+// HTTPProxies when they become invalid or orphaned, when they recover, and
+// when they are affected by an invalid object they conflict with. An event
+// is recorded only when the state of the object changes, not on every DAG
+// rebuild.
+type StatusEvents struct {
+	Recorder record.EventRecorder
+
+	// last holds the state of the objects, and of the conflicts, as of the
+	// previous call to Record.
+	last map[string]string
+}
+
+// Record records the events for the statuses and conflicts of the last DAG.
+func (s *StatusEvents) Record(statuses map[k8s.FullName]dag.Status, conflicts []dag.Conflict) {
+	last := make(map[string]string, len(statuses)+len(conflicts))
+
+	for _, st := range statuses {
+		obj, ok := st.Object.(runtime.Object)
+		if !ok {
+			continue
+		}
+		key := eventKey(st.Object)
+		state := statusState(st)
+		last[key] = state
+
+		prev, seen := s.last[key]
+		if seen && prev == state {
+			continue
+		}
+
+		switch st.Status {
+		case k8s.StatusValid:
+			// only the recovery of an object is worth an event
+			if seen {
+				s.Recorder.Event(obj, v1.EventTypeNormal, EventReasonValid, st.Description)
+			}
+		case k8s.StatusOrphaned:
+			s.Recorder.Event(obj, v1.EventTypeWarning, EventReasonOrphaned, st.Description)
+		case k8s.StatusInvalid:
+			recorded := false
+			for _, c := range st.Conditions {
+				if c.Type == projcontour.ConditionError {
+					s.Recorder.Event(obj, v1.EventTypeWarning, c.Reason, c.Message)
+					recorded = true
+				}
+			}
+			if !recorded {
+				s.Recorder.Event(obj, v1.EventTypeWarning, EventReasonInvalid, st.Description)
+			}
+		}
+	}
+
+	for _, c := range conflicts {
+		obj, ok := c.Object.(runtime.Object)
+		if !ok {
+			continue
+		}
+		key := "conflict/" + eventKey(c.Object) + "/" + eventKey(c.With)
+		last[key] = c.Description
+		if prev, seen := s.last[key]; seen && prev == c.Description {
+			continue
+		}
+
+		with := c.With.GetObjectMeta()
+		s.Recorder.Eventf(obj, v1.EventTypeWarning, EventReasonConflict,
+			"%s %s/%s is invalid: %s", k8s.KindOf(c.With), with.GetNamespace(), with.GetName(), c.Description)
+	}
+
+	// forget the objects no longer present
+	s.last = last
+}
+
+// eventKey identifies the object, its UID telling apart the objects
+// recreated with the same name.
+func eventKey(obj k8s.Object) string {
+	meta := obj.GetObjectMeta()
+	return fmt.Sprintf("%s/%s/%s/%s", k8s.KindOf(obj), meta.GetNamespace(), meta.GetName(), meta.GetUID())
+}
+
+// statusState summarises the status of the object, a change of which is
+// worth an event. The description of a valid object is left out, as its
+// warnings change without the object becoming invalid.
+func statusState(st dag.Status) string {
+	if st.Status == k8s.StatusValid {
+		return st.Status
+	}
+	return st.Status + ": " + st.Description
+}
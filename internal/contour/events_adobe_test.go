@@ -0,0 +1,132 @@
+package contour
+
+import (
+	"testing"
+
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/k8s"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestStatusEvents(t *testing.T) {
+	proxy := func(name string, uid types.UID) *projcontour.HTTPProxy {
+		return &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				UID:       uid,
+			},
+		}
+	}
+	wildcard := proxy("wildcard", "1")
+	other := proxy("other", "2")
+	recreated := proxy("wildcard", "3")
+
+	valid := func(obj *projcontour.HTTPProxy) dag.Status {
+		return dag.Status{Object: obj, Status: k8s.StatusValid, Description: "valid HTTPProxy"}
+	}
+	invalid := func(obj *projcontour.HTTPProxy, reason, msg string) dag.Status {
+		return dag.Status{
+			Object:      obj,
+			Status:      k8s.StatusInvalid,
+			Description: msg,
+			Conditions: []projcontour.StatusCondition{{
+				Type:    projcontour.ConditionValid,
+				Status:  v1.ConditionFalse,
+				Reason:  "Invalid",
+				Message: msg,
+			}, {
+				Type:    projcontour.ConditionError,
+				Status:  v1.ConditionTrue,
+				Reason:  reason,
+				Message: msg,
+			}},
+		}
+	}
+	orphaned := func(obj *projcontour.HTTPProxy) dag.Status {
+		return dag.Status{Object: obj, Status: k8s.StatusOrphaned, Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy"}
+	}
+	statuses := func(sts ...dag.Status) map[k8s.FullName]dag.Status {
+		m := make(map[k8s.FullName]dag.Status)
+		for _, st := range sts {
+			meta := st.Object.GetObjectMeta()
+			m[k8s.FullName{Name: meta.GetName(), Namespace: meta.GetNamespace()}] = st
+		}
+		return m
+	}
+
+	const overlapping = `fqdn "*.example.com" overlaps with the virtual hosts of other namespaces: default/other`
+	conflict := dag.Conflict{Object: other, With: wildcard, Description: overlapping}
+
+	type step struct {
+		statuses  map[k8s.FullName]dag.Status
+		conflicts []dag.Conflict
+		want      []string
+	}
+
+	tests := map[string][]step{
+		"valid objects": {{
+			statuses: statuses(valid(wildcard), valid(other)),
+		}},
+		"invalid object": {{
+			statuses: statuses(invalid(wildcard, dag.ReasonServiceInvalid, `Service [missing:8080] is invalid or missing`)),
+			want:     []string{`Warning ServiceInvalid Service [missing:8080] is invalid or missing`},
+		}, {
+			// unchanged
+			statuses: statuses(invalid(wildcard, dag.ReasonServiceInvalid, `Service [missing:8080] is invalid or missing`)),
+		}, {
+			statuses: statuses(invalid(wildcard, dag.ReasonServiceInvalid, `Service [other:8080] is invalid or missing`)),
+			want:     []string{`Warning ServiceInvalid Service [other:8080] is invalid or missing`},
+		}, {
+			statuses: statuses(valid(wildcard)),
+			want:     []string{`Normal Valid valid HTTPProxy`},
+		}},
+		"orphaned object": {{
+			statuses: statuses(orphaned(other)),
+			want:     []string{`Warning Orphaned this HTTPProxy is not part of a delegation chain from a root HTTPProxy`},
+		}, {
+			statuses: statuses(orphaned(other)),
+		}},
+		"conflicting objects": {{
+			statuses:  statuses(invalid(wildcard, dag.ReasonVirtualHostInvalid, overlapping), valid(other)),
+			conflicts: []dag.Conflict{conflict},
+			want: []string{
+				`Warning VirtualHostInvalid ` + overlapping,
+				`Warning Conflict HTTPProxy default/wildcard is invalid: ` + overlapping,
+			},
+		}, {
+			statuses:  statuses(invalid(wildcard, dag.ReasonVirtualHostInvalid, overlapping), valid(other)),
+			conflicts: []dag.Conflict{conflict},
+		}, {
+			// the conflict is recorded again once resolved then back
+			statuses: statuses(valid(other)),
+		}, {
+			statuses:  statuses(invalid(recreated, dag.ReasonVirtualHostInvalid, overlapping), valid(other)),
+			conflicts: []dag.Conflict{{Object: other, With: recreated, Description: overlapping}},
+			want: []string{
+				`Warning VirtualHostInvalid ` + overlapping,
+				`Warning Conflict HTTPProxy default/wildcard is invalid: ` + overlapping,
+			},
+		}},
+	}
+
+	for name, steps := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			events := &StatusEvents{Recorder: recorder}
+			for _, s := range steps {
+				events.Record(s.statuses, s.conflicts)
+				var got []string
+				for len(recorder.Events) > 0 {
+					got = append(got, <-recorder.Events)
+				}
+				assert.Equal(t, s.want, got)
+			}
+		})
+	}
+}
@@ -41,6 +41,10 @@ type EventHandler struct {
 
 	StatusClient k8s.StatusClient
 
+	// Adobe - StatusEvents, if not nil, records events on the invalid,
+	// orphaned and conflicting objects.
+	StatusEvents *StatusEvents
+
 	logrus.FieldLogger
 
 	// IsLeader will become ready to read when this EventHandler becomes
@@ -188,6 +192,8 @@ func (e *EventHandler) onUpdate(op interface{}) bool {
 		insert := e.Builder.Source.Insert(op.newObj)
 		return remove || insert
 	case opDelete:
+		// Adobe - the status written for the object is forgotten
+		e.forgetStatus(op.obj)
 		return e.Builder.Source.Remove(op.obj)
 	case bool:
 		return op
@@ -213,12 +219,20 @@ func (e *EventHandler) updateDAG() {
 	dag := e.Builder.Build()
 	e.CacheHandler.OnChange(dag)
 
+	// Adobe - certificate expiry, exported by every replica
+	e.Metrics.SetCertificateExpiry(calculateCertificateExpiry(dag))
+
 	select {
 	case <-e.IsLeader:
 		// we're the leader, update status and metrics
 		statuses := dag.Statuses()
 		e.setStatus(statuses)
 
+		// Adobe - events
+		if e.StatusEvents != nil {
+			e.StatusEvents.Record(statuses, dag.Conflicts())
+		}
+
 		metrics, proxymetrics := calculateRouteMetric(statuses)
 		e.Metrics.SetIngressRouteMetric(metrics)
 		e.Metrics.SetHTTPProxyMetric(proxymetrics)
@@ -232,7 +246,7 @@ func (e *EventHandler) setStatus(statuses map[k8s.FullName]dag.Status) {
 	for _, st := range statuses {
 		switch obj := st.Object.(type) {
 		case *ingressroutev1.IngressRoute:
-			err := e.StatusClient.SetStatus(st.Status, st.Description, obj)
+			err := e.StatusClient.SetStatus(st.Status, st.Description, st.Conditions, obj)
 			if err != nil {
 				e.WithError(err).
 					WithField("status", st.Status).
@@ -242,7 +256,7 @@ func (e *EventHandler) setStatus(statuses map[k8s.FullName]dag.Status) {
 					Error("failed to set status")
 			}
 		case *projcontour.HTTPProxy:
-			err := e.StatusClient.SetStatus(st.Status, st.Description, obj)
+			err := e.StatusClient.SetStatus(st.Status, st.Description, st.Conditions, obj)
 			if err != nil {
 				e.WithError(err).
 					WithField("status", st.Status).
@@ -1,7 +1,33 @@
 package contour
 
+import (
+	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
 // Used during synchronous cache initialization so that update() is called
 // only once instead of after every inserts
 func (reh *EventHandler) UpdateDAG() {
 	reh.updateDAG()
 }
+
+// statusDeleter is implemented by the StatusClients keeping the status
+// of the objects, like k8s.StatusQueue and k8s.StatusCacher.
+type statusDeleter interface {
+	Delete(obj interface{})
+}
+
+// forgetStatus removes the deleted IngressRoute or HTTPProxy from the
+// StatusClient, if it keeps the status of the objects.
+func (reh *EventHandler) forgetStatus(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	switch obj.(type) {
+	case *ingressroutev1.IngressRoute, *projcontour.HTTPProxy:
+		if sd, ok := reh.StatusClient.(statusDeleter); ok {
+			sd.Delete(obj)
+		}
+	}
+}
@@ -94,6 +94,25 @@ type ListenerVisitorConfig struct {
 
 	// RequestTimeout configures the request_timeout for all Connection Managers.
 	RequestTimeout time.Duration
+
+	// Adobe - CipherSuites lists the cipher suites offered by the HTTPS
+	// listener for TLS 1.2. Virtual hosts may override them.
+	// If not set, defaults to the cipher suites of internal/envoy/auth.go.
+	CipherSuites []string
+
+	// Adobe - ECDHCurves lists the ECDH curves offered by the HTTPS listener.
+	// Virtual hosts may override them.
+	// If not set, defaults to the Envoy defaults.
+	ECDHCurves []string
+
+	// Adobe - AccessLogSink, if set, sends the access logs to a gRPC
+	// access log service instead of the access log paths.
+	AccessLogSink *AccessLogSink
+
+	// Adobe - AccessLogFilter selects the requests logged. Virtual hosts
+	// may override it.
+	// If not set, all the requests are logged.
+	AccessLogFilter *dag.AccessLogFilter
 }
 
 // httpAddress returns the port for the HTTP (non TLS)
@@ -168,22 +187,16 @@ func (lvc *ListenerVisitorConfig) accesslogFields() []string {
 	return envoy.DefaultFields
 }
 
-func (lvc *ListenerVisitorConfig) newInsecureAccessLog() []*envoy_api_v2_accesslog.AccessLog {
-	switch lvc.accesslogType() {
-	case "json":
-		return envoy.FileAccessLogJSON(lvc.httpAccessLog(), lvc.accesslogFields())
-	default:
-		return envoy.FileAccessLogEnvoy(lvc.httpAccessLog())
-	}
+// Adobe - the access log, unless the virtual hosts override it with
+// accessLog, selects the requests logged with filter.
+func (lvc *ListenerVisitorConfig) newInsecureAccessLog(accessLog *dag.AccessLog, filter *envoy_api_v2_accesslog.AccessLogFilter) []*envoy_api_v2_accesslog.AccessLog {
+	return lvc.newAccessLog(lvc.httpAccessLog(), accessLog, filter)
 }
 
-func (lvc *ListenerVisitorConfig) newSecureAccessLog() []*envoy_api_v2_accesslog.AccessLog {
-	switch lvc.accesslogType() {
-	case "json":
-		return envoy.FileAccessLogJSON(lvc.httpsAccessLog(), lvc.accesslogFields())
-	default:
-		return envoy.FileAccessLogEnvoy(lvc.httpsAccessLog())
-	}
+// Adobe - the access log, unless the virtual hosts override it with
+// accessLog, selects the requests logged with filter.
+func (lvc *ListenerVisitorConfig) newSecureAccessLog(accessLog *dag.AccessLog, filter *envoy_api_v2_accesslog.AccessLogFilter) []*envoy_api_v2_accesslog.AccessLog {
+	return lvc.newAccessLog(lvc.httpsAccessLog(), accessLog, filter)
 }
 
 // requestTimeout sets any durations in lvc.RequestTimeout <0 to 0 so that Envoy ends up with a positive duration.
@@ -279,6 +292,16 @@ type listenerVisitor struct {
 
 	listeners map[string]*v2.Listener
 	http      bool // at least one dag.VirtualHost encountered
+
+	// Adobe - the name of the listener, and of its route configuration,
+	// the secure virtual hosts visited are served by; empty for the
+	// HTTPS listener.
+	secure string
+
+	// Adobe - the insecure virtual hosts, some of which may override the
+	// access log, and the filter chains of the secure ones overriding it.
+	insecureVirtualHosts []*dag.VirtualHost
+	secureAccessLogs     map[*envoy_api_v2_listener.FilterChain]bool
 }
 
 func visitListeners(root dag.Vertex, lvc *ListenerVisitorConfig) map[string]*v2.Listener {
@@ -302,7 +325,7 @@ func visitListeners(root dag.Vertex, lvc *ListenerVisitorConfig) map[string]*v2.
 			DefaultFilters().
 			RouteConfigName(ENVOY_HTTP_LISTENER).
 			MetricsPrefix(ENVOY_HTTP_LISTENER).
-			AccessLoggers(lvc.newInsecureAccessLog()).
+			AccessLoggers(lvc.newInsecureVirtualHostsAccessLog(lv.insecureVirtualHosts)). // Adobe - access log overrides
 			RequestTimeout(lvc.requestTimeout()).
 			Get()
 
@@ -327,7 +350,7 @@ func visitListeners(root dag.Vertex, lvc *ListenerVisitorConfig) map[string]*v2.
 					DefaultFilters().
 					RouteConfigName(ENVOY_HTTPS_LISTENER).
 					MetricsPrefix(ENVOY_HTTPS_LISTENER).
-					AccessLoggers(lv.ListenerVisitorConfig.newSecureAccessLog()).
+					AccessLoggers(lv.ListenerVisitorConfig.newSecureAccessLog(nil, lv.ListenerVisitorConfig.accessLogFilter(nil))).
 					RequestTimeout(lv.ListenerVisitorConfig.requestTimeout()).
 					Get(),
 			)
@@ -335,7 +358,8 @@ func visitListeners(root dag.Vertex, lvc *ListenerVisitorConfig) map[string]*v2.
 
 			fcNoSNI := envoy.FilterChainTLS(
 				"", // no "server_names"
-				envoy.DownstreamTLSContext(secret, lv.ListenerVisitorConfig.minProtoVersion(), nil, alpnProtos...),
+				lv.ListenerVisitorConfig.downstreamTLSParams(
+					envoy.DownstreamTLSContext(secret, lv.ListenerVisitorConfig.minProtoVersion(), nil, alpnProtos...), nil),
 				filters,
 			)
 			lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains = append(lv.listeners[ENVOY_HTTPS_LISTENER].FilterChains, fcNoSNI)
@@ -376,15 +400,27 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 	}
 
 	switch vh := vertex.(type) {
+	case *dag.Listener:
+		// Adobe - additional listeners
+		if vh.Name != "" {
+			v.visitAdditionalListener(vh)
+			return
+		}
+		vertex.Visit(v.visit)
 	case *dag.VirtualHost:
 		// we only create on http listener so record the fact
 		// that we need to then double back at the end and add
 		// the listener properly.
 		v.http = true
+		// Adobe - access log overrides
+		v.insecureVirtualHosts = append(v.insecureVirtualHosts, vh)
 	case *dag.SecureVirtualHost:
 		var alpnProtos []string
 		var filters []*envoy_api_v2_listener.Filter
 
+		// Adobe - the HTTPS listener, or the additional listener visited
+		listener := v.secureListener()
+
 		if vh.TCPProxy == nil {
 			// Create a uniquely named HTTP connection manager for
 			// this vhost, so that the SNI name the client requests
@@ -399,9 +435,9 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 					// AddFilter(envoy.FilterMisdirectedRequests(vh.VirtualHost.Name)).
 					DefaultFilters().
 					// RouteConfigName(path.Join("https", vh.VirtualHost.Name)).
-					RouteConfigName(ENVOY_HTTPS_LISTENER).
-					MetricsPrefix(ENVOY_HTTPS_LISTENER).
-					AccessLoggers(v.ListenerVisitorConfig.newSecureAccessLog()).
+					RouteConfigName(listener).
+					MetricsPrefix(listener).
+					AccessLoggers(v.ListenerVisitorConfig.newSecureAccessLog(vh.AccessLog, v.ListenerVisitorConfig.accessLogFilter(&vh.VirtualHost))).
 					RequestTimeout(v.ListenerVisitorConfig.requestTimeout()).
 					Get(),
 			)
@@ -409,9 +445,9 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 			alpnProtos = []string{"h2", "http/1.1"}
 		} else {
 			filters = envoy.Filters(
-				envoy.TCPProxy(ENVOY_HTTPS_LISTENER,
+				envoy.TCPProxy(listener,
 					vh.TCPProxy,
-					v.ListenerVisitorConfig.newSecureAccessLog()),
+					v.ListenerVisitorConfig.newTCPAccessLog(vh.AccessLog)), // Adobe - tcp access log
 			)
 
 			// Do not offer ALPN for TCP proxying, since
@@ -432,15 +468,22 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 				maxProtoVersion(vh.MaxProtoVersion),
 				vh.DownstreamValidation,
 				alpnProtos...)
+
+			// Adobe - cipher suites, ECDH curves and secondary certificate
+			downstreamTLS = envoy.DownstreamTLSSecondaryCertificate(
+				v.ListenerVisitorConfig.downstreamTLSParams(downstreamTLS, vh),
+				vh.Secret,
+				vh.SecondarySecret)
 		}
 
 		// Group filter chain by TransportSocket
 		// if a filter chain with the exact same DownstreamTlsContext already exists, just
 		// add the vhost name to the existing list
 		// EXCEPTION: don't group if TCPProxy filter exists (client-provided)
+		// Adobe - nor if either virtual host overrides the access log
 		fcExists := false
-		if vh.TCPProxy == nil && vh.Secret != nil {
-			for _, fc := range v.listeners[ENVOY_HTTPS_LISTENER].FilterChains {
+		if vh.TCPProxy == nil && vh.Secret != nil && vh.AccessLog == nil {
+			for _, fc := range v.listeners[listener].FilterChains {
 				if fc.TransportSocket == nil {
 					// No TransportSocket, skip
 					continue
@@ -449,8 +492,14 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 					// TCPProxy filter exists, skip
 					continue
 				}
+				if v.secureAccessLogs[fc] {
+					// access log override, skip
+					continue
+				}
 				if cmp.Equal(downstreamTLS, envoy.GetDownstreamTLSContext(fc)) {
 					fc.FilterChainMatch.ServerNames = append(fc.FilterChainMatch.ServerNames, vh.VirtualHost.Name)
+					// Adobe - aliases
+					fc.FilterChainMatch.ServerNames = append(fc.FilterChainMatch.ServerNames, vh.Aliases...)
 					sort.Strings(fc.FilterChainMatch.ServerNames)
 					fcExists = true
 					break
@@ -459,8 +508,20 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 		}
 
 		if !fcExists {
-			v.listeners[ENVOY_HTTPS_LISTENER].FilterChains = append(v.listeners[ENVOY_HTTPS_LISTENER].FilterChains,
-				envoy.FilterChainTLS(vh.VirtualHost.Name, downstreamTLS, filters))
+			fc := envoy.FilterChainTLS(vh.VirtualHost.Name, downstreamTLS, filters)
+			// Adobe - aliases
+			if len(vh.Aliases) > 0 {
+				fc.FilterChainMatch.ServerNames = append(fc.FilterChainMatch.ServerNames, vh.Aliases...)
+				sort.Strings(fc.FilterChainMatch.ServerNames)
+			}
+			v.listeners[listener].FilterChains = append(v.listeners[listener].FilterChains, fc)
+			// Adobe - access log override
+			if vh.AccessLog != nil {
+				if v.secureAccessLogs == nil {
+					v.secureAccessLogs = make(map[*envoy_api_v2_listener.FilterChain]bool)
+				}
+				v.secureAccessLogs[fc] = true
+			}
 		}
 
 		// If this VirtualHost has enabled the fallback certificate then set a default
@@ -468,7 +529,8 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 		// Note that we don't add the misdirected requests filter on this chain because at this
 		// point we don't actually know the full set of server names that will be bound to the
 		// filter chain through the ENVOY_FALLBACK_ROUTECONFIG route configuration.
-		if vh.FallbackCertificate != nil && !envoy.ContainsFallbackFilterChain(v.listeners[ENVOY_HTTPS_LISTENER].FilterChains) {
+		// Adobe - only on the HTTPS listener.
+		if vh.FallbackCertificate != nil && listener == ENVOY_HTTPS_LISTENER && !envoy.ContainsFallbackFilterChain(v.listeners[ENVOY_HTTPS_LISTENER].FilterChains) {
 			// Construct the downstreamTLSContext passing the configured fallbackCertificate. The TLS minProtocolVersion will use
 			// the value defined in the Contour Configuration file if defined.
 			downstreamTLS = envoy.DownstreamTLSContext(
@@ -476,13 +538,16 @@ func (v *listenerVisitor) visit(vertex dag.Vertex) {
 				v.ListenerVisitorConfig.minProtoVersion(),
 				vh.DownstreamValidation,
 				alpnProtos...)
+			// Adobe - the configured cipher suites and ECDH curves
+			downstreamTLS = v.ListenerVisitorConfig.downstreamTLSParams(downstreamTLS, nil)
 
 			// Default filter chain
+			// Adobe - counting the requests matching the fallback chain apart
 			filters = envoy.Filters(
 				envoy.HTTPConnectionManagerBuilder().
 					RouteConfigName(ENVOY_FALLBACK_ROUTECONFIG).
-					MetricsPrefix(ENVOY_HTTPS_LISTENER).
-					AccessLoggers(v.ListenerVisitorConfig.newSecureAccessLog()).
+					MetricsPrefix(ENVOY_FALLBACK_ROUTECONFIG).
+					AccessLoggers(v.ListenerVisitorConfig.newSecureAccessLog(nil, v.ListenerVisitorConfig.accessLogFilter(nil))).
 					RequestTimeout(v.ListenerVisitorConfig.requestTimeout()).
 					Get(),
 			)
@@ -3,13 +3,17 @@ package contour
 import (
 	"encoding/json"
 	"os"
+	"sort"
 
 	udpa_type_v1 "github.com/cncf/udpa/go/udpa/type/v1"
 	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	envoy_api_v2_listener "github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/envoy"
 	"github.com/projectcontour/contour/internal/protobuf"
+	"github.com/projectcontour/contour/internal/sorter"
 )
 
 type (
@@ -107,6 +111,22 @@ func CustomListenerFilters() []*envoy_api_v2_listener.ListenerFilter {
 	return []*envoy_api_v2_listener.ListenerFilter{ipAllowDenyListenerFilter}
 }
 
+// downstreamTLSParams sets the cipher suites and ECDH curves of the
+// DownstreamTlsContext: those of the secure vhost, if any, else the
+// configured ones.
+func (lvc *ListenerVisitorConfig) downstreamTLSParams(tls *envoy_api_v2_auth.DownstreamTlsContext, vh *dag.SecureVirtualHost) *envoy_api_v2_auth.DownstreamTlsContext {
+	cipherSuites, ecdhCurves := lvc.CipherSuites, lvc.ECDHCurves
+	if vh != nil {
+		if len(vh.CipherSuites) > 0 {
+			cipherSuites = vh.CipherSuites
+		}
+		if len(vh.ECDHCurves) > 0 {
+			ecdhCurves = vh.ECDHCurves
+		}
+	}
+	return envoy.DownstreamTLSParams(tls, cipherSuites, ecdhCurves)
+}
+
 // maxProtoVersion returns the max supported version if the given version is TLS_AUTO
 func maxProtoVersion(version envoy_api_v2_auth.TlsParameters_TlsProtocol) envoy_api_v2_auth.TlsParameters_TlsProtocol {
 	if version == envoy_api_v2_auth.TlsParameters_TLS_AUTO {
@@ -124,3 +144,64 @@ func isTCPProxyFilter(filters []*envoy_api_v2_listener.Filter) bool {
 	}
 	return false
 }
+
+// secureListener returns the name of the listener the secure virtual hosts
+// visited are served by.
+func (v *listenerVisitor) secureListener() string {
+	if v.secure != "" {
+		return v.secure
+	}
+	return ENVOY_HTTPS_LISTENER
+}
+
+// visitAdditionalListener adds the Envoy listener of an additional listener:
+// either a plain HTTP one routing with the route configuration of the same
+// name, or one with a TLS filter chain per secure virtual host, like the
+// HTTPS listener.
+func (v *listenerVisitor) visitAdditionalListener(l *dag.Listener) {
+	address := l.Address
+	if address == "" {
+		address = DEFAULT_HTTP_LISTENER_ADDRESS
+	}
+
+	secure := false
+	var virtualhosts []*dag.VirtualHost
+	for _, vh := range l.VirtualHosts {
+		switch vh := vh.(type) {
+		case *dag.SecureVirtualHost:
+			secure = true
+		case *dag.VirtualHost:
+			virtualhosts = append(virtualhosts, vh)
+		}
+	}
+	if !secure {
+		cm := envoy.HTTPConnectionManagerBuilder().
+			DefaultFilters().
+			RouteConfigName(l.Name).
+			MetricsPrefix(l.Name).
+			AccessLoggers(v.ListenerVisitorConfig.newInsecureVirtualHostsAccessLog(virtualhosts)).
+			RequestTimeout(v.ListenerVisitorConfig.requestTimeout()).
+			Get()
+		v.listeners[l.Name] = envoy.Listener(
+			l.Name,
+			address,
+			l.Port,
+			append(proxyProtocol(v.UseProxyProto), CustomListenerFilters()...),
+			cm,
+		)
+		return
+	}
+
+	v.listeners[l.Name] = envoy.Listener(
+		l.Name,
+		address,
+		l.Port,
+		append(secureProxyProtocol(v.UseProxyProto), CustomListenerFilters()...),
+	)
+	v.secure = l.Name
+	l.Visit(v.visit)
+	v.secure = ""
+
+	// sort the filter chains to ensure that the LDS entries are identical.
+	sort.Stable(sorter.For(v.listeners[l.Name].FilterChains))
+}
@@ -16,6 +16,7 @@ package contour
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/projectcontour/contour/adobe"
 	"github.com/projectcontour/contour/internal/k8s"
@@ -139,7 +140,7 @@ func TestListenerVisit(t *testing.T) {
 	}
 
 	fallbackCertFilter := envoy.HTTPConnectionManagerBuilder().
-		MetricsPrefix(ENVOY_HTTPS_LISTENER).
+		MetricsPrefix(ENVOY_FALLBACK_ROUTECONFIG).
 		RouteConfigName(ENVOY_FALLBACK_ROUTECONFIG).
 		AccessLoggers(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG)).
 		Get()
@@ -503,6 +504,85 @@ func TestListenerVisit(t *testing.T) {
 				),
 			}),
 		},
+		"ingressroute with aliases": {
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn:    "www.example.com",
+							Aliases: []string{"example.com"},
+							TLS: &ingressroutev1.TLS{
+								SecretName: "secret",
+							},
+						},
+						Routes: []ingressroutev1.Route{
+							{
+								Services: []ingressroutev1.Service{
+									{
+										Name: "backend",
+										Port: 80,
+									},
+								},
+							},
+						},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(EC_CERTIFICATE, EC_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     80,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:         ENVOY_HTTP_LISTENER,
+				Address:      envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"example.com", "www.example.com"},
+					},
+					TransportSocket: envoy.DownstreamTLSTransportSocket(
+						envoy.DownstreamTLSContext(&dag.Secret{
+							Object: &v1.Secret{
+								ObjectMeta: metav1.ObjectMeta{
+									Name:      "secret",
+									Namespace: "default",
+								},
+								Type: v1.SecretTypeTLS,
+								Data: secretdata(EC_CERTIFICATE, EC_PRIVATE_KEY),
+							},
+						}, envoy_api_v2_auth.TlsParameters_TLSv1_1, nil, "h2", "http/1.1"),
+					),
+					Filters: envoy.Filters(httpsFilterFor("www.example.com")),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			}),
+		},
 		"ingress with allow-http: false": {
 			objs: []interface{}{
 				&v1beta1.Ingress{
@@ -1083,6 +1163,81 @@ func TestListenerVisit(t *testing.T) {
 				),
 			}),
 		},
+		"cipher suites and ecdh curves from config overridden by ingressroute": {
+			ListenerVisitorConfig: ListenerVisitorConfig{
+				CipherSuites: []string{"ECDHE-RSA-AES256-GCM-SHA384"},
+				ECDHCurves:   []string{"P-256"},
+			},
+			objs: []interface{}{
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "simple",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "www.example.com",
+							TLS: &ingressroutev1.TLS{
+								SecretName:   "secret",
+								CipherSuites: []string{"[ECDHE-RSA-AES128-GCM-SHA256|ECDHE-RSA-CHACHA20-POLY1305]"},
+							},
+						},
+						Routes: []ingressroutev1.Route{
+							{
+								Services: []ingressroutev1.Service{
+									{
+										Name: "backend",
+										Port: 80,
+									},
+								},
+							},
+						},
+					},
+				},
+				&v1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "secret",
+						Namespace: "default",
+					},
+					Type: "kubernetes.io/tls",
+					Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Name:     "http",
+							Protocol: "TCP",
+							Port:     80,
+						}},
+					},
+				},
+			},
+			want: listenermap(&v2.Listener{
+				Name:         ENVOY_HTTP_LISTENER,
+				Address:      envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"www.example.com"},
+					},
+					TransportSocket: transportSocketWithParams("secret",
+						[]string{"[ECDHE-RSA-AES128-GCM-SHA256|ECDHE-RSA-CHACHA20-POLY1305]"}, // the ingressroute cipher suites
+						[]string{"P-256"}, // the configured curves
+						"h2", "http/1.1"),
+					Filters: envoy.Filters(httpsFilterFor("www.example.com")),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			}),
+		},
 		"httpproxy with fallback certificate": {
 			fallbackCertificate: &k8s.FullName{
 				Name:      "fallbacksecret",
@@ -1448,6 +1603,24 @@ func transportSocket(secretname string, tlsMinProtoVersion envoy_api_v2_auth.Tls
 	)
 }
 
+func transportSocketWithParams(secretname string, cipherSuites, ecdhCurves []string, alpnprotos ...string) *envoy_api_v2_core.TransportSocket {
+	secret := &dag.Secret{
+		Object: &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretname,
+				Namespace: "default",
+			},
+			Type: v1.SecretTypeTLS,
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+	}
+	return envoy.DownstreamTLSTransportSocket(
+		envoy.DownstreamTLSParams(
+			envoy.DownstreamTLSContext(secret, envoy_api_v2_auth.TlsParameters_TLSv1_1, nil, alpnprotos...),
+			cipherSuites, ecdhCurves),
+	)
+}
+
 func listenermap(listeners ...*v2.Listener) map[string]*v2.Listener {
 	m := make(map[string]*v2.Listener)
 	for _, l := range listeners {
@@ -1455,3 +1628,671 @@ func listenermap(listeners ...*v2.Listener) map[string]*v2.Listener {
 	}
 	return m
 }
+
+func TestAdobeListenerVisitAdditionalListeners(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+	admin := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "admin",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "admin.example.com",
+				Port: 9080,
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+	mqtt := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mqtt",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "mqtt.example.com",
+				Port: 8883,
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	builder := dag.Builder{
+		Listeners: []dag.AdditionalListener{
+			{Name: "admin", Address: "127.0.0.1", Port: 9080, Protocol: dag.ListenerProtocolHTTP},
+			{Name: "mqtt", Port: 8883, Protocol: dag.ListenerProtocolHTTPS},
+		},
+		Source: dag.KubernetesCache{
+			FieldLogger: testLogger(t),
+		},
+	}
+	for _, o := range []interface{}{service, admin, mqtt} {
+		builder.Source.Insert(o)
+	}
+
+	tcpproxy := &dag.TCPProxy{
+		Clusters: []*dag.Cluster{{
+			Upstream: &dag.Service{
+				Name:        service.Name,
+				Namespace:   service.Namespace,
+				ServicePort: &service.Spec.Ports[0],
+			},
+		}},
+	}
+
+	want := listenermap(&v2.Listener{
+		Name:         "admin",
+		Address:      envoy.SocketAddress("127.0.0.1", 9080),
+		FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager("admin", envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), 0)),
+	}, &v2.Listener{
+		Name:    "mqtt",
+		Address: envoy.SocketAddress("0.0.0.0", 8883),
+		FilterChains: []*envoy_api_v2_listener.FilterChain{{
+			FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+				ServerNames: []string{"mqtt.example.com"},
+			},
+			Filters: envoy.Filters(envoy.TCPProxy("mqtt", tcpproxy, envoy.FileAccessLogEnvoy(DEFAULT_HTTPS_ACCESS_LOG))),
+		}},
+		ListenerFilters: envoy.ListenerFilters(
+			envoy.TLSInspector(),
+		),
+	})
+
+	got := visitListeners(builder.Build(), new(ListenerVisitorConfig))
+	assert.Equal(t, want, got)
+}
+
+func TestAdobeListenerVisitPassthroughWildcards(t *testing.T) {
+	service := func(name string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Name:     "https",
+					Protocol: "TCP",
+					Port:     8443,
+				}},
+			},
+		}
+	}
+	stable, canary := service("stable"), service("canary")
+	ir := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "passthrough",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn:    "*.example.com",
+				Aliases: []string{"example.com", "*.example.net"},
+				TLS: &ingressroutev1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name:   "stable",
+					Port:   8443,
+					Weight: 95,
+					HealthCheck: &ingressroutev1.HealthCheck{
+						IntervalSeconds: 5,
+					},
+				}, {
+					Name:   "canary",
+					Port:   8443,
+					Weight: 5,
+					HealthCheck: &ingressroutev1.HealthCheck{
+						IntervalSeconds: 5,
+					},
+				}},
+			},
+		},
+	}
+
+	cluster := func(s *v1.Service, weight uint32) *dag.Cluster {
+		return &dag.Cluster{
+			Upstream: &dag.Service{
+				Name:        s.Name,
+				Namespace:   s.Namespace,
+				ServicePort: &s.Spec.Ports[0],
+			},
+			Weight: weight,
+		}
+	}
+	tcpproxy := &dag.TCPProxy{
+		Clusters: []*dag.Cluster{cluster(stable, 95), cluster(canary, 5)},
+	}
+
+	want := listenermap(&v2.Listener{
+		Name:    ENVOY_HTTPS_LISTENER,
+		Address: envoy.SocketAddress("0.0.0.0", 8443),
+		FilterChains: []*envoy_api_v2_listener.FilterChain{{
+			FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+				ServerNames: []string{"*.example.com", "*.example.net", "example.com"},
+			},
+			Filters: envoy.Filters(envoy.TCPProxy(ENVOY_HTTPS_LISTENER, tcpproxy, envoy.FileAccessLogEnvoy(DEFAULT_HTTPS_ACCESS_LOG))),
+		}},
+		ListenerFilters: envoy.ListenerFilters(
+			envoy.TLSInspector(),
+		),
+	})
+
+	got := visitListeners(buildDAG(t, stable, canary, ir), new(ListenerVisitorConfig))
+	assert.Equal(t, want, got)
+}
+
+func TestAdobeListenerVisitAccessLogSink(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+	www := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "www",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "www.example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+	mqtt := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mqtt",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "mqtt.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			},
+		},
+	}
+
+	tcpproxy := &dag.TCPProxy{
+		Clusters: []*dag.Cluster{{
+			Upstream: &dag.Service{
+				Name:        service.Name,
+				Namespace:   service.Namespace,
+				ServicePort: &service.Spec.Ports[0],
+			},
+		}},
+	}
+
+	tests := map[string]struct {
+		sink    *AccessLogSink
+		cluster string
+	}{
+		"access log service": {
+			sink:    &AccessLogSink{Address: "als.logging", Port: 9001, LogName: "edge"},
+			cluster: envoy.AccessLogServiceCluster,
+		},
+		"builtin access log service": {
+			sink:    &AccessLogSink{LogName: "edge"},
+			cluster: "contour",
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			want := listenermap(&v2.Listener{
+				Name:         ENVOY_HTTP_LISTENER,
+				Address:      envoy.SocketAddress("0.0.0.0", 8080),
+				FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, envoy.GRPCAccessLog(tc.cluster, "edge", envoy.DefaultFields, nil), 0)),
+			}, &v2.Listener{
+				Name:    ENVOY_HTTPS_LISTENER,
+				Address: envoy.SocketAddress("0.0.0.0", 8443),
+				FilterChains: []*envoy_api_v2_listener.FilterChain{{
+					FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+						ServerNames: []string{"mqtt.example.com"},
+					},
+					Filters: envoy.Filters(envoy.TCPProxy(ENVOY_HTTPS_LISTENER, tcpproxy, envoy.TCPGRPCAccessLog(tc.cluster, "edge"))),
+				}},
+				ListenerFilters: envoy.ListenerFilters(
+					envoy.TLSInspector(),
+				),
+			})
+
+			got := visitListeners(buildDAG(t, service, www, mqtt), &ListenerVisitorConfig{
+				AccessLogSink: tc.sink,
+			})
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestAdobeListenerVisitAccessLogFilter(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Type: "kubernetes.io/tls",
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+	ingressroute := func(name, fqdn string, tls *ingressroutev1.TLS, accessLog *ingressroutev1.AccessLog) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn:      fqdn,
+					TLS:       tls,
+					AccessLog: accessLog,
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+	}
+	serverErrors := &ingressroutev1.AccessLog{
+		Filter: &ingressroutev1.AccessLogFilter{
+			StatusCodes: []string{"500-599"},
+		},
+	}
+	sampled := &ingressroutev1.AccessLog{
+		Filter: &ingressroutev1.AccessLogFilter{
+			SamplePercent: 10,
+		},
+	}
+	objs := []interface{}{
+		service,
+		secret,
+		ingressroute("www", "www.example.com", nil, serverErrors),
+		ingressroute("api", "api.example.com", nil, nil),
+		ingressroute("secure", "secure.example.com", &ingressroutev1.TLS{SecretName: "secret"}, sampled),
+	}
+
+	global := &dag.AccessLogFilter{MinDuration: time.Second}
+	serverErrorsFilter := &dag.AccessLogFilter{StatusCodes: []dag.StatusCodeRange{{Min: 500, Max: 599}}}
+	sampledFilter := &dag.AccessLogFilter{SamplePercent: 10}
+
+	insecureAccessLog := append(
+		envoy.FilterAccessLog(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), envoy.AndAccessLogFilter(
+			envoy.AuthorityAccessLogFilter([]string{"secure.example.com", "www.example.com"}, true),
+			envoy.AccessLogFilter(global, envoy.AccessLogListenerScope),
+		)),
+		append(
+			envoy.FilterAccessLog(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), envoy.AndAccessLogFilter(
+				envoy.AuthorityAccessLogFilter([]string{"secure.example.com"}, false),
+				envoy.AccessLogFilter(sampledFilter, envoy.AccessLogVirtualHostScope),
+			)),
+			envoy.FilterAccessLog(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), envoy.AndAccessLogFilter(
+				envoy.AuthorityAccessLogFilter([]string{"www.example.com"}, false),
+				envoy.AccessLogFilter(serverErrorsFilter, envoy.AccessLogVirtualHostScope),
+			))...,
+		)...,
+	)
+
+	want := listenermap(&v2.Listener{
+		Name:         ENVOY_HTTP_LISTENER,
+		Address:      envoy.SocketAddress("0.0.0.0", 8080),
+		FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, insecureAccessLog, 0)),
+	}, &v2.Listener{
+		Name:    ENVOY_HTTPS_LISTENER,
+		Address: envoy.SocketAddress("0.0.0.0", 8443),
+		FilterChains: []*envoy_api_v2_listener.FilterChain{{
+			FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+				ServerNames: []string{"secure.example.com"},
+			},
+			TransportSocket: transportSocket("secret", envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+			Filters: envoy.Filters(envoy.HTTPConnectionManagerBuilder().
+				DefaultFilters().
+				RouteConfigName(ENVOY_HTTPS_LISTENER).
+				MetricsPrefix(ENVOY_HTTPS_LISTENER).
+				AccessLoggers(envoy.FilterAccessLog(envoy.FileAccessLogEnvoy(DEFAULT_HTTPS_ACCESS_LOG), envoy.AccessLogFilter(sampledFilter, envoy.AccessLogVirtualHostScope))).
+				Get()),
+		}},
+		ListenerFilters: envoy.ListenerFilters(
+			envoy.TLSInspector(),
+		),
+	})
+
+	got := visitListeners(buildDAG(t, objs...), &ListenerVisitorConfig{
+		AccessLogFilter: global,
+	})
+	assert.Equal(t, want, got)
+}
+
+func TestAdobeListenerVisitAccessLogOverride(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+		},
+		Type: "kubernetes.io/tls",
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+	ingressroute := func(name, fqdn string, tls *ingressroutev1.TLS, accessLog *ingressroutev1.AccessLog) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn:      fqdn,
+					TLS:       tls,
+					AccessLog: accessLog,
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+	}
+	objs := []interface{}{
+		service,
+		secret,
+		ingressroute("tenant", "tenant.example.com", nil, &ingressroutev1.AccessLog{
+			Path:    "/var/log/envoy/tenant.log",
+			Fields:  []string{"@timestamp", "path"},
+			Headers: []string{"x-adobe-tenant"},
+		}),
+		ingressroute("www", "www.example.com", nil, nil),
+		ingressroute("secure", "secure.example.com", &ingressroutev1.TLS{SecretName: "secret"}, &ingressroutev1.AccessLog{
+			Path: "/var/log/envoy/secure.log",
+		}),
+	}
+
+	insecureAccessLog := append(
+		envoy.FilterAccessLog(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG),
+			envoy.AuthorityAccessLogFilter([]string{"secure.example.com", "tenant.example.com"}, true),
+		),
+		append(
+			envoy.FilterAccessLog(envoy.FileAccessLogEnvoy("/var/log/envoy/secure.log"),
+				envoy.AuthorityAccessLogFilter([]string{"secure.example.com"}, false),
+			),
+			envoy.FilterAccessLog(envoy.FileAccessLogJSONHeaders("/var/log/envoy/tenant.log", []string{"@timestamp", "path"}, []string{"X-Adobe-Tenant"}),
+				envoy.AuthorityAccessLogFilter([]string{"tenant.example.com"}, false),
+			)...,
+		)...,
+	)
+
+	want := listenermap(&v2.Listener{
+		Name:         ENVOY_HTTP_LISTENER,
+		Address:      envoy.SocketAddress("0.0.0.0", 8080),
+		FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, insecureAccessLog, 0)),
+	}, &v2.Listener{
+		Name:    ENVOY_HTTPS_LISTENER,
+		Address: envoy.SocketAddress("0.0.0.0", 8443),
+		FilterChains: []*envoy_api_v2_listener.FilterChain{{
+			FilterChainMatch: &envoy_api_v2_listener.FilterChainMatch{
+				ServerNames: []string{"secure.example.com"},
+			},
+			TransportSocket: transportSocket("secret", envoy_api_v2_auth.TlsParameters_TLSv1_1, "h2", "http/1.1"),
+			Filters: envoy.Filters(envoy.HTTPConnectionManagerBuilder().
+				DefaultFilters().
+				RouteConfigName(ENVOY_HTTPS_LISTENER).
+				MetricsPrefix(ENVOY_HTTPS_LISTENER).
+				AccessLoggers(envoy.FileAccessLogEnvoy("/var/log/envoy/secure.log")).
+				Get()),
+		}},
+		ListenerFilters: envoy.ListenerFilters(
+			envoy.TLSInspector(),
+		),
+	})
+
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			FieldLogger: testLogger(t),
+		},
+		AccessLogPaths: []string{"/var/log/envoy/"},
+	}
+	for _, o := range objs {
+		adobe.AdobefyObject(o)
+		builder.Source.Insert(o)
+	}
+	got := visitListeners(builder.Build(), new(ListenerVisitorConfig))
+	assert.Equal(t, want, got)
+}
+
+func TestAdobeListenerVisitAccessLogWildcardOverride(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+	ingressroute := func(name, fqdn string, accessLog *ingressroutev1.AccessLog) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn:      fqdn,
+					AccessLog: accessLog,
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+	}
+	objs := []interface{}{
+		service,
+		ingressroute("wildcard", "*.example.com", &ingressroutev1.AccessLog{
+			Path: "/var/log/envoy/wildcard.log",
+		}),
+		ingressroute("www", "www.example.com", &ingressroutev1.AccessLog{
+			Path: "/var/log/envoy/www.log",
+		}),
+		ingressroute("api", "api.example.com", nil),
+	}
+
+	// the requests for www.example.com and api.example.com, served by
+	// their own virtual hosts, are not logged by the wildcard
+	insecureAccessLog := append(
+		envoy.FilterAccessLog(envoy.FileAccessLogEnvoy(DEFAULT_HTTP_ACCESS_LOG), envoy.AndAccessLogFilter(
+			envoy.AuthorityAccessLogFilter([]string{"www.example.com"}, true),
+			envoy.OrAccessLogFilter(
+				envoy.AuthorityAccessLogFilter([]string{"*.example.com"}, true),
+				envoy.AuthorityAccessLogFilter([]string{"api.example.com", "www.example.com"}, false),
+			),
+		)),
+		append(
+			envoy.FilterAccessLog(envoy.FileAccessLogEnvoy("/var/log/envoy/wildcard.log"), envoy.AndAccessLogFilter(
+				envoy.AuthorityAccessLogFilter([]string{"*.example.com"}, false),
+				envoy.AuthorityAccessLogFilter([]string{"api.example.com", "www.example.com"}, true),
+			)),
+			envoy.FilterAccessLog(envoy.FileAccessLogEnvoy("/var/log/envoy/www.log"),
+				envoy.AuthorityAccessLogFilter([]string{"www.example.com"}, false),
+			)...,
+		)...,
+	)
+
+	want := listenermap(&v2.Listener{
+		Name:         ENVOY_HTTP_LISTENER,
+		Address:      envoy.SocketAddress("0.0.0.0", 8080),
+		FilterChains: envoy.FilterChains(envoy.HTTPConnectionManager(ENVOY_HTTP_LISTENER, insecureAccessLog, 0)),
+	})
+
+	builder := dag.Builder{
+		Source: dag.KubernetesCache{
+			FieldLogger: testLogger(t),
+		},
+		AccessLogPaths: []string{"/var/log/envoy/"},
+	}
+	for _, o := range objs {
+		adobe.AdobefyObject(o)
+		builder.Source.Insert(o)
+	}
+	got := visitListeners(builder.Build(), new(ListenerVisitorConfig))
+	assert.Equal(t, want, got)
+}
+
+func TestAdobeListenerVisitSecondaryCertificate(t *testing.T) {
+	service := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:     "http",
+				Protocol: "TCP",
+				Port:     8080,
+			}},
+		},
+	}
+	rsa := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rsa",
+			Namespace: "default",
+		},
+		Type: "kubernetes.io/tls",
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+	ecdsa := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ecdsa",
+			Namespace: "default",
+		},
+		Type: "kubernetes.io/tls",
+		Data: secretdata(EC_CERTIFICATE, EC_PRIVATE_KEY),
+	}
+	ir := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secure",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "secure.example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName:          rsa.Name,
+					SecondarySecretName: ecdsa.Name,
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	got := visitListeners(buildDAG(t, service, rsa, ecdsa, ir), new(ListenerVisitorConfig))
+	listener, ok := got[ENVOY_HTTPS_LISTENER]
+	if !ok {
+		t.Fatalf("expected the %s listener", ENVOY_HTTPS_LISTENER)
+	}
+	if err := listener.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, len(listener.FilterChains))
+
+	tls := envoy.GetDownstreamTLSContext(listener.FilterChains[0])
+	if err := tls.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, len(tls.CommonTlsContext.TlsCertificateSdsSecretConfigs))
+	assert.Equal(t, [][]byte{[]byte(CERTIFICATE), []byte(EC_CERTIFICATE)}, [][]byte{
+		tls.CommonTlsContext.TlsCertificates[0].CertificateChain.GetInlineBytes(),
+		tls.CommonTlsContext.TlsCertificates[1].CertificateChain.GetInlineBytes(),
+	})
+}
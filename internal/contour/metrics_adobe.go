@@ -0,0 +1,54 @@
+package contour
+
+import (
+	"time"
+
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/metrics"
+)
+
+// calculateCertificateExpiry returns the expiry of the earliest expiring
+// certificate of each Secret the DAG serves: the certificates of the secure
+// vhosts and their fallback, the client certificates presented to upstreams
+// and the CA bundles validating peers.
+func calculateCertificateExpiry(root dag.Vertex) map[metrics.CertificateMeta]time.Time {
+	expiries := make(map[metrics.CertificateMeta]time.Time)
+	record := func(sec *dag.Secret, notAfter time.Time, ok bool) {
+		if !ok {
+			return
+		}
+		meta := metrics.CertificateMeta{Namespace: sec.Namespace(), Name: sec.Name()}
+		if cur, seen := expiries[meta]; !seen || notAfter.Before(cur) {
+			expiries[meta] = notAfter
+		}
+	}
+	cert := func(sec *dag.Secret) {
+		if sec != nil {
+			notAfter, ok := sec.NotAfter()
+			record(sec, notAfter, ok)
+		}
+	}
+	ca := func(pvc *dag.PeerValidationContext) {
+		if pvc != nil && pvc.CACertificate != nil {
+			notAfter, ok := pvc.CACertificate.CANotAfter()
+			record(pvc.CACertificate, notAfter, ok)
+		}
+	}
+
+	var visit func(dag.Vertex)
+	visit = func(vertex dag.Vertex) {
+		switch vertex := vertex.(type) {
+		case *dag.SecureVirtualHost:
+			cert(vertex.Secret)
+			cert(vertex.SecondarySecret)
+			cert(vertex.FallbackCertificate)
+			ca(vertex.DownstreamValidation)
+		case *dag.Cluster:
+			cert(vertex.ClientCertificate)
+			ca(vertex.UpstreamValidation)
+		}
+		vertex.Visit(visit)
+	}
+	visit(root)
+	return expiries
+}
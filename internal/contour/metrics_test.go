@@ -15,6 +15,7 @@ package contour
 
 import (
 	"testing"
+	"time"
 
 	"github.com/projectcontour/contour/adobe"
 
@@ -1034,3 +1035,44 @@ func TestIngressRouteMetrics(t *testing.T) {
 		},
 	})
 }
+
+func TestCertificateExpiryMetrics(t *testing.T) {
+	secret := func(name string, data map[string][]byte) *dag.Secret {
+		return &dag.Secret{
+			Object: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: data,
+			},
+		}
+	}
+	cert := secret("cert", secretdata(CERTIFICATE, RSA_PRIVATE_KEY))
+	ca := secret("ca", map[string][]byte{dag.CACertificateKey: []byte(CERTIFICATE)})
+
+	// CERTIFICATE expires at 2029-12-02T01:34:33Z.
+	notAfter := time.Date(2029, 12, 2, 1, 34, 33, 0, time.UTC)
+
+	svh := &dag.SecureVirtualHost{
+		Secret:              cert,
+		FallbackCertificate: cert,
+		TCPProxy: &dag.TCPProxy{
+			Clusters: []*dag.Cluster{{
+				Upstream: &dag.Service{},
+				UpstreamValidation: &dag.PeerValidationContext{
+					CACertificate: ca,
+					SubjectName:   "example.com",
+				},
+			}},
+		},
+	}
+
+	got := calculateCertificateExpiry(svh)
+	want := map[metrics.CertificateMeta]time.Time{
+		{Namespace: "default", Name: "cert"}: notAfter,
+		{Namespace: "default", Name: "ca"}:   notAfter,
+	}
+	assert.Equal(t, want, got)
+}
@@ -91,6 +91,9 @@ func (*RouteCache) TypeURL() string { return resource.RouteType }
 
 type routeVisitor struct {
 	routes map[string]*v2.RouteConfiguration
+
+	// Adobe - the name of the additional listener visited, if any
+	listener string
 }
 
 func visitRoutes(root dag.Vertex) map[string]*v2.RouteConfiguration {
@@ -171,7 +174,17 @@ func (v *routeVisitor) onVirtualHost(vh *dag.VirtualHost) {
 	if len(routes) > 0 {
 		sortRoutes(routes)
 
-		v.routes[ENVOY_HTTP_LISTENER].VirtualHosts = append(v.routes[ENVOY_HTTP_LISTENER].VirtualHosts,
+		// Adobe - the route configuration of the additional listener
+		name := ENVOY_HTTP_LISTENER
+		if v.listener != "" {
+			name = v.listener
+		}
+
+		if _, ok := v.routes[name]; !ok {
+			v.routes[name] = envoy.RouteConfiguration(name)
+		}
+
+		v.routes[name].VirtualHosts = append(v.routes[name].VirtualHosts,
 			envoy.AdobeVirtualHost(vh.Name, vh.HostNames, routes...))
 	}
 }
@@ -208,6 +221,10 @@ func (v *routeVisitor) onSecureVirtualHost(svh *dag.SecureVirtualHost) {
 		// undo https://github.com/projectcontour/contour/pull/2381
 		// name := path.Join("https", svh.VirtualHost.Name)
 		name := ENVOY_HTTPS_LISTENER
+		// Adobe - the route configuration of the additional listener
+		if v.listener != "" {
+			name = v.listener
+		}
 
 		if _, ok := v.routes[name]; !ok {
 			v.routes[name] = envoy.RouteConfiguration(name)
@@ -219,7 +236,8 @@ func (v *routeVisitor) onSecureVirtualHost(svh *dag.SecureVirtualHost) {
 		// A fallback route configuration contains routes for all the vhosts that have the fallback certificate enabled.
 		// When a request is received, the default TLS filterchain will accept the connection,
 		// and this routing table in RDS defines where the request proxies next.
-		if svh.FallbackCertificate != nil {
+		// Adobe - only on the HTTPS listener.
+		if svh.FallbackCertificate != nil && v.listener == "" {
 			// Add fallback route if not already
 			if _, ok := v.routes[ENVOY_FALLBACK_ROUTECONFIG]; !ok {
 				v.routes[ENVOY_FALLBACK_ROUTECONFIG] = envoy.RouteConfiguration(ENVOY_FALLBACK_ROUTECONFIG)
@@ -234,6 +252,8 @@ func (v *routeVisitor) onSecureVirtualHost(svh *dag.SecureVirtualHost) {
 func (v *routeVisitor) visit(vertex dag.Vertex) {
 	switch l := vertex.(type) {
 	case *dag.Listener:
+		v.listener = l.Name
+		defer func() { v.listener = "" }()
 		l.Visit(func(vertex dag.Vertex) {
 			switch vh := vertex.(type) {
 			case *dag.VirtualHost:
@@ -277,6 +277,54 @@ func TestRouteVisit(t *testing.T) {
 				),
 			),
 		},
+		"one http only wildcard httpproxy": {
+			objs: []interface{}{
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "wildcard",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "*.example.com",
+						},
+						Routes: []projcontour.Route{{
+							Services: []projcontour.Service{{
+								Name: "backend",
+								Port: 80,
+							}},
+						}},
+					},
+				},
+				&v1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "backend",
+						Namespace: "default",
+					},
+					Spec: v1.ServiceSpec{
+						Ports: []v1.ServicePort{{
+							Protocol:   "TCP",
+							Port:       80,
+							TargetPort: intstr.FromInt(8080),
+						}},
+					},
+				},
+			},
+			want: routeConfigurations(
+				envoy.RouteConfiguration("ingress_http",
+					&envoy_api_v2_route.VirtualHost{
+						Name: "*.example.com",
+						// no port-qualified domain for a wildcard
+						Domains: []string{"*.example.com"},
+						Routes: []*envoy_api_v2_route.Route{{
+							Match:  routePrefix("/"),
+							Action: routecluster("default/backend/80/da39a3ee5e"),
+						}},
+						RetryPolicy: envoy.VirtualHost("*.example.com").RetryPolicy,
+					},
+				),
+			),
+		},
 		"default backend ingress with secret": {
 			objs: []interface{}{
 				&v1beta1.Ingress{
@@ -3012,3 +3060,84 @@ func withMirrorPolicy(route *envoy_api_v2_route.Route_Route, mirror string) *env
 	}}
 	return route
 }
+
+func TestAdobeRouteVisitAdditionalListeners(t *testing.T) {
+	proxy := func(name string, port int, secret string) *projcontour.HTTPProxy {
+		p := &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn: name + ".example.com",
+					Port: port,
+				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+		if secret != "" {
+			p.Spec.VirtualHost.TLS = &projcontour.TLS{SecretName: secret}
+		}
+		return p
+	}
+
+	builder := dag.Builder{
+		Listeners: []dag.AdditionalListener{
+			{Name: "admin", Port: 9080, Protocol: dag.ListenerProtocolHTTP},
+			{Name: "internal", Port: 9443, Protocol: dag.ListenerProtocolHTTPS},
+		},
+		Source: dag.KubernetesCache{
+			FieldLogger: testLogger(t),
+		},
+	}
+	for _, o := range []interface{}{
+		&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Protocol:   "TCP",
+					Port:       8080,
+					TargetPort: intstr.FromInt(8080),
+				}},
+			},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "secret",
+				Namespace: "default",
+			},
+			Type: "kubernetes.io/tls",
+			Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		},
+		proxy("www", 0, ""),
+		proxy("admin", 9080, ""),
+		proxy("internal", 9443, "secret"),
+	} {
+		builder.Source.Insert(o)
+	}
+
+	// the virtual hosts of each route configuration
+	got := make(map[string][]string)
+	for name, rc := range visitRoutes(builder.Build()) {
+		got[name] = []string{}
+		for _, vh := range rc.VirtualHosts {
+			got[name] = append(got[name], vh.Name)
+		}
+	}
+
+	want := map[string][]string{
+		ENVOY_HTTP_LISTENER: {"www.example.com"},
+		"admin":             {"admin.example.com"},
+		"internal":          {"internal.example.com"},
+	}
+	assert.Equal(t, want, got)
+}
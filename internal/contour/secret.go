@@ -86,16 +86,28 @@ func visitSecrets(root dag.Vertex) map[string]*envoy_api_v2_auth.Secret {
 }
 
 func (v *secretVisitor) visit(vertex dag.Vertex) {
-	switch svh := vertex.(type) {
+	switch vertex := vertex.(type) {
 	case *dag.SecureVirtualHost:
-		if svh.Secret != nil {
-			name := envoy.Secretname(svh.Secret)
-			if _, ok := v.secrets[name]; !ok {
-				s := envoy.Secret(svh.Secret)
-				v.secrets[s.Name] = s
-			}
+		if vertex.Secret != nil {
+			v.addSecret(vertex.Secret)
 		}
+		// Adobe - visit the clusters of the secure vhost
+		vertex.Visit(v.visit)
+	// Adobe - client certificates presented to the upstreams
+	case *dag.Cluster:
+		if vertex.ClientCertificate != nil {
+			v.addSecret(vertex.ClientCertificate)
+		}
+		vertex.Visit(v.visit)
 	default:
 		vertex.Visit(v.visit)
 	}
 }
+
+func (v *secretVisitor) addSecret(secret *dag.Secret) {
+	name := envoy.Secretname(secret)
+	if _, ok := v.secrets[name]; !ok {
+		s := envoy.Secret(secret)
+		v.secrets[s.Name] = s
+	}
+}
@@ -0,0 +1,241 @@
+package dag
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// AccessLog overrides the access log of a virtual host. The fields not set
+// are configured globally.
+type AccessLog struct {
+	// Filter selects the requests logged, in place of the filter
+	// configured globally. If nil, the global filter applies.
+	Filter *AccessLogFilter
+
+	// Path is the file the access log is written to.
+	Path string
+
+	// Format is the access log format, either envoy or json.
+	Format string
+
+	// Fields are the JSON fields logged.
+	Fields []string
+
+	// Headers are the request headers logged in addition to the
+	// fields, in canonical form.
+	Headers []string
+}
+
+// AccessLogFilter selects the requests logged, which must satisfy all the
+// conditions set.
+type AccessLogFilter struct {
+	// StatusCodes are the ranges of the response codes logged.
+	StatusCodes []StatusCodeRange
+
+	// MinDuration is the duration a request lasts at least to be logged.
+	MinDuration time.Duration
+
+	// SamplePercent is the percentage of the requests logged, if not 0.
+	SamplePercent uint32
+
+	// HeaderConditions are the conditions on the request headers.
+	HeaderConditions []HeaderCondition
+}
+
+// StatusCodeRange is a range of response codes, Min and Max included.
+type StatusCodeRange struct {
+	Min, Max uint32
+}
+
+// NewAccessLogFilter returns the filter of the access log, or nil if it
+// sets no conditions.
+func NewAccessLogFilter(statusCodes []string, minDuration time.Duration, samplePercent uint32, headers []projcontour.HeaderCondition) (*AccessLogFilter, error) {
+	var filter AccessLogFilter
+	for _, codes := range statusCodes {
+		r, err := parseStatusCodeRange(codes)
+		if err != nil {
+			return nil, err
+		}
+		filter.StatusCodes = append(filter.StatusCodes, r)
+	}
+
+	if minDuration < 0 {
+		return nil, fmt.Errorf("minimum duration %v must not be negative", minDuration)
+	}
+	filter.MinDuration = minDuration
+
+	if samplePercent > 100 {
+		return nil, fmt.Errorf("sample percent %d must be between 1 and 100", samplePercent)
+	}
+	filter.SamplePercent = samplePercent
+
+	var conds []projcontour.Condition
+	for i := range headers {
+		if err := validAccessLogHeaderCondition(headers[i]); err != nil {
+			return nil, err
+		}
+		conds = append(conds, projcontour.Condition{Header: &headers[i]})
+	}
+	filter.HeaderConditions = mergeHeaderConditions(conds)
+
+	if len(filter.StatusCodes) == 0 && filter.MinDuration == 0 && filter.SamplePercent == 0 && len(filter.HeaderConditions) == 0 {
+		return nil, nil
+	}
+	return &filter, nil
+}
+
+// parseStatusCodeRange parses a single response code, such as 503, or a
+// range of them, such as 500-599.
+func parseStatusCodeRange(codes string) (StatusCodeRange, error) {
+	min, max := codes, codes
+	if i := strings.IndexByte(codes, '-'); i >= 0 {
+		min, max = codes[:i], codes[i+1:]
+	}
+	var r StatusCodeRange
+	for _, code := range []struct {
+		s string
+		v *uint32
+	}{{min, &r.Min}, {max, &r.Max}} {
+		v, err := strconv.ParseUint(code.s, 10, 32)
+		if err != nil || v < 100 || v > 599 {
+			return r, fmt.Errorf("status code %q must be between 100 and 599", code.s)
+		}
+		*code.v = uint32(v)
+	}
+	if r.Min > r.Max {
+		return r, fmt.Errorf("status code range %q is empty", codes)
+	}
+	return r, nil
+}
+
+// validAccessLogHeaderCondition ensures a header condition names a header
+// and sets exactly one match.
+func validAccessLogHeaderCondition(h projcontour.HeaderCondition) error {
+	if h.Name == "" {
+		return errors.New("header condition must name a header")
+	}
+	matches := 0
+	for _, set := range []bool{h.Present, h.Contains != "", h.NotContains != "", h.Exact != "", h.NotExact != ""} {
+		if set {
+			matches++
+		}
+	}
+	if matches != 1 {
+		return fmt.Errorf("header condition %q must set one of present, contains, notcontains, exact or notexact", h.Name)
+	}
+	return nil
+}
+
+// ingressrouteAccessLog returns the access log override of an IngressRoute
+// virtual host, or nil if it overrides nothing.
+func (b *Builder) ingressrouteAccessLog(al *ingressroutev1.AccessLog) (*AccessLog, error) {
+	if al == nil {
+		return nil, nil
+	}
+	var accessLog AccessLog
+	if f := al.Filter; f != nil {
+		var minDuration time.Duration
+		if f.MinDuration != nil {
+			d, err := ptypes.Duration(&f.MinDuration.Duration)
+			if err != nil {
+				return nil, fmt.Errorf("filter: %v", err)
+			}
+			minDuration = d
+		}
+		filter, err := NewAccessLogFilter(f.StatusCodes, minDuration, f.SamplePercent, f.Headers)
+		if err != nil {
+			return nil, fmt.Errorf("filter: %v", err)
+		}
+		accessLog.Filter = filter
+	}
+
+	if al.Path != "" {
+		if !path.IsAbs(al.Path) {
+			return nil, fmt.Errorf("path %q must be absolute", al.Path)
+		}
+		if !allowedAccessLogPath(al.Path, b.AccessLogPaths) {
+			return nil, fmt.Errorf("path %q is not allowed", al.Path)
+		}
+	}
+	accessLog.Path = al.Path
+
+	switch al.Format {
+	case "", "json":
+	case "envoy":
+		if len(al.Fields) > 0 {
+			return nil, errors.New("fields require the json format")
+		}
+	default:
+		return nil, fmt.Errorf("format %q must be envoy or json", al.Format)
+	}
+	accessLog.Format = al.Format
+	if accessLog.Format == "" && len(al.Fields) > 0 {
+		accessLog.Format = "json"
+	}
+	if len(al.Fields) > 0 && b.ValidAccessLogFields != nil {
+		if err := b.ValidAccessLogFields(al.Fields); err != nil {
+			return nil, fmt.Errorf("fields: %v", err)
+		}
+	}
+	accessLog.Fields = al.Fields
+
+	seen := make(map[string]bool)
+	for _, h := range al.Headers {
+		key := http.CanonicalHeaderKey(h)
+		if msgs := validation.IsHTTPHeaderName(key); len(msgs) != 0 {
+			return nil, fmt.Errorf("invalid header %q: %v", key, msgs)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate header %q", key)
+		}
+		seen[key] = true
+		accessLog.Headers = append(accessLog.Headers, key)
+	}
+
+	if accessLog.Filter == nil && accessLog.Path == "" && accessLog.Format == "" && len(accessLog.Headers) == 0 {
+		return nil, nil
+	}
+	return &accessLog, nil
+}
+
+// allowedAccessLogPath returns whether the access log may be written to the
+// file, which is either one of the allowed paths or lies in one of the
+// allowed directories, whose paths end with a slash.
+func allowedAccessLogPath(file string, allowed []string) bool {
+	if path.Clean(file) != file {
+		return false
+	}
+	for _, p := range allowed {
+		if strings.HasSuffix(p, "/") {
+			if strings.HasPrefix(file, p) {
+				return true
+			}
+			continue
+		}
+		if file == p {
+			return true
+		}
+	}
+	return false
+}
+
+// setAccessLog sets the access log override of the virtual hosts of a root.
+func (b *Builder) setAccessLog(host string, accessLog *AccessLog, secure bool) {
+	if accessLog == nil {
+		return
+	}
+	b.lookupVirtualHost(host).AccessLog = accessLog
+	if secure {
+		b.lookupSecureVirtualHost(host).AccessLog = accessLog
+	}
+}
@@ -50,10 +50,53 @@ type Builder struct {
 	virtualhosts       map[string]*VirtualHost
 	securevirtualhosts map[string]*SecureVirtualHost
 
+	// Adobe - the ports of the additional listeners serving the
+	// virtual hosts, by name
+	listenerPorts map[string]int
+
+	// Adobe - why the roots, and the hosts of the Ingresses, conflicting
+	// with the hosts of other objects of any kind are invalid
+	hostConflicts        map[k8s.Object]string
+	ingressHostConflicts map[ingressHost]string
+
 	orphaned map[k8s.FullName]bool
 
 	FallbackCertificate *k8s.FullName
 
+	// Adobe - UpstreamClientCertificate is the default client certificate
+	// presented to upstreams speaking TLS.
+	UpstreamClientCertificate *k8s.FullName
+
+	// Adobe - UpstreamTLS holds the default TLS parameters of the
+	// connections to upstreams speaking TLS.
+	UpstreamTLS *projcontour.UpstreamTLS
+
+	// Adobe - CertificateExpiryWarning is the period before the expiry of
+	// the certificate of a secure vhost during which its status carries
+	// a warning. Zero disables the warning.
+	CertificateExpiryWarning time.Duration
+
+	// Adobe - RefuseExpiredCertificates invalidates the secure vhosts
+	// whose certificate has expired rather than serving it.
+	RefuseExpiredCertificates bool
+
+	// Adobe - Listeners are the additional listeners the roots select
+	// by port.
+	Listeners []AdditionalListener
+
+	// Adobe - AccessLogPaths are the files the IngressRoute virtual hosts
+	// may write their access log to, the paths ending with a slash
+	// allowing any file under the directory. Other files are refused.
+	AccessLogPaths []string
+
+	// Adobe - ValidAccessLogFields, if not nil, validates the JSON fields
+	// logged by the IngressRoute virtual hosts.
+	ValidAccessLogFields func(fields []string) error
+
+	// now returns the current time, used to check certificate expiry.
+	// If nil, time.Now is used.
+	now func() time.Time
+
 	StatusWriter
 }
 
@@ -61,6 +104,9 @@ type Builder struct {
 func (b *Builder) Build() *DAG {
 	b.reset()
 
+	// Adobe - find the conflicting hosts across the kinds of objects
+	b.computeHostConflicts()
+
 	// setup secure vhosts if there is a matching secret
 	// we do this first so that the set of active secure vhosts is stable
 	// during computeIngresses.
@@ -83,8 +129,10 @@ func (b *Builder) reset() {
 
 	b.virtualhosts = make(map[string]*VirtualHost)
 	b.securevirtualhosts = make(map[string]*SecureVirtualHost)
+	b.listenerPorts = make(map[string]int)
 
 	b.statuses = make(map[k8s.FullName]Status, len(b.statuses))
+	b.conflicts = nil
 }
 
 // lookupService returns a Service that matches the Meta and Port of the Kubernetes' Service.
@@ -134,6 +182,7 @@ func (b *Builder) addService(svc *v1.Service, port *v1.ServicePort) *Service {
 		MaxRequests:        annotation.MaxRequests(svc),
 		MaxRetries:         annotation.MaxRetries(svc),
 		ExternalName:       externalName(svc),
+		LocalityLbPolicy:   annotation.LocalityLbPolicy(svc),
 	}
 	b.services[s.ToFullName()] = s
 	return s
@@ -223,7 +272,7 @@ func (b *Builder) validIngressRoutes() []*ingressroutev1.IngressRoute {
 			msg := fmt.Sprintf("fqdn %q is used in multiple IngressRoutes: %s", fqdn, strings.Join(conflicting, ", "))
 			for _, ir := range irs {
 				sw, commit := b.WithObject(ir)
-				sw.WithValue("vhost", fqdn).SetInvalid(msg)
+				sw.WithValue("vhost", fqdn).SetInvalid(ReasonVirtualHostInvalid, msg)
 				commit()
 			}
 		}
@@ -260,7 +309,7 @@ func (b *Builder) validHTTPProxies() []*projcontour.HTTPProxy {
 			msg := fmt.Sprintf("fqdn %q is used in multiple HTTPProxies: %s", fqdn, strings.Join(conflicting, ", "))
 			for _, proxy := range proxies {
 				sw, commit := b.WithObject(proxy)
-				sw.WithValue("vhost", fqdn).SetInvalid(msg)
+				sw.WithValue("vhost", fqdn).SetInvalid(ReasonVirtualHostInvalid, msg)
 				commit()
 			}
 		}
@@ -292,10 +341,22 @@ func (b *Builder) computeSecureVirtualhosts() {
 				continue
 			}
 
+			// Adobe - refuse expired certificates
+			if b.RefuseExpiredCertificates && b.certificateExpired(sec) {
+				b.Source.WithField("name", ing.GetName()).
+					WithField("namespace", ing.GetNamespace()).
+					Errorf("certificate of Secret %q has expired", secretName)
+				continue
+			}
+
 			// We have validated the TLS secrets, so we can go
 			// ahead and create the SecureVirtualHost for this
 			// Ingress.
 			for _, host := range tls.Hosts {
+				// Adobe - skip the hosts conflicting with the alias of a root
+				if _, ok := b.hostConflict(ing, host); ok {
+					continue
+				}
 				svhost := b.lookupSecureVirtualHost(host)
 				svhost.Secret = sec
 				svhost.MinProtoVersion = annotation.MinProtoVersion(
@@ -365,8 +426,16 @@ func (b *Builder) computeIngresses() {
 
 func (b *Builder) computeIngressRule(ing *v1beta1.Ingress, rule v1beta1.IngressRule) {
 	host := rule.Host
-	if strings.Contains(host, "*") {
+	if strings.Contains(host, "*") && validWildcardFQDN(host) != nil {
 		// reject hosts with wildcard characters.
+		// Adobe - other than those of the form *.example.com
+		return
+	}
+	// Adobe - skip the hosts conflicting with the alias of a root
+	if msg, ok := b.hostConflict(ing, host); ok {
+		b.Source.WithField("name", ing.GetName()).
+			WithField("namespace", ing.GetNamespace()).
+			Errorf("invalid host %q: %s", host, msg)
 		return
 	}
 	if host == "" {
@@ -417,22 +486,29 @@ func (b *Builder) computeIngressRoute(ir *ingressroutev1.IngressRoute) {
 
 	// ensure root ingressroute lives in allowed namespace
 	if !b.rootAllowed(ir.Namespace) {
-		sw.SetInvalid("root IngressRoute cannot be defined in this namespace")
+		sw.SetInvalid(ReasonRootNotAllowed, "root IngressRoute cannot be defined in this namespace")
 		return
 	}
 
 	host := ir.Spec.VirtualHost.Fqdn
 	if isBlank(host) {
-		sw.SetInvalid("Spec.VirtualHost.Fqdn must be specified")
+		sw.SetInvalid(ReasonVirtualHostInvalid, "Spec.VirtualHost.Fqdn must be specified")
 		return
 	}
 	sw.WithValue("vhost", host)
 
-	// Adobe - we allow
-	// if strings.Contains(host, "*") {
-	// 	sw.SetInvalid("Spec.VirtualHost.Fqdn %q cannot use wildcards", host)
-	// 	return
-	// }
+	// Adobe - we allow the catch-all and wildcards of the form *.example.com
+	if err := validWildcardFQDN(host); host != "*" && err != nil {
+		sw.SetInvalid(ReasonVirtualHostInvalid, "%s", err)
+		return
+	}
+
+	// Adobe - access log override
+	accessLog, err := b.ingressrouteAccessLog(ir.Spec.VirtualHost.AccessLog)
+	if err != nil {
+		sw.SetInvalid(ReasonVirtualHostInvalid, "Spec.VirtualHost.AccessLog %s", err)
+		return
+	}
 
 	var enforceTLS, passthrough bool
 	if tls := ir.Spec.VirtualHost.TLS; tls != nil {
@@ -444,17 +520,66 @@ func (b *Builder) computeIngressRoute(ir *ingressroutev1.IngressRoute) {
 			secretName := splitSecret(tls.SecretName, ir.Namespace)
 			sec, err := b.lookupSecret(secretName, validSecret)
 			if err != nil {
-				sw.SetInvalid("Spec.VirtualHost.TLS Secret %q is invalid: %s", tls.SecretName, err)
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.TLS Secret %q is invalid: %s", tls.SecretName, err)
 				return
 			}
 
 			if !b.delegationPermitted(secretName, ir.Namespace) {
-				sw.SetInvalid("Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", tls.SecretName)
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", tls.SecretName)
+				return
+			}
+
+			// Adobe - certificate expiry
+			if err := b.checkCertificateExpiry(sw, tls.SecretName, sec); err != nil {
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.TLS %s", err)
+				return
+			}
+
+			// Adobe - cipher suites, ECDH curves and secondary certificate
+			if err := ValidCipherSuites(tls.CipherSuites); err != nil {
+				sw.SetInvalid(ReasonTLSInvalid, "Spec.VirtualHost.TLS %s", err)
+				return
+			}
+			if err := ValidECDHCurves(tls.ECDHCurves); err != nil {
+				sw.SetInvalid(ReasonTLSInvalid, "Spec.VirtualHost.TLS %s", err)
+				return
+			}
+			var secondary *Secret
+			if tls.SecondarySecretName != "" {
+				secondary, err = b.lookupSecondaryCertificate(sec, tls.SecondarySecretName, ir.Namespace)
+				if err != nil {
+					sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.TLS %s", err)
+					return
+				}
+				if err := b.checkCertificateExpiry(sw, tls.SecondarySecretName, secondary); err != nil {
+					sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.TLS %s", err)
+					return
+				}
+				if err := secondary.coverWildcard(host); err != nil {
+					sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.Fqdn %s", err)
+					return
+				}
+				if err := secondary.coverNames(ir.Spec.VirtualHost.Aliases); err != nil {
+					sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.Aliases %s", err)
+					return
+				}
+			}
+
+			// Adobe - the certificate must cover the wildcard and the aliases
+			if err := sec.coverWildcard(host); err != nil {
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.Fqdn %s", err)
+				return
+			}
+			if err := sec.coverNames(ir.Spec.VirtualHost.Aliases); err != nil {
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.Aliases %s", err)
 				return
 			}
 
 			svhost := b.lookupSecureVirtualHost(ir.Spec.VirtualHost.Fqdn)
 			svhost.Secret = sec
+			svhost.SecondarySecret = secondary
+			svhost.CipherSuites = tls.CipherSuites
+			svhost.ECDHCurves = tls.ECDHCurves
 			svhost.MinProtoVersion = annotation.MinProtoVersion(ir.Spec.VirtualHost.TLS.MinimumProtocolVersion)
 			svhost.MaxProtoVersion = annotation.MaxProtoVersion(ir.Spec.VirtualHost.TLS.MaximumProtocolVersion)
 			enforceTLS = true
@@ -466,10 +591,15 @@ func (b *Builder) computeIngressRoute(ir *ingressroutev1.IngressRoute) {
 	}
 
 	b.processIngressRoutes(sw, ir, "", nil, host, ir.Spec.TCPProxy == nil && enforceTLS)
+
+	// Adobe - aliases and access log
+	b.addAliases(host, ir.Spec.VirtualHost.Aliases, passthrough || enforceTLS)
+	b.setAccessLog(host, accessLog, passthrough || enforceTLS)
 }
 
 func (b *Builder) computeHTTPProxies() {
-	for _, proxy := range b.validHTTPProxies() {
+	// Adobe - validate aliases
+	for _, proxy := range b.validHTTPProxiesAdobe() {
 		b.computeHTTPProxy(proxy)
 	}
 }
@@ -486,18 +616,19 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 
 	// ensure root httpproxy lives in allowed namespace
 	if !b.rootAllowed(proxy.Namespace) {
-		sw.SetInvalid("root HTTPProxy cannot be defined in this namespace")
+		sw.SetInvalid(ReasonRootNotAllowed, "root HTTPProxy cannot be defined in this namespace")
 		return
 	}
 
 	host := proxy.Spec.VirtualHost.Fqdn
 	if isBlank(host) {
-		sw.SetInvalid("Spec.VirtualHost.Fqdn must be specified")
+		sw.SetInvalid(ReasonVirtualHostInvalid, "Spec.VirtualHost.Fqdn must be specified")
 		return
 	}
 	sw = sw.WithValue("vhost", host)
-	if strings.Contains(host, "*") {
-		sw.SetInvalid("Spec.VirtualHost.Fqdn %q cannot use wildcards", host)
+	// Adobe - we allow wildcards of the form *.example.com
+	if err := validWildcardFQDN(host); err != nil {
+		sw.SetInvalid(ReasonVirtualHostInvalid, "%s", err)
 		return
 	}
 
@@ -512,12 +643,28 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 			secretName := splitSecret(tls.SecretName, proxy.Namespace)
 			sec, err := b.lookupSecret(secretName, validSecret)
 			if err != nil {
-				sw.SetInvalid("Spec.VirtualHost.TLS Secret %q is invalid: %s", tls.SecretName, err)
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.TLS Secret %q is invalid: %s", tls.SecretName, err)
 				return
 			}
 
 			if !b.delegationPermitted(secretName, proxy.Namespace) {
-				sw.SetInvalid("Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", tls.SecretName)
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", tls.SecretName)
+				return
+			}
+
+			// Adobe - certificate expiry
+			if err := b.checkCertificateExpiry(sw, tls.SecretName, sec); err != nil {
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.TLS %s", err)
+				return
+			}
+
+			// Adobe - the certificate must cover the wildcard and the aliases
+			if err := sec.coverWildcard(host); err != nil {
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.Fqdn %s", err)
+				return
+			}
+			if err := sec.coverNames(proxy.Spec.VirtualHost.Aliases); err != nil {
+				sw.SetInvalid(ReasonSecretInvalid, "Spec.VirtualHost.Aliases %s", err)
 				return
 			}
 
@@ -527,25 +674,25 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 
 			// Check if FallbackCertificate && ClientValidation are both enabled in the same vhost
 			if tls.EnableFallbackCertificate && tls.ClientValidation != nil {
-				sw.SetInvalid("Spec.Virtualhost.TLS fallback & client validation are incompatible together")
+				sw.SetInvalid(ReasonTLSInvalid, "Spec.Virtualhost.TLS fallback & client validation are incompatible together")
 				return
 			}
 
 			// If FallbackCertificate is enabled, but no cert passed, set error
 			if tls.EnableFallbackCertificate {
 				if b.FallbackCertificate == nil {
-					sw.SetInvalid("Spec.Virtualhost.TLS enabled fallback but the fallback Certificate Secret is not configured in Contour configuration file")
+					sw.SetInvalid(ReasonTLSInvalid, "Spec.Virtualhost.TLS enabled fallback but the fallback Certificate Secret is not configured in Contour configuration file")
 					return
 				}
 
 				sec, err = b.lookupSecret(*b.FallbackCertificate, validSecret)
 				if err != nil {
-					sw.SetInvalid("Spec.Virtualhost.TLS Secret %q fallback certificate is invalid: %s", b.FallbackCertificate, err)
+					sw.SetInvalid(ReasonTLSInvalid, "Spec.Virtualhost.TLS Secret %q fallback certificate is invalid: %s", b.FallbackCertificate, err)
 					return
 				}
 
 				if !b.delegationPermitted(*b.FallbackCertificate, proxy.Namespace) {
-					sw.SetInvalid("Spec.VirtualHost.TLS fallback Secret %q is not configured for certificate delegation", b.FallbackCertificate)
+					sw.SetInvalid(ReasonTLSInvalid, "Spec.VirtualHost.TLS fallback Secret %q is not configured for certificate delegation", b.FallbackCertificate)
 					return
 				}
 
@@ -556,20 +703,20 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 			if tls.ClientValidation != nil {
 				dv, err := b.lookupDownstreamValidation(tls.ClientValidation, proxy.Namespace)
 				if err != nil {
-					sw.SetInvalid("Spec.VirtualHost.TLS client validation is invalid: %s", err)
+					sw.SetInvalid(ReasonTLSInvalid, "Spec.VirtualHost.TLS client validation is invalid: %s", err)
 					return
 				}
 				svhost.DownstreamValidation = dv
 			}
 		} else if tls.ClientValidation != nil {
-			sw.SetInvalid("Spec.VirtualHost.TLS passthrough cannot be combined with tls.clientValidation")
+			sw.SetInvalid(ReasonTLSInvalid, "Spec.VirtualHost.TLS passthrough cannot be combined with tls.clientValidation")
 			return
 		}
 	}
 
 	if proxy.Spec.TCPProxy != nil {
 		if !tlsValid {
-			sw.SetInvalid("tcpproxy: missing tls.passthrough or tls.secretName")
+			sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: missing tls.passthrough or tls.secretName")
 			return
 		}
 		if !b.processHTTPProxyTCPProxy(sw, proxy, nil, host) {
@@ -587,6 +734,9 @@ func (b *Builder) computeHTTPProxy(proxy *projcontour.HTTPProxy) {
 		secure := b.lookupSecureVirtualHost(host)
 		addRoutes(secure, routes)
 	}
+
+	// Adobe - aliases
+	b.addAliases(host, proxy.Spec.VirtualHost.Aliases, tlsValid)
 }
 
 type vhost interface {
@@ -717,17 +867,18 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 		}
 		if v.Name == proxy.Name && v.Namespace == proxy.Namespace {
 			path = append(path, fmt.Sprintf("%s/%s", proxy.Namespace, proxy.Name))
-			sw.SetInvalid("include creates a delegation cycle: %s", strings.Join(path, " -> "))
+			sw.SetInvalid(ReasonIncludeInvalid, "include creates a delegation cycle: %s", strings.Join(path, " -> "))
 			return nil
 		}
 	}
 
 	visited = append(visited, proxy)
 	var routes []*Route
+	valid := true
 
 	// Check for duplicate conditions on the includes
 	if includeConditionsIdentical(proxy.Spec.Includes) {
-		sw.SetInvalid("duplicate conditions defined on an include")
+		sw.SetInvalid(ReasonIncludeInvalid, "duplicate conditions defined on an include")
 		return nil
 	}
 
@@ -740,17 +891,20 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 
 		delegate, ok := b.Source.httpproxies[k8s.FullName{Name: include.Name, Namespace: namespace}]
 		if !ok {
-			sw.SetInvalid("include %s/%s not found", namespace, include.Name)
-			return nil
+			sw.SetInvalid(ReasonIncludeInvalid, "include %s/%s not found", namespace, include.Name)
+			valid = false
+			continue
 		}
 		if delegate.Spec.VirtualHost != nil {
-			sw.SetInvalid("root httpproxy cannot delegate to another root httpproxy")
-			return nil
+			sw.SetInvalid(ReasonIncludeInvalid, "root httpproxy cannot delegate to another root httpproxy")
+			valid = false
+			continue
 		}
 
 		if err := pathConditionsValid(include.Conditions); err != nil {
-			sw.SetInvalid("include: %s", err)
-			return nil
+			sw.SetInvalid(ReasonIncludeInvalid, "include: %s", err)
+			valid = false
+			continue
 		}
 
 		sw, commit := b.WithObject(delegate)
@@ -763,33 +917,38 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 
 	for _, route := range proxy.Spec.Routes {
 		if err := pathConditionsValid(route.Conditions); err != nil {
-			sw.SetInvalid("route: %s", err)
-			return nil
+			sw.SetInvalid(ReasonRouteInvalid, "route: %s", err)
+			valid = false
+			continue
 		}
 
 		conds := append(conditions, route.Conditions...)
 
 		// Look for duplicate exact match headers on this route
 		if !headerConditionsAreValid(conds) {
-			sw.SetInvalid("cannot specify duplicate header 'exact match' conditions in the same route")
-			return nil
+			sw.SetInvalid(ReasonRouteInvalid, "cannot specify duplicate header 'exact match' conditions in the same route")
+			valid = false
+			continue
 		}
 
 		reqHP, err := headersPolicy(route.RequestHeadersPolicy, true /* allow Host */)
 		if err != nil {
-			sw.SetInvalid(err.Error())
-			return nil
+			sw.SetInvalid(ReasonRouteInvalid, err.Error())
+			valid = false
+			continue
 		}
 
 		respHP, err := headersPolicy(route.ResponseHeadersPolicy, false /* disallow Host */)
 		if err != nil {
-			sw.SetInvalid(err.Error())
-			return nil
+			sw.SetInvalid(ReasonRouteInvalid, err.Error())
+			valid = false
+			continue
 		}
 
 		if len(route.Services) < 1 {
-			sw.SetInvalid("route.services must have at least one entry")
-			return nil
+			sw.SetInvalid(ReasonRouteInvalid, "route.services must have at least one entry")
+			valid = false
+			continue
 		}
 
 		r := &Route{
@@ -805,13 +964,15 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 
 		if len(route.GetPrefixReplacements()) > 0 {
 			if !r.HasPathPrefix() {
-				sw.SetInvalid("cannot specify prefix replacements without a prefix condition")
-				return nil
+				sw.SetInvalid(ReasonRouteInvalid, "cannot specify prefix replacements without a prefix condition")
+				valid = false
+				continue
 			}
 
 			if err := prefixReplacementsAreValid(route.GetPrefixReplacements()); err != nil {
-				sw.SetInvalid(err.Error())
-				return nil
+				sw.SetInvalid(ReasonRouteInvalid, err.Error())
+				valid = false
+				continue
 			}
 
 			// Note that we are guaranteed to always have a prefix
@@ -840,24 +1001,47 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 
 		}
 
+		hps, err := hashPolicies(route.HashPolicy)
+		if err != nil {
+			sw.SetInvalid(ReasonRouteInvalid, err.Error())
+			valid = false
+			continue
+		}
+		r.HashPolicy = hps
+
+		lbPolicy := loadBalancerPolicy(route.LoadBalancerPolicy)
+		if len(hps) > 0 {
+			var ignored string
+			lbPolicy, ignored = hashLoadBalancerPolicy(route.LoadBalancerPolicy)
+			if ignored != "" {
+				sw.SetWarning(ReasonHashPolicyIgnored, "load balancer strategy %q ignores the hash policy of route (%s), %q is used instead",
+					ignored, r.PathCondition, lbPolicy)
+			}
+		}
+
+		var priorities []int
+		routeValid := true
 		for _, service := range route.Services {
 			if service.Port < 1 || service.Port > 65535 {
-				sw.SetInvalid("service %q: port must be in the range 1-65535", service.Name)
-				return nil
+				sw.SetInvalid(ReasonServiceInvalid, "service %q: port must be in the range 1-65535", service.Name)
+				routeValid = false
+				continue
 			}
 			m := k8s.FullName{Name: service.Name, Namespace: proxy.Namespace}
 			s := b.lookupService(m, intstr.FromInt(service.Port))
 
 			if s == nil {
-				sw.SetInvalid("Service [%s:%d] is invalid or missing", service.Name, service.Port)
-				return nil
+				sw.SetInvalid(ReasonServiceInvalid, "Service [%s:%d] is invalid or missing", service.Name, service.Port)
+				routeValid = false
+				continue
 			}
 
 			// Determine the protocol to use to speak to this Cluster.
 			protocol, err := getProtocol(service, s)
 			if err != nil {
-				sw.SetInvalid(err.Error())
-				return nil
+				sw.SetInvalid(ReasonServiceInvalid, err.Error())
+				routeValid = false
+				continue
 			}
 
 			var uv *PeerValidationContext
@@ -865,27 +1049,31 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 				// we can only validate TLS connections to services that talk TLS
 				uv, err = b.lookupUpstreamValidation(service.UpstreamValidation, proxy.Namespace)
 				if err != nil {
-					sw.SetInvalid("Service [%s:%d] TLS upstream validation policy error: %s",
+					sw.SetInvalid(ReasonServiceInvalid, "Service [%s:%d] TLS upstream validation policy error: %s",
 						service.Name, service.Port, err)
-					return nil
+					routeValid = false
+					continue
 				}
 			}
 
 			reqHP, err := headersPolicy(service.RequestHeadersPolicy, true /* allow Host */)
 			if err != nil {
-				sw.SetInvalid(err.Error())
-				return nil
+				sw.SetInvalid(ReasonServiceInvalid, err.Error())
+				routeValid = false
+				continue
 			}
 
 			respHP, err := headersPolicy(service.ResponseHeadersPolicy, false /* disallow Host */)
 			if err != nil {
-				sw.SetInvalid(err.Error())
-				return nil
+				sw.SetInvalid(ReasonServiceInvalid, err.Error())
+				routeValid = false
+				continue
 			}
 
 			c := &Cluster{
 				Upstream:              s,
-				LoadBalancerPolicy:    loadBalancerPolicy(route.LoadBalancerPolicy),
+				LoadBalancerPolicy:    lbPolicy,
+				LoadBalancerOptions:   loadBalancerOptions(route.LoadBalancerPolicy),
 				Weight:                uint32(service.Weight),
 				HTTPHealthCheckPolicy: httpHealthCheckPolicy(route.HealthCheckPolicy),
 				UpstreamValidation:    uv,
@@ -894,9 +1082,32 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 				Protocol:              protocol,
 				SNI:                   determineSNI(r.RequestHeadersPolicy, reqHP, s),
 			}
+			if err := validHealthCheckPolicy(c); err != nil {
+				sw.SetInvalid(ReasonServiceInvalid, "service %q: %s", service.Name, err)
+				routeValid = false
+				continue
+			}
+			if err := validLoadBalancerOptions(c); err != nil {
+				sw.SetInvalid(ReasonServiceInvalid, "service %q: %s", service.Name, err)
+				routeValid = false
+				continue
+			}
+			c.ClientCertificate, err = b.lookupClientCertificate(service.ClientCertificate, protocol, proxy.Namespace)
+			if err != nil {
+				sw.SetInvalid(ReasonServiceInvalid, "service %q: %s", service.Name, err)
+				routeValid = false
+				continue
+			}
+			c.UpstreamTLS, err = b.upstreamTLS(service.UpstreamTLS, protocol)
+			if err != nil {
+				sw.SetInvalid(ReasonServiceInvalid, "service %q: %s", service.Name, err)
+				routeValid = false
+				continue
+			}
 			if service.Mirror && r.MirrorPolicy != nil {
-				sw.SetInvalid("only one service per route may be nominated as mirror")
-				return nil
+				sw.SetInvalid(ReasonRouteInvalid, "only one service per route may be nominated as mirror")
+				routeValid = false
+				continue
 			}
 			if service.Mirror {
 				r.MirrorPolicy = &MirrorPolicy{
@@ -904,11 +1115,31 @@ func (b *Builder) computeRoutes(sw *ObjectStatusWriter, proxy *projcontour.HTTPP
 				}
 			} else {
 				r.Clusters = append(r.Clusters, c)
+				priorities = append(priorities, service.Priority)
 			}
 		}
+
+		if !routeValid {
+			valid = false
+			continue
+		}
+
+		clusters, err := priorityClusters(r.Clusters, priorities)
+		if err != nil {
+			sw.SetInvalid(ReasonRouteInvalid, err.Error())
+			valid = false
+			continue
+		}
+		r.Clusters = clusters
 		routes = append(routes, r)
 	}
 
+	// Adobe - the errors of all the includes and routes are reported
+	// before the routes are dropped
+	if !valid {
+		return nil
+	}
+
 	routes = expandPrefixMatches(routes)
 
 	sw.SetValid()
@@ -974,6 +1205,9 @@ func (b *Builder) buildDAG() *DAG {
 		dag.roots = append(dag.roots, https)
 	}
 
+	// Adobe - additional listeners
+	dag.roots = append(dag.roots, b.buildAdditionalListeners()...)
+
 	for meta := range b.orphaned {
 		ir, ok := b.Source.ingressroutes[meta]
 		if ok {
@@ -991,6 +1225,7 @@ func (b *Builder) buildDAG() *DAG {
 		}
 	}
 	dag.statuses = b.statuses
+	dag.conflicts = b.conflicts
 	return &dag
 }
 
@@ -1001,7 +1236,8 @@ func (b *Builder) buildHTTPListener() *Listener {
 	var virtualhosts = make([]Vertex, 0, len(b.virtualhosts))
 
 	for _, vh := range b.virtualhosts {
-		if vh.Valid() {
+		// Adobe - unless served by an additional listener
+		if vh.Valid() && b.listenerPorts[vh.Name] == 0 {
 			virtualhosts = append(virtualhosts, vh)
 		}
 	}
@@ -1020,7 +1256,8 @@ func (b *Builder) buildHTTPListener() *Listener {
 func (b *Builder) buildHTTPSListener() *Listener {
 	var virtualhosts = make([]Vertex, 0, len(b.securevirtualhosts))
 	for _, svh := range b.securevirtualhosts {
-		if svh.Valid() {
+		// Adobe - unless served by an additional listener
+		if svh.Valid() && b.listenerPorts[svh.Name] == 0 {
 			virtualhosts = append(virtualhosts, svh)
 		}
 	}
@@ -1057,6 +1294,7 @@ func (b *Builder) rootAllowed(namespace string) bool {
 
 func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev1.IngressRoute, prefixMatch string, visited []*ingressroutev1.IngressRoute, host string, enforceTLS bool) {
 	visited = append(visited, ir)
+	valid := true
 
 	if vhosts := annotation.ExtraVHosts(ir); vhosts != nil {
 		b.lookupVirtualHost(host).HostNames = vhosts
@@ -1068,15 +1306,17 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 	for _, route := range ir.Spec.Routes {
 		// route cannot both delegate and point to services
 		if len(route.Services) > 0 && route.Delegate != nil {
-			sw.SetInvalid("route %q: cannot specify services and delegate in the same route", route.Match)
-			return
+			sw.SetInvalid(ReasonRouteInvalid, "route %q: cannot specify services and delegate in the same route", route.Match)
+			valid = false
+			continue
 		}
 
 		// base case: The route points to services, so we add them to the vhost
 		if len(route.Services) > 0 {
 			if !matchesPathPrefix(route.Match, prefixMatch) {
-				sw.SetInvalid("the path prefix %q does not match the parent's path prefix %q", route.Match, prefixMatch)
-				return
+				sw.SetInvalid(ReasonIncludeInvalid, "the path prefix %q does not match the parent's path prefix %q", route.Match, prefixMatch)
+				valid = false
+				continue
 			}
 
 			permitInsecure := route.PermitInsecure && !b.DisablePermitInsecure
@@ -1094,8 +1334,9 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 			if route.RequestHeadersPolicy != nil {
 				reqHP, err := headersPolicy(route.RequestHeadersPolicy, true /* allow Host */)
 				if err != nil {
-					sw.SetInvalid(err.Error())
-					return
+					sw.SetInvalid(ReasonRouteInvalid, err.Error())
+					valid = false
+					continue
 				}
 				r.RequestHeadersPolicy = reqHP
 			}
@@ -1103,8 +1344,9 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 			if route.ResponseHeadersPolicy != nil {
 				respHP, err := headersPolicy(route.ResponseHeadersPolicy, false /* disallow Host */)
 				if err != nil {
-					sw.SetInvalid(err.Error())
-					return
+					sw.SetInvalid(ReasonRouteInvalid, err.Error())
+					valid = false
+					continue
 				}
 				r.ResponseHeadersPolicy = respHP
 			}
@@ -1114,8 +1356,9 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 					if d > time.Hour {
 						r.IdleTimeout = ptypes.DurationProto(time.Hour)
 					} else if d <= 0 {
-						sw.SetInvalid("route %q: idle timeout can not be disabled", route.Match)
-						return
+						sw.SetInvalid(ReasonRouteInvalid, "route %q: idle timeout can not be disabled", route.Match)
+						valid = false
+						continue
 					} else {
 						r.IdleTimeout = &route.IdleTimeout.Duration
 					}
@@ -1125,8 +1368,9 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 			if route.Timeout != nil {
 				if d, err := ptypes.Duration(&route.Timeout.Duration); err == nil {
 					if d < 0 {
-						sw.SetInvalid("route %q: timeout value must be >= 0", route.Match)
-						return
+						sw.SetInvalid(ReasonRouteInvalid, "route %q: timeout value must be >= 0", route.Match)
+						valid = false
+						continue
 					} else {
 						r.Timeout = &route.Timeout.Duration
 					}
@@ -1135,11 +1379,13 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 
 			if route.Tracing != nil {
 				if route.Tracing.ClientSampling > 100 {
-					sw.SetInvalid("route %q: tracing clientSampling must be in the range [0,100]", route.Match)
-					return
+					sw.SetInvalid(ReasonRouteInvalid, "route %q: tracing clientSampling must be in the range [0,100]", route.Match)
+					valid = false
+					continue
 				} else if route.Tracing.RandomSampling > 100 {
-					sw.SetInvalid("route %q: tracing randomSampling must be in the range [0,100]", route.Match)
-					return
+					sw.SetInvalid(ReasonRouteInvalid, "route %q: tracing randomSampling must be in the range [0,100]", route.Match)
+					valid = false
+					continue
 				} else {
 					r.Tracing = route.Tracing
 				}
@@ -1155,23 +1401,28 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 					})
 				}
 				if !headerConditionsAreValid(conds) {
-					sw.SetInvalid("cannot specify duplicate header 'exact match' conditions in the same route")
-					return
+					sw.SetInvalid(ReasonRouteInvalid, "cannot specify duplicate header 'exact match' conditions in the same route")
+					valid = false
+					continue
 				}
 				r.HeaderConditions = mergeHeaderConditions(conds)
 			}
 
+			var priorities []int
+			routeValid := true
 			for _, service := range route.Services {
 				if service.Port < 1 || service.Port > 65535 {
-					sw.SetInvalid("route %q: service %q: port must be in the range 1-65535", route.Match, service.Name)
-					return
+					sw.SetInvalid(ReasonServiceInvalid, "route %q: service %q: port must be in the range 1-65535", route.Match, service.Name)
+					routeValid = false
+					continue
 				}
 				m := k8s.FullName{Name: service.Name, Namespace: ir.Namespace}
 
 				s := b.lookupService(m, intstr.FromInt(service.Port))
 				if s == nil {
-					sw.SetInvalid("Service [%s:%d] is invalid or missing", service.Name, service.Port)
-					return
+					sw.SetInvalid(ReasonServiceInvalid, "Service [%s:%d] is invalid or missing", service.Name, service.Port)
+					routeValid = false
+					continue
 				}
 
 				var uv *PeerValidationContext
@@ -1180,28 +1431,61 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 					// we can only validate TLS connections to services that talk TLS
 					uv, err = b.lookupUpstreamValidation(service.UpstreamValidation, ir.Namespace)
 					if err != nil {
-						sw.SetInvalid("Service [%s:%d] TLS upstream validation policy error: %s",
+						sw.SetInvalid(ReasonServiceInvalid, "Service [%s:%d] TLS upstream validation policy error: %s",
 							service.Name, service.Port, err)
-						return
+						routeValid = false
+						continue
 					}
 				}
 
+				strategy, err := ingressrouteLoadBalancerPolicy(service)
+				if err != nil {
+					sw.SetInvalid(ReasonServiceInvalid, "route %q: service %q: %s", route.Match, service.Name, err)
+					routeValid = false
+					continue
+				}
+
 				c := &Cluster{
 					Upstream:              s,
-					LoadBalancerPolicy:    service.Strategy,
+					LoadBalancerPolicy:    strategy,
+					LoadBalancerOptions:   loadBalancerOptions(service.LoadBalancerPolicy),
 					Weight:                uint32(service.Weight),
 					HTTPHealthCheckPolicy: ingressrouteHealthCheckPolicy(service.HealthCheck),
 					UpstreamValidation:    uv,
 					Protocol:              s.Protocol,
 				}
 
+				if err := validHealthCheckPolicy(c); err != nil {
+					sw.SetInvalid(ReasonServiceInvalid, "route %q: service %q: %s", route.Match, service.Name, err)
+					routeValid = false
+					continue
+				}
+				if err := validLoadBalancerOptions(c); err != nil {
+					sw.SetInvalid(ReasonServiceInvalid, "route %q: service %q: %s", route.Match, service.Name, err)
+					routeValid = false
+					continue
+				}
+				c.ClientCertificate, err = b.lookupClientCertificate(service.ClientCertificate, s.Protocol, ir.Namespace)
+				if err != nil {
+					sw.SetInvalid(ReasonServiceInvalid, "route %q: service %q: %s", route.Match, service.Name, err)
+					routeValid = false
+					continue
+				}
+				c.UpstreamTLS, err = b.upstreamTLS(service.UpstreamTLS, s.Protocol)
+				if err != nil {
+					sw.SetInvalid(ReasonServiceInvalid, "route %q: service %q: %s", route.Match, service.Name, err)
+					routeValid = false
+					continue
+				}
+
 				if service.IdleTimeout != nil {
 					if d, err := ptypes.Duration(&service.IdleTimeout.Duration); err == nil {
 						if d > time.Hour {
 							c.IdleTimeout = ptypes.DurationProto(time.Hour)
 						} else if d <= 0 {
-							sw.SetInvalid("route: %q service %q: idle timeout can not be disabled", route.Match, service.Name)
-							return
+							sw.SetInvalid(ReasonServiceInvalid, "route: %q service %q: idle timeout can not be disabled", route.Match, service.Name)
+							routeValid = false
+							continue
 						} else {
 							c.IdleTimeout = &service.IdleTimeout.Duration
 						}
@@ -1209,8 +1493,26 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 				}
 
 				r.Clusters = append(r.Clusters, c)
+				priorities = append(priorities, service.Priority)
 			}
 
+			if !routeValid {
+				valid = false
+				continue
+			}
+
+			clusters, err := priorityClusters(r.Clusters, priorities)
+			if err != nil {
+				sw.SetInvalid(ReasonRouteInvalid, "route %q: %s", route.Match, err)
+				valid = false
+				continue
+			}
+			r.Clusters = clusters
+
+			// Adobe - the routes following an error are only validated
+			if !valid {
+				continue
+			}
 			b.lookupVirtualHost(host).addRoute(r)
 			if enforceTLS {
 				b.lookupSecureVirtualHost(host).addRoute(r)
@@ -1218,8 +1520,9 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 			continue
 		}
 
-		if route.Delegate == nil {
+		if route.Delegate == nil || !valid {
 			// not a delegate route
+			// Adobe - or following an error
 			continue
 		}
 
@@ -1247,10 +1550,14 @@ func (b *Builder) processIngressRoutes(sw *ObjectStatusWriter, ir *ingressroutev
 			for _, vir := range visited {
 				if dest.Name == vir.Name && dest.Namespace == vir.Namespace {
 					path = append(path, fmt.Sprintf("%s/%s", dest.Namespace, dest.Name))
-					sw.SetInvalid("route creates a delegation cycle: %s", strings.Join(path, " -> "))
-					return
+					sw.SetInvalid(ReasonIncludeInvalid, "route creates a delegation cycle: %s", strings.Join(path, " -> "))
+					valid = false
+					break
 				}
 			}
+			if !valid {
+				continue
+			}
 
 			// follow the link and process the target ingress route
 			sw, commit := b.WithObject(dest)
@@ -1304,7 +1611,7 @@ func (b *Builder) processIngressRouteTCPProxy(sw *ObjectStatusWriter, ir *ingres
 	// tcpproxy cannot both delegate and point to services
 	tcpproxy := ir.Spec.TCPProxy
 	if len(tcpproxy.Services) > 0 && tcpproxy.Delegate != nil {
-		sw.SetInvalid("tcpproxy: cannot specify services and delegate in the same tcpproxy")
+		sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: cannot specify services and delegate in the same tcpproxy")
 		return
 	}
 
@@ -1314,14 +1621,33 @@ func (b *Builder) processIngressRouteTCPProxy(sw *ObjectStatusWriter, ir *ingres
 			m := k8s.FullName{Name: service.Name, Namespace: ir.Namespace}
 			s := b.lookupService(m, intstr.FromInt(service.Port))
 			if s == nil {
-				sw.SetInvalid("tcpproxy: service %s/%s/%d: not found", ir.Namespace, service.Name, service.Port)
+				sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: service %s/%s/%d: not found", ir.Namespace, service.Name, service.Port)
+				return
+			}
+			strategy, err := ingressrouteLoadBalancerPolicy(service)
+			if err != nil {
+				sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: service %s/%s/%d: %s", ir.Namespace, service.Name, service.Port, err)
+				return
+			}
+			c := &Cluster{
+				Upstream:            s,
+				LoadBalancerPolicy:  strategy,
+				LoadBalancerOptions: loadBalancerOptions(service.LoadBalancerPolicy),
+				Protocol:            s.Protocol,
+				// Adobe - weighted services, checked by connecting
+				Weight:               uint32(service.Weight),
+				TCPHealthCheckPolicy: ingressrouteTCPHealthCheckPolicy(service.HealthCheck),
+			}
+			if err := validLoadBalancerOptions(c); err != nil {
+				sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: service %s/%s/%d: %s", ir.Namespace, service.Name, service.Port, err)
 				return
 			}
-			proxy.Clusters = append(proxy.Clusters, &Cluster{
-				Upstream:           s,
-				LoadBalancerPolicy: service.Strategy,
-				Protocol:           s.Protocol,
-			})
+			proxy.Clusters = append(proxy.Clusters, c)
+		}
+		// Adobe - idle timeout, connect attempts and access log
+		if err := ingressrouteTCPProxyOptions(&proxy, tcpproxy); err != nil {
+			sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: %s", err)
+			return
 		}
 		b.lookupSecureVirtualHost(host).TCPProxy = &proxy
 		sw.SetValid()
@@ -1353,7 +1679,7 @@ func (b *Builder) processIngressRouteTCPProxy(sw *ObjectStatusWriter, ir *ingres
 		for _, vir := range visited {
 			if dest.Name == vir.Name && dest.Namespace == vir.Namespace {
 				path = append(path, fmt.Sprintf("%s/%s", dest.Namespace, dest.Name))
-				sw.SetInvalid("tcpproxy creates a delegation cycle: %s", strings.Join(path, " -> "))
+				sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy creates a delegation cycle: %s", strings.Join(path, " -> "))
 				return
 			}
 		}
@@ -1386,7 +1712,7 @@ func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *pr
 	}
 
 	if len(tcpproxy.Services) > 0 && tcpProxyInclude != nil {
-		sw.SetInvalid("tcpproxy: cannot specify services and include in the same httpproxy")
+		sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: cannot specify services and include in the same httpproxy")
 		return false
 	}
 
@@ -1396,15 +1722,32 @@ func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *pr
 			m := k8s.FullName{Name: service.Name, Namespace: httpproxy.Namespace}
 			s := b.lookupService(m, intstr.FromInt(service.Port))
 			if s == nil {
-				sw.SetInvalid("tcpproxy: service %s/%s/%d: not found", httpproxy.Namespace, service.Name, service.Port)
+				sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: service %s/%s/%d: not found", httpproxy.Namespace, service.Name, service.Port)
 				return false
 			}
-			proxy.Clusters = append(proxy.Clusters, &Cluster{
+			c := &Cluster{
 				Upstream:             s,
 				Protocol:             s.Protocol,
 				LoadBalancerPolicy:   loadBalancerPolicy(tcpproxy.LoadBalancerPolicy),
+				LoadBalancerOptions:  loadBalancerOptions(tcpproxy.LoadBalancerPolicy),
 				TCPHealthCheckPolicy: tcpHealthCheckPolicy(tcpproxy.HealthCheckPolicy),
-			})
+				// Adobe - weighted services
+				Weight: uint32(service.Weight),
+			}
+			// Adobe - health check of the service, overriding the tcpproxy one
+			if service.HealthCheckPolicy != nil {
+				c.TCPHealthCheckPolicy = tcpHealthCheckPolicy(service.HealthCheckPolicy)
+			}
+			if err := validLoadBalancerOptions(c); err != nil {
+				sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: service %s/%s/%d: %s", httpproxy.Namespace, service.Name, service.Port, err)
+				return false
+			}
+			proxy.Clusters = append(proxy.Clusters, c)
+		}
+		// Adobe - idle timeout, connect attempts and access log
+		if err := tcpProxyOptions(&proxy, tcpproxy); err != nil {
+			sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: %s", err)
+			return false
 		}
 		b.lookupSecureVirtualHost(host).TCPProxy = &proxy
 		return true
@@ -1412,7 +1755,7 @@ func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *pr
 
 	if tcpProxyInclude == nil {
 		// We don't allow an empty TCPProxy object.
-		sw.SetInvalid("tcpproxy: either services or inclusion must be specified")
+		sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: either services or inclusion must be specified")
 		return false
 	}
 
@@ -1425,12 +1768,12 @@ func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *pr
 	m := k8s.FullName{Name: tcpProxyInclude.Name, Namespace: namespace}
 	dest, ok := b.Source.httpproxies[m]
 	if !ok {
-		sw.SetInvalid("tcpproxy: include %s/%s not found", m.Namespace, m.Name)
+		sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy: include %s/%s not found", m.Namespace, m.Name)
 		return false
 	}
 
 	if dest.Spec.VirtualHost != nil {
-		sw.SetInvalid("root httpproxy cannot delegate to another root httpproxy")
+		sw.SetInvalid(ReasonTCPProxyInvalid, "root httpproxy cannot delegate to another root httpproxy")
 		return false
 	}
 
@@ -1445,7 +1788,7 @@ func (b *Builder) processHTTPProxyTCPProxy(sw *ObjectStatusWriter, httpproxy *pr
 	for _, hp := range visited {
 		if dest.Name == hp.Name && dest.Namespace == hp.Namespace {
 			path = append(path, fmt.Sprintf("%s/%s", dest.Namespace, dest.Name))
-			sw.SetInvalid("tcpproxy include creates a cycle: %s", strings.Join(path, " -> "))
+			sw.SetInvalid(ReasonTCPProxyInvalid, "tcpproxy include creates a cycle: %s", strings.Join(path, " -> "))
 			return false
 		}
 	}
@@ -1563,7 +1906,8 @@ func validSecret(s *v1.Secret) error {
 		return fmt.Errorf("empty %q key", v1.TLSPrivateKeyKey)
 	}
 
-	return nil
+	// Adobe - Envoy rejects the key pairs which do not match
+	return validKeyPair(s)
 }
 
 func validCA(s *v1.Secret) error {
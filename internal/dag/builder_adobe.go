@@ -1,126 +1,888 @@
 package dag
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	"github.com/golang/protobuf/ptypes"
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/annotation"
+	"github.com/projectcontour/contour/internal/k8s"
+	"k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
-// validIngressRoutesAdobe ensures the extra vhost headers don't conflict
-// with other IngressRoutes vhosts or fqdns
+// validIngressRoutesAdobe ensures the aliases and extra vhost headers are
+// valid and don't conflict with the hosts of other objects, and the wildcard
+// fqdns don't overlap with the virtual hosts of other namespaces
 func (b *Builder) validIngressRoutesAdobe() []*ingressroutev1.IngressRoute {
 	valid := b.validIngressRoutes()
 
-	// list of all vhost headers
-	hostIngressroutes := make(map[string][]*ingressroutev1.IngressRoute)
+	invalid := make(map[k8s.Object]string)
 	for _, ir := range valid {
-		if vhosts := annotation.ExtraVHosts(ir); vhosts != nil {
-			for _, vh := range vhosts {
-				hostIngressroutes[vh] = append(hostIngressroutes[vh], ir)
+		for _, vh := range annotation.ExtraVHosts(ir) {
+			switch {
+			// empty vhost
+			case strings.Trim(vh, " ") == "":
+				invalid[ir] = "empty value in host annotation"
+			// Don't allow '*' at all, which takes care of the suffix ':*'
+			case strings.Contains(vh, "*"):
+				invalid[ir] = "illegal charaters in host annotation"
+			}
+		}
+		if vhost := ir.Spec.VirtualHost; vhost != nil {
+			passthrough := vhost.TLS != nil && vhost.TLS.SecretName == "" && vhost.TLS.Passthrough
+			if err := validAliases(vhost.Fqdn, vhost.Aliases, passthrough); err != nil {
+				invalid[ir] = err.Error()
+				continue
+			}
+			if err := b.validListenerPort(vhost.Port, vhost.Fqdn, vhost.TLS != nil, passthrough); err != nil {
+				invalid[ir] = err.Error()
+				continue
 			}
+			b.setListenerPort(ir.Namespace, vhost.Fqdn, vhost.Port)
+		}
+	}
+	for obj, msg := range b.hostConflicts {
+		if _, ok := invalid[obj]; !ok {
+			invalid[obj] = msg
+		}
+	}
+	overlaps := b.wildcardOverlaps()
+	for obj, o := range overlaps {
+		if _, ok := invalid[obj]; !ok {
+			invalid[obj] = o.description
 		}
 	}
 
-	// no extra vhosts, no extra validation needed
-	if len(hostIngressroutes) == 0 {
+	if len(invalid) == 0 {
 		return valid
 	}
 
-	// list of all fqdns
-	fqdnIngressroutes := make(map[string][]*ingressroutev1.IngressRoute)
+	var valid2 []*ingressroutev1.IngressRoute
 	for _, ir := range valid {
-		if ir.Spec.VirtualHost != nil {
-			fqdnIngressroutes[ir.Spec.VirtualHost.Fqdn] = append(fqdnIngressroutes[ir.Spec.VirtualHost.Fqdn], ir)
-		}
-	}
-
-	// find the invalid ones
-	//   A) empty vhost
-	//   B) invalid format
-	//   C) vhost against other vhosts
-	//   D) vhost against other fqdns
-	//   E) fqdn against other vhosts
-	// (fqdn against other fqdns is done by upstream)
-	var invalid = make(map[*ingressroutev1.IngressRoute]string)
-	for vh, irs := range hostIngressroutes {
-		// == A)
-		if strings.Trim(vh, " ") == "" {
-			for _, ir := range irs {
-				invalid[ir] = "empty value in host annotation"
-			}
+		msg, ok := invalid[ir]
+		if !ok {
+			valid2 = append(valid2, ir)
 			continue
 		}
-		// == B)
-		// Don't allow '*' at all, which takes care of the suffix ':*'
-		if strings.Contains(vh, "*") {
-			for _, ir := range irs {
-				invalid[ir] = "illegal charaters in host annotation"
-			}
+		if vhost := ir.Spec.VirtualHost; vhost != nil {
+			delete(b.listenerPorts, vhost.Fqdn)
+		}
+		sw, commit := b.WithObject(ir)
+		sw.SetInvalid(ReasonVirtualHostInvalid, msg)
+		commit()
+		b.addOverlapConflicts(ir, msg, overlaps)
+	}
+
+	return valid2
+}
+
+// validHTTPProxiesAdobe ensures the aliases are valid and don't conflict
+// with the hosts of other objects, and the wildcard fqdns don't overlap
+// with the virtual hosts of other namespaces
+func (b *Builder) validHTTPProxiesAdobe() []*projcontour.HTTPProxy {
+	valid := b.validHTTPProxies()
+
+	invalid := make(map[k8s.Object]string)
+	for _, proxy := range valid {
+		vhost := proxy.Spec.VirtualHost
+		if vhost == nil {
+			continue
+		}
+		passthrough := vhost.TLS != nil && vhost.TLS.Passthrough
+		if err := validAliases(vhost.Fqdn, vhost.Aliases, passthrough); err != nil {
+			invalid[proxy] = err.Error()
 			continue
 		}
-		// == C)
-		if len(irs) > 1 {
-			var conflicting []string
-			for _, ir := range irs {
-				conflicting = append(conflicting, ir.Namespace+"/"+ir.Name)
+		if err := b.validListenerPort(vhost.Port, vhost.Fqdn, vhost.TLS != nil, passthrough); err != nil {
+			invalid[proxy] = err.Error()
+			continue
+		}
+		b.setListenerPort(proxy.Namespace, vhost.Fqdn, vhost.Port)
+	}
+	for obj, msg := range b.hostConflicts {
+		if _, ok := invalid[obj]; !ok {
+			invalid[obj] = msg
+		}
+	}
+	overlaps := b.wildcardOverlaps()
+	for obj, o := range overlaps {
+		if _, ok := invalid[obj]; !ok {
+			invalid[obj] = o.description
+		}
+	}
+
+	if len(invalid) == 0 {
+		return valid
+	}
+
+	var valid2 []*projcontour.HTTPProxy
+	for _, proxy := range valid {
+		msg, ok := invalid[proxy]
+		if !ok {
+			valid2 = append(valid2, proxy)
+			continue
+		}
+		delete(b.listenerPorts, proxy.Spec.VirtualHost.Fqdn)
+		sw, commit := b.WithObject(proxy)
+		sw.WithValue("vhost", proxy.Spec.VirtualHost.Fqdn).SetInvalid(ReasonVirtualHostInvalid, msg)
+		commit()
+		b.addOverlapConflicts(proxy, msg, overlaps)
+	}
+
+	return valid2
+}
+
+// validAliases ensures the aliases of a virtual host are DNS names, which
+// rules out stray whitespace and wildcards, other than its fqdn. The
+// aliases of a TLS passthrough virtual host, matched by SNI only, may be
+// wildcards of the form *.example.com.
+func validAliases(fqdn string, aliases []string, passthrough bool) error {
+	for _, alias := range aliases {
+		if passthrough && strings.Contains(alias, "*") {
+			if !isWildcardFQDN(alias) || len(validation.IsDNS1123Subdomain(alias[2:])) > 0 {
+				return fmt.Errorf("Spec.VirtualHost.Aliases %q cannot use wildcards other than a leading \"*.\" label", alias)
 			}
-			sort.Strings(conflicting)
-			msg := fmt.Sprintf("host annotation %q is duplicated in multiple IngressRoutes: %s", vh, strings.Join(conflicting, ", "))
-			for _, ir := range irs {
-				invalid[ir] = msg
+		} else if errs := validation.IsDNS1123Subdomain(alias); len(errs) > 0 {
+			return fmt.Errorf("Spec.VirtualHost.Aliases %q is invalid: %s", alias, strings.Join(errs, ", "))
+		}
+		if alias == fqdn {
+			return fmt.Errorf("Spec.VirtualHost.Aliases %q is the fqdn of the virtual host", alias)
+		}
+	}
+	return nil
+}
+
+// isWildcardFQDN returns whether the fqdn is a wildcard of the form
+// *.example.com, the only form matched by SNI as well as by routes.
+func isWildcardFQDN(fqdn string) bool {
+	return strings.HasPrefix(fqdn, "*.")
+}
+
+// validWildcardFQDN ensures the wildcard of an fqdn, if any, is of the form
+// *.example.com.
+func validWildcardFQDN(fqdn string) error {
+	if !strings.Contains(fqdn, "*") {
+		return nil
+	}
+	if !isWildcardFQDN(fqdn) || len(validation.IsDNS1123Subdomain(fqdn[2:])) > 0 {
+		return fmt.Errorf("Spec.VirtualHost.Fqdn %q cannot use wildcards other than a leading \"*.\" label", fqdn)
+	}
+	return nil
+}
+
+// addOverlapConflicts records that the roots overlapping with obj are
+// affected by obj being invalid for its wildcard fqdn. The roots invalid for
+// their own wildcard fqdn are left out.
+func (b *Builder) addOverlapConflicts(obj k8s.Object, msg string, overlaps map[k8s.Object]overlap) {
+	o, ok := overlaps[obj]
+	if !ok || o.description != msg {
+		return
+	}
+	var others []k8s.Object
+	for _, other := range o.others {
+		if _, ok := overlaps[other]; !ok {
+			others = append(others, other)
+		}
+	}
+	b.addConflicts(obj, msg, others)
+}
+
+// overlap describes why a root whose wildcard fqdn overlaps with the
+// virtual hosts of other namespaces is invalid.
+type overlap struct {
+	description string
+	others      []k8s.Object
+}
+
+// wildcardOverlaps returns why the roots whose wildcard fqdn, or wildcard
+// alias, overlaps with the fqdn or aliases of roots of other namespaces are
+// invalid, as either would capture requests the other namespace expects.
+func (b *Builder) wildcardOverlaps() map[k8s.Object]overlap {
+	type root struct {
+		obj   k8s.Object
+		hosts []string
+	}
+	var roots []root
+	for _, ir := range b.Source.ingressroutes {
+		if vhost := ir.Spec.VirtualHost; vhost != nil && b.rootAllowed(ir.Namespace) {
+			roots = append(roots, root{obj: ir, hosts: append([]string{vhost.Fqdn}, vhost.Aliases...)})
+		}
+	}
+	for _, proxy := range b.Source.httpproxies {
+		if vhost := proxy.Spec.VirtualHost; vhost != nil && b.rootAllowed(proxy.Namespace) {
+			roots = append(roots, root{obj: proxy, hosts: append([]string{vhost.Fqdn}, vhost.Aliases...)})
+		}
+	}
+
+	overlaps := make(map[k8s.Object]overlap)
+	for _, r := range roots {
+		namespace := r.obj.GetObjectMeta().GetNamespace()
+		for i, wildcard := range r.hosts {
+			if !isWildcardFQDN(wildcard) {
+				continue
+			}
+			var overlapping []k8s.Object
+			for _, other := range roots {
+				if other.obj.GetObjectMeta().GetNamespace() == namespace {
+					continue
+				}
+				for _, host := range other.hosts {
+					if overlapsWildcard(wildcard, host) {
+						overlapping = append(overlapping, other.obj)
+						break
+					}
+				}
 			}
+			if len(overlapping) > 0 {
+				field := "fqdn"
+				if i > 0 {
+					field = "alias"
+				}
+				overlaps[r.obj] = overlap{
+					description: fmt.Sprintf("%s %q overlaps with the virtual hosts of other namespaces: %s", field, wildcard, fullNames(overlapping)),
+					others:      overlapping,
+				}
+				break
+			}
+		}
+	}
+	return overlaps
+}
+
+// overlapsWildcard returns whether some host names are matched both by the
+// wildcard and by host, itself a wildcard or not.
+func overlapsWildcard(wildcard, host string) bool {
+	suffix := wildcard[1:]
+	if isWildcardFQDN(host) {
+		return strings.HasSuffix(host[1:], suffix) || strings.HasSuffix(suffix, host[1:])
+	}
+	return strings.HasSuffix(host, suffix)
+}
+
+// appendObject appends obj to objs unless it is already the last one, as
+// the same alias may be set by an object more than once.
+func appendObject(objs []k8s.Object, obj k8s.Object) []k8s.Object {
+	if len(objs) > 0 && objs[len(objs)-1] == obj {
+		return objs
+	}
+	return append(objs, obj)
+}
+
+// ingressHost identifies a host of an Ingress.
+type ingressHost struct {
+	ing  *v1beta1.Ingress
+	host string
+}
+
+// computeHostConflicts records why the roots, IngressRoutes and HTTPProxies,
+// and the hosts of the Ingresses conflicting with the hosts of other objects
+// of any kind are invalid: the aliases duplicated in other roots, or being
+// the fqdn of another root or a host of an Ingress, as well as those fqdns
+// and hosts. (fqdns used by several roots of the same kind are reported by
+// upstream, while the roots and Ingresses sharing a fqdn are merged as
+// upstream does.)
+func (b *Builder) computeHostConflicts() {
+	b.hostConflicts = make(map[k8s.Object]string)
+	b.ingressHostConflicts = make(map[ingressHost]string)
+
+	aliases := make(map[string][]k8s.Object)
+	fqdns := make(map[string][]k8s.Object)
+	hosts := make(map[string][]*v1beta1.Ingress)
+	for _, ir := range b.Source.ingressroutes {
+		if !b.rootAllowed(ir.Namespace) {
 			continue
 		}
-		// == D)
-		if firs, ok := fqdnIngressroutes[vh]; ok {
-			var conflicting3 []string
-			for _, ir := range firs {
-				conflicting3 = append(conflicting3, ir.Namespace+"/"+ir.Name)
+		for _, vh := range annotation.ExtraVHosts(ir) {
+			if strings.Trim(vh, " ") != "" && !strings.Contains(vh, "*") {
+				aliases[vh] = appendObject(aliases[vh], ir)
+			}
+		}
+		if vhost := ir.Spec.VirtualHost; vhost != nil {
+			fqdns[vhost.Fqdn] = append(fqdns[vhost.Fqdn], ir)
+			for _, alias := range vhost.Aliases {
+				aliases[alias] = appendObject(aliases[alias], ir)
+			}
+		}
+	}
+	for _, proxy := range b.Source.httpproxies {
+		if vhost := proxy.Spec.VirtualHost; vhost != nil && b.rootAllowed(proxy.Namespace) {
+			fqdns[vhost.Fqdn] = append(fqdns[vhost.Fqdn], proxy)
+			for _, alias := range vhost.Aliases {
+				aliases[alias] = appendObject(aliases[alias], proxy)
+			}
+		}
+	}
+	for _, ing := range b.Source.ingresses {
+		for _, rule := range ing.Spec.Rules {
+			if rule.Host != "" {
+				hosts[rule.Host] = appendIngress(hosts[rule.Host], ing)
+			}
+		}
+		for _, tls := range ing.Spec.TLS {
+			for _, host := range tls.Hosts {
+				hosts[host] = appendIngress(hosts[host], ing)
 			}
-			sort.Strings(conflicting3)
-			msg := fmt.Sprintf("host annotation %q is duplicated with a fqdn in other IngressRoutes: %s", vh, strings.Join(conflicting3, ", "))
-			for _, ir := range irs {
-				invalid[ir] = msg
+		}
+	}
+
+	// the first conflict found is recorded, the hosts being sorted for
+	// test stability
+	invalid := func(objs []k8s.Object, format string, args ...interface{}) {
+		for _, obj := range objs {
+			if _, ok := b.hostConflicts[obj]; !ok {
+				b.hostConflicts[obj] = fmt.Sprintf(format, args...)
 			}
 		}
 	}
-	for fqdn, irs := range fqdnIngressroutes {
-		// if we already have an issue with this IngressRoute, skip
-		if _, seen := invalid[irs[0]]; seen {
+	for _, alias := range sortedHosts(aliases) {
+		objs := aliases[alias]
+		if len(objs) > 1 {
+			invalid(objs, "alias %q is duplicated in multiple %s", alias, describeObjects(objs))
+		}
+		var others []k8s.Object
+		for _, other := range fqdns[alias] {
+			if !containsObject(objs, other) {
+				others = append(others, other)
+			}
+		}
+		for _, ing := range hosts[alias] {
+			others = append(others, ing)
+		}
+		if len(others) == 0 {
 			continue
 		}
-		// == E)
-		if hirs, ok := hostIngressroutes[fqdn]; ok {
-			var conflicting3 []string
-			for _, ir := range hirs {
-				conflicting3 = append(conflicting3, ir.Namespace+"/"+ir.Name)
+		invalid(objs, "alias %q is duplicated with a fqdn in other %s", alias, describeObjects(others))
+		msg := fmt.Sprintf("fqdn %q is duplicated with an alias in other %s", alias, describeObjects(objs))
+		for _, other := range others {
+			if ing, ok := other.(*v1beta1.Ingress); ok {
+				key := ingressHost{ing: ing, host: alias}
+				if _, ok := b.ingressHostConflicts[key]; !ok {
+					b.ingressHostConflicts[key] = msg
+				}
+				continue
 			}
-			sort.Strings(conflicting3)
-			msg := fmt.Sprintf("fqdn %q is duplicated with a host annotation in other IngressRoutes: %s", fqdn, strings.Join(conflicting3, ", "))
-			for _, ir := range irs {
-				invalid[ir] = msg
+			invalid([]k8s.Object{other}, msg)
+		}
+	}
+}
+
+// hostConflict returns why the host of the Ingress is invalid, if it
+// conflicts with the alias of a root.
+func (b *Builder) hostConflict(ing *v1beta1.Ingress, host string) (string, bool) {
+	msg, ok := b.ingressHostConflicts[ingressHost{ing: ing, host: host}]
+	return msg, ok
+}
+
+// sortedHosts returns the sorted hosts of the map.
+func sortedHosts(m map[string][]k8s.Object) []string {
+	hosts := make([]string, 0, len(m))
+	for host := range m {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// appendIngress appends ing to ings unless it is already the last one, as
+// the same host may be set by an Ingress more than once.
+func appendIngress(ings []*v1beta1.Ingress, ing *v1beta1.Ingress) []*v1beta1.Ingress {
+	if len(ings) > 0 && ings[len(ings)-1] == ing {
+		return ings
+	}
+	return append(ings, ing)
+}
+
+// containsObject returns whether obj is one of objs.
+func containsObject(objs []k8s.Object, obj k8s.Object) bool {
+	for _, o := range objs {
+		if o == obj {
+			return true
+		}
+	}
+	return false
+}
+
+// describeObjects returns the kind and the sorted namespace/name of the
+// objects, or the namespace/name of each qualified by its kind if the
+// objects are of different kinds.
+func describeObjects(objs []k8s.Object) string {
+	kind := k8s.KindOf(objs[0])
+	for _, obj := range objs[1:] {
+		if k8s.KindOf(obj) != kind {
+			var names []string
+			for _, obj := range objs {
+				meta := obj.GetObjectMeta()
+				names = append(names, k8s.KindOf(obj)+" "+meta.GetNamespace()+"/"+meta.GetName())
 			}
+			sort.Strings(names) // sort for test stability
+			return "objects: " + strings.Join(names, ", ")
 		}
 	}
+	return pluralKind(kind) + ": " + fullNames(objs)
+}
 
-	if len(invalid) == 0 {
-		return valid
+// pluralKind returns the plural of the kind of a root or an Ingress.
+func pluralKind(kind string) string {
+	switch {
+	case strings.HasSuffix(kind, "y"):
+		return strings.TrimSuffix(kind, "y") + "ies"
+	case strings.HasSuffix(kind, "s"):
+		return kind + "es"
+	default:
+		return kind + "s"
 	}
+}
 
-	var valid2 []*ingressroutev1.IngressRoute
-	for _, ir := range valid {
-		msg, ok := invalid[ir]
-		if !ok {
-			valid2 = append(valid2, ir)
-			continue
+// fullNames returns the sorted namespace/name of the objects.
+func fullNames(objs []k8s.Object) string {
+	var names []string
+	for _, obj := range objs {
+		meta := obj.GetObjectMeta()
+		names = append(names, meta.GetNamespace()+"/"+meta.GetName())
+	}
+	sort.Strings(names) // sort for test stability
+	return strings.Join(names, ", ")
+}
+
+// addAliases adds the aliases of a root to the host names its virtual
+// hosts are routed on, and for TLS to the server names of its secure
+// virtual host.
+func (b *Builder) addAliases(host string, aliases []string, secure bool) {
+	if len(aliases) == 0 {
+		return
+	}
+	vhost := b.lookupVirtualHost(host)
+	vhost.HostNames = append(vhost.HostNames, aliases...)
+	if secure {
+		svhost := b.lookupSecureVirtualHost(host)
+		svhost.HostNames = append(svhost.HostNames, aliases...)
+		svhost.Aliases = aliases
+	}
+}
+
+// priorityClusters arranges the clusters of a route according to the
+// priority of their services. If no service has a priority the clusters
+// are returned unchanged. Otherwise the cluster of lowest priority becomes
+// the only cluster of the route, with the others as its failover clusters
+// in increasing order of priority.
+func priorityClusters(clusters []*Cluster, priorities []int) ([]*Cluster, error) {
+	prioritized := false
+	for _, p := range priorities {
+		if p < 0 {
+			return nil, fmt.Errorf("service priority must be >= 0")
 		}
-		sw, commit := b.WithObject(ir)
-		sw.SetInvalid(msg)
-		commit()
+		prioritized = prioritized || p > 0
+	}
+	if !prioritized {
+		return clusters, nil
 	}
 
-	return valid2
+	idx := make([]int, len(clusters))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		return priorities[idx[a]] < priorities[idx[b]]
+	})
+	for i := 1; i < len(idx); i++ {
+		prev, cur := idx[i-1], idx[i]
+		if priorities[prev] == priorities[cur] {
+			return nil, fmt.Errorf("services %q and %q have the same priority %d",
+				clusters[prev].Upstream.Name, clusters[cur].Upstream.Name, priorities[cur])
+		}
+	}
+
+	primary := clusters[idx[0]]
+	for _, i := range idx[1:] {
+		primary.Failover = append(primary.Failover, clusters[i])
+	}
+	return []*Cluster{primary}, nil
+}
+
+// validHealthCheckPolicy ensures the HTTP health check policy of the
+// cluster can be applied to its upstream.
+func validHealthCheckPolicy(c *Cluster) error {
+	hc := c.HTTPHealthCheckPolicy
+	if hc == nil {
+		return nil
+	}
+	for _, r := range hc.ExpectedStatuses {
+		if r.Start < 100 || r.End > 600 || r.Start >= r.End {
+			return fmt.Errorf("health check expected status range [%d, %d) is invalid", r.Start, r.End)
+		}
+	}
+	if hc.InitialJitter != nil && *hc.InitialJitter < 0 {
+		return fmt.Errorf("health check initial jitter must be >= 0")
+	}
+	if hc.GRPC && c.Protocol != "h2" && c.Protocol != "h2c" {
+		return fmt.Errorf("gRPC health check requires the h2 or h2c protocol")
+	}
+	return nil
+}
+
+// lookupClientCertificate returns the client certificate presented to the
+// upstream of a service speaking protocol: the Secret named by the service,
+// which must be delegated if it lives in another namespace, or else the
+// upstream client certificate configured globally.
+func (b *Builder) lookupClientCertificate(secretName, protocol, namespace string) (*Secret, error) {
+	if protocol != "tls" && protocol != "h2" {
+		if secretName != "" {
+			return nil, fmt.Errorf("client certificate requires the tls or h2 protocol")
+		}
+		return nil, nil
+	}
+
+	if secretName != "" {
+		m := splitSecret(secretName, namespace)
+		sec, err := b.lookupSecret(m, validSecret)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate Secret %q is invalid: %s", secretName, err)
+		}
+		if !b.delegationPermitted(m, namespace) {
+			return nil, fmt.Errorf("client certificate Secret %q certificate delegation not permitted", secretName)
+		}
+		return sec, nil
+	}
+
+	if b.UpstreamClientCertificate == nil {
+		return nil, nil
+	}
+	sec, err := b.lookupSecret(*b.UpstreamClientCertificate, validSecret)
+	if err != nil {
+		return nil, fmt.Errorf("upstream client certificate Secret %q is invalid: %s", b.UpstreamClientCertificate, err)
+	}
+	return sec, nil
+}
+
+// maxRingSize is the largest hash ring supported by Envoy.
+const maxRingSize = 8 * 1024 * 1024
+
+// validLoadBalancerOptions ensures the load balancer options of the
+// cluster apply to its load balancer policy.
+func validLoadBalancerOptions(c *Cluster) error {
+	lbo := c.LoadBalancerOptions
+	if lbo == nil {
+		return nil
+	}
+	if lbo.MinimumRingSize > 0 || lbo.MaximumRingSize > 0 {
+		if c.LoadBalancerPolicy != "RingHash" {
+			return fmt.Errorf("ring hash options require the RingHash load balancer strategy")
+		}
+		if lbo.MaximumRingSize > maxRingSize {
+			return fmt.Errorf("maximum ring size must be <= %d", maxRingSize)
+		}
+		if lbo.MaximumRingSize > 0 && lbo.MinimumRingSize > lbo.MaximumRingSize {
+			return fmt.Errorf("minimum ring size %d is greater than maximum ring size %d", lbo.MinimumRingSize, lbo.MaximumRingSize)
+		}
+	}
+	if lbo.ChoiceCount > 0 {
+		if c.LoadBalancerPolicy != "WeightedLeastRequest" {
+			return fmt.Errorf("least request options require the WeightedLeastRequest load balancer strategy")
+		}
+		if lbo.ChoiceCount < 2 {
+			return fmt.Errorf("least request choice count must be >= 2")
+		}
+	}
+	return nil
+}
+
+// hashLoadBalancerPolicy returns the load balancer policy of a route with
+// hash policies. Only the RingHash and Maglev strategies hash requests, any
+// other strategy specified is replaced by RingHash and returned as ignored.
+func hashLoadBalancerPolicy(lbp *projcontour.LoadBalancerPolicy) (policy, ignored string) {
+	switch strategy := loadBalancerPolicy(lbp); strategy {
+	case "RingHash", "Maglev":
+		return strategy, ""
+	default:
+		if lbp != nil {
+			ignored = lbp.Strategy
+		}
+		return "RingHash", ignored
+	}
+}
+
+// tlsCiphers are the TLS 1.2 cipher suites Envoy may offer.
+var tlsCiphers = map[string]bool{
+	"ECDHE-ECDSA-AES128-GCM-SHA256": true,
+	"ECDHE-RSA-AES128-GCM-SHA256":   true,
+	"ECDHE-ECDSA-CHACHA20-POLY1305": true,
+	"ECDHE-RSA-CHACHA20-POLY1305":   true,
+	"ECDHE-ECDSA-AES128-SHA":        true,
+	"ECDHE-RSA-AES128-SHA":          true,
+	"AES128-GCM-SHA256":             true,
+	"AES128-SHA":                    true,
+	"ECDHE-ECDSA-AES256-GCM-SHA384": true,
+	"ECDHE-RSA-AES256-GCM-SHA384":   true,
+	"ECDHE-ECDSA-AES256-SHA":        true,
+	"ECDHE-RSA-AES256-SHA":          true,
+	"AES256-GCM-SHA384":             true,
+	"AES256-SHA":                    true,
+}
+
+// ecdhCurves are the ECDH curves Envoy may offer.
+var ecdhCurves = map[string]bool{
+	"X25519": true,
+	"P-256":  true,
+	"P-384":  true,
+	"P-521":  true,
+}
+
+// ValidCipherSuites ensures Envoy can offer the cipher suites, either
+// single or in equal preference groups written [CIPHER1|CIPHER2].
+func ValidCipherSuites(cipherSuites []string) error {
+	for _, cipher := range cipherSuites {
+		group := cipher
+		if strings.HasPrefix(cipher, "[") && strings.HasSuffix(cipher, "]") {
+			group = cipher[1 : len(cipher)-1]
+		}
+		for _, c := range strings.Split(group, "|") {
+			if !tlsCiphers[c] {
+				return fmt.Errorf("cipher suite %q is not supported", cipher)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidECDHCurves ensures Envoy can offer the ECDH curves.
+func ValidECDHCurves(curves []string) error {
+	for _, curve := range curves {
+		if !ecdhCurves[curve] {
+			return fmt.Errorf("ECDH curve %q is not supported", curve)
+		}
+	}
+	return nil
+}
+
+// upstreamTLS returns the TLS parameters of the connections to the upstream
+// of a service speaking protocol: those set on the service, each of which
+// overrides the one configured globally.
+func (b *Builder) upstreamTLS(service *projcontour.UpstreamTLS, protocol string) (*UpstreamTLS, error) {
+	if protocol != "tls" && protocol != "h2" {
+		if service != nil {
+			return nil, fmt.Errorf("upstream TLS parameters require the tls or h2 protocol")
+		}
+		return nil, nil
+	}
+
+	var p projcontour.UpstreamTLS
+	if b.UpstreamTLS != nil {
+		p = *b.UpstreamTLS
+	}
+	if service != nil {
+		if service.MinimumProtocolVersion != "" {
+			p.MinimumProtocolVersion = service.MinimumProtocolVersion
+		}
+		if service.MaximumProtocolVersion != "" {
+			p.MaximumProtocolVersion = service.MaximumProtocolVersion
+		}
+		if len(service.CipherSuites) > 0 {
+			p.CipherSuites = service.CipherSuites
+		}
+		if len(service.ALPNProtocols) > 0 {
+			p.ALPNProtocols = service.ALPNProtocols
+		}
+	}
+	return newUpstreamTLS(&p)
+}
+
+// ValidUpstreamTLS ensures the upstream TLS parameters can be configured
+// on Envoy.
+func ValidUpstreamTLS(p *projcontour.UpstreamTLS) error {
+	_, err := newUpstreamTLS(p)
+	return err
+}
+
+// newUpstreamTLS validates the upstream TLS parameters and converts them
+// to their DAG form, which is nil if all parameters are left unset.
+func newUpstreamTLS(p *projcontour.UpstreamTLS) (*UpstreamTLS, error) {
+	minVersion, err := tlsProtocolVersion(p.MinimumProtocolVersion)
+	if err != nil {
+		return nil, fmt.Errorf("upstream TLS minimum protocol version: %s", err)
+	}
+	maxVersion, err := tlsProtocolVersion(p.MaximumProtocolVersion)
+	if err != nil {
+		return nil, fmt.Errorf("upstream TLS maximum protocol version: %s", err)
+	}
+	if minVersion != envoy_api_v2_auth.TlsParameters_TLS_AUTO &&
+		maxVersion != envoy_api_v2_auth.TlsParameters_TLS_AUTO && minVersion > maxVersion {
+		return nil, fmt.Errorf("upstream TLS minimum protocol version %q is greater than maximum protocol version %q",
+			p.MinimumProtocolVersion, p.MaximumProtocolVersion)
+	}
+	if err := ValidCipherSuites(p.CipherSuites); err != nil {
+		return nil, fmt.Errorf("upstream TLS %s", err)
+	}
+	for _, proto := range p.ALPNProtocols {
+		if proto == "" {
+			return nil, fmt.Errorf("upstream TLS ALPN protocol must not be empty")
+		}
+	}
+
+	if minVersion == envoy_api_v2_auth.TlsParameters_TLS_AUTO && maxVersion == envoy_api_v2_auth.TlsParameters_TLS_AUTO &&
+		len(p.CipherSuites) == 0 && len(p.ALPNProtocols) == 0 {
+		return nil, nil
+	}
+	return &UpstreamTLS{
+		MinProtoVersion: minVersion,
+		MaxProtoVersion: maxVersion,
+		CipherSuites:    p.CipherSuites,
+		ALPNProtocols:   p.ALPNProtocols,
+	}, nil
+}
+
+// tlsProtocolVersion parses a TLS protocol version, an empty version
+// leaving the Envoy default.
+func tlsProtocolVersion(version string) (envoy_api_v2_auth.TlsParameters_TlsProtocol, error) {
+	switch version {
+	case "":
+		return envoy_api_v2_auth.TlsParameters_TLS_AUTO, nil
+	case "1.2":
+		return envoy_api_v2_auth.TlsParameters_TLSv1_2, nil
+	case "1.3":
+		return envoy_api_v2_auth.TlsParameters_TLSv1_3, nil
+	default:
+		return envoy_api_v2_auth.TlsParameters_TLS_AUTO, fmt.Errorf("%q is not one of 1.2 or 1.3", version)
+	}
+}
+
+// lookupSecondaryCertificate returns the secondary certificate of a secure
+// vhost presenting the primary certificate: the Secret named, which must be
+// delegated if it lives in another namespace, and must hold a certificate
+// of the other key type, RSA or ECDSA.
+func (b *Builder) lookupSecondaryCertificate(primary *Secret, secretName, namespace string) (*Secret, error) {
+	m := splitSecret(secretName, namespace)
+	sec, err := b.lookupSecret(m, validSecret)
+	if err != nil {
+		return nil, fmt.Errorf("secondary Secret %q is invalid: %s", secretName, err)
+	}
+	if !b.delegationPermitted(m, namespace) {
+		return nil, fmt.Errorf("secondary Secret %q certificate delegation not permitted", secretName)
+	}
+
+	primaryAlgorithm, err := publicKeyAlgorithm(primary)
+	if err != nil {
+		return nil, fmt.Errorf("Secret %q is invalid: %s", primary.Name(), err)
+	}
+	secondaryAlgorithm, err := publicKeyAlgorithm(sec)
+	if err != nil {
+		return nil, fmt.Errorf("secondary Secret %q is invalid: %s", secretName, err)
+	}
+	if primaryAlgorithm == secondaryAlgorithm {
+		return nil, fmt.Errorf("secondary Secret %q holds a %s certificate like the primary Secret", secretName, primaryAlgorithm)
+	}
+	return sec, nil
+}
+
+// publicKeyAlgorithm returns the public key algorithm, RSA or ECDSA, of the
+// leaf certificate of the Secret.
+func publicKeyAlgorithm(s *Secret) (x509.PublicKeyAlgorithm, error) {
+	block, _ := pem.Decode(s.Cert())
+	if block == nil {
+		return x509.UnknownPublicKeyAlgorithm, errors.New("failed to locate certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return x509.UnknownPublicKeyAlgorithm, err
+	}
+	switch cert.PublicKeyAlgorithm {
+	case x509.RSA, x509.ECDSA:
+		return cert.PublicKeyAlgorithm, nil
+	default:
+		return x509.UnknownPublicKeyAlgorithm, fmt.Errorf("certificate public key algorithm %s is neither RSA nor ECDSA", cert.PublicKeyAlgorithm)
+	}
+}
+
+func (b *Builder) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+// certificateExpired returns true if the certificate of the Secret has expired.
+func (b *Builder) certificateExpired(sec *Secret) bool {
+	notAfter, ok := sec.NotAfter()
+	return ok && !b.clock().Before(notAfter)
+}
+
+// checkCertificateExpiry checks the expiry of the certificate of the Secret
+// a secure vhost presents. An expired certificate is an error if expired
+// certificates are refused, else a warning, as is a certificate expiring
+// within the CertificateExpiryWarning period.
+func (b *Builder) checkCertificateExpiry(sw *ObjectStatusWriter, secretName string, sec *Secret) error {
+	notAfter, ok := sec.NotAfter()
+	if !ok {
+		return nil
+	}
+	expiry := notAfter.UTC().Format(time.RFC3339)
+	switch now := b.clock(); {
+	case !now.Before(notAfter):
+		if b.RefuseExpiredCertificates {
+			return fmt.Errorf("Secret %q certificate expired at %s", secretName, expiry)
+		}
+		sw.SetWarning(ReasonCertificateExpiring, "Secret %q certificate expired at %s", secretName, expiry)
+	case notAfter.Sub(now) < b.CertificateExpiryWarning:
+		sw.SetWarning(ReasonCertificateExpiring, "Secret %q certificate expires at %s", secretName, expiry)
+	}
+	return nil
+}
+
+// tcpProxyOptions sets the idle timeout, connect attempts and access log
+// of the HTTPProxy tcpproxy on proxy.
+func tcpProxyOptions(proxy *TCPProxy, tcpproxy *projcontour.TCPProxy) error {
+	switch tcpproxy.IdleTimeout {
+	case "":
+	case "infinity":
+		proxy.IdleTimeout = -1
+	default:
+		d, err := time.ParseDuration(tcpproxy.IdleTimeout)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("idleTimeout %q must be a positive duration or \"infinity\"", tcpproxy.IdleTimeout)
+		}
+		proxy.IdleTimeout = d
+	}
+	proxy.MaxConnectAttempts = tcpproxy.MaxConnectAttempts
+	proxy.DisableAccessLog = tcpproxy.DisableAccessLog
+	return nil
+}
+
+// ingressrouteTCPProxyOptions sets the idle timeout, connect attempts and
+// access log of the IngressRoute tcpproxy on proxy.
+func ingressrouteTCPProxyOptions(proxy *TCPProxy, tcpproxy *ingressroutev1.TCPProxy) error {
+	if tcpproxy.IdleTimeout != nil {
+		d, err := ptypes.Duration(&tcpproxy.IdleTimeout.Duration)
+		if err != nil || d < 0 {
+			return fmt.Errorf("idleTimeout must be a positive duration, or 0s to disable it")
+		}
+		proxy.IdleTimeout = d
+		if d == 0 {
+			proxy.IdleTimeout = -1
+		}
+	}
+	proxy.MaxConnectAttempts = tcpproxy.MaxConnectAttempts
+	proxy.DisableAccessLog = tcpproxy.DisableAccessLog
+	return nil
+}
+
+// ingressrouteTCPHealthCheckPolicy returns the health check of a service of
+// a tcpproxy, which connects to the upstream only.
+func ingressrouteTCPHealthCheckPolicy(hc *ingressroutev1.HealthCheck) *TCPHealthCheckPolicy {
+	if hc == nil {
+		return nil
+	}
+	return &TCPHealthCheckPolicy{
+		Interval:           time.Duration(hc.IntervalSeconds) * time.Second,
+		Timeout:            time.Duration(hc.TimeoutSeconds) * time.Second,
+		UnhealthyThreshold: uint32(hc.UnhealthyThresholdCount),
+		HealthyThreshold:   uint32(hc.HealthyThresholdCount),
+	}
 }
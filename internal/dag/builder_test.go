@@ -1097,6 +1097,39 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// ir1g passes TLS through for a wildcard and its aliases to weighted,
+	// health checked, services
+	ir1g := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard-tcp",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn:    "*.kuard.example.com",
+				Aliases: []string{"kuard.example.com", "*.kuard.example.net"},
+				TLS: &ingressroutev1.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &ingressroutev1.TCPProxy{
+				Services: []ingressroutev1.Service{{
+					Name:   "kuard",
+					Port:   8080,
+					Weight: 90,
+					HealthCheck: &ingressroutev1.HealthCheck{
+						IntervalSeconds:         5,
+						UnhealthyThresholdCount: 3,
+					},
+				}, {
+					Name:   "kuarder",
+					Port:   8080,
+					Weight: 10,
+				}},
+			},
+		},
+	}
+
 	// ir2 is like ir1 but refers to two backend services
 	ir2 := &ingressroutev1.IngressRoute{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1203,6 +1236,26 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// ir6a is like ir6 but filters its access log
+	ir6a := ir6.DeepCopy()
+	ir6a.Spec.VirtualHost.AccessLog = &ingressroutev1.AccessLog{
+		Filter: &ingressroutev1.AccessLogFilter{
+			StatusCodes: []string{"500-599"},
+			Headers: []projcontour.HeaderCondition{{
+				Name:        "user-agent",
+				NotContains: "kube-probe",
+			}},
+		},
+	}
+
+	// ir6b is like ir6 but writes its access log to its own file
+	ir6b := ir6.DeepCopy()
+	ir6b.Spec.VirtualHost.AccessLog = &ingressroutev1.AccessLog{
+		Path:    "/var/log/envoy/foo.log",
+		Fields:  []string{"@timestamp", "path", "response_code"},
+		Headers: []string{"x-adobe-tenant"},
+	}
+
 	// ir7 has TLS and specifies min tls version of 1.2
 	ir7 := &ingressroutev1.IngressRoute{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1955,6 +2008,41 @@ func TestDAGInsert(t *testing.T) {
 		},
 	}
 
+	// proxy1g passes TLS through to weighted services, one of which
+	// overrides the health check of the tcpproxy.
+	proxy1g := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard-tcp",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "kuard.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				HealthCheckPolicy: &projcontour.TCPHealthCheckPolicy{
+					IntervalSeconds: 10,
+				},
+				Services: []projcontour.Service{{
+					Name:   "kuard",
+					Port:   8080,
+					Weight: 90,
+				}, {
+					Name:   "kuarder",
+					Port:   8080,
+					Weight: 10,
+					HealthCheckPolicy: &projcontour.TCPHealthCheckPolicy{
+						IntervalSeconds:         5,
+						UnhealthyThresholdCount: 3,
+					},
+				}},
+			},
+		},
+	}
+
 	proxy1b := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "example-com",
@@ -3917,6 +4005,38 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert ingressroute with tcp forward w/ passthrough, wildcard aliases and weighted services": {
+			objs: []interface{}{
+				ir1g, s1, s2,
+			},
+			want: listeners(
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name:      "*.kuard.example.com",
+								HostNames: []string{"kuard.example.com", "*.kuard.example.net"},
+							},
+							Aliases: []string{"kuard.example.com", "*.kuard.example.net"},
+							TCPProxy: &TCPProxy{
+								Clusters: []*Cluster{{
+									Upstream: service(s1),
+									Weight:   90,
+									TCPHealthCheckPolicy: &TCPHealthCheckPolicy{
+										Interval:           5 * time.Second,
+										UnhealthyThreshold: 3,
+									},
+								}, {
+									Upstream: service(s2),
+									Weight:   10,
+								}},
+							},
+						},
+					),
+				},
+			),
+		},
 		"insert root ingress route and delegate ingress route for a tcp proxy": {
 			objs: []interface{}{
 				ir1d, s6, ir1c,
@@ -4002,6 +4122,42 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert ingressroute with access log filter": {
+			objs: []interface{}{
+				ir6a, s1, sec1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						accessLogVirtualHost(virtualhost("foo.com", routeUpgrade("/", service(s1))), accessLogFilter),
+					),
+				}, &Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						accessLogSecureVirtualHost(securevirtualhost("foo.com", sec1, routeUpgrade("/", service(s1))), accessLogFilter),
+					),
+				},
+			),
+		},
+		"insert ingressroute with access log path and fields": {
+			objs: []interface{}{
+				ir6b, s1, sec1,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						accessLogVirtualHost(virtualhost("foo.com", routeUpgrade("/", service(s1))), accessLogFile),
+					),
+				}, &Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						accessLogSecureVirtualHost(securevirtualhost("foo.com", sec1, routeUpgrade("/", service(s1))), accessLogFile),
+					),
+				},
+			),
+		},
 		"insert ingressroute with TLS one insecure": {
 			objs: []interface{}{
 				ir14, s1, sec1,
@@ -5883,6 +6039,39 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"insert proxy with tcp forward w/ passthrough and a service overriding the health check": {
+			objs: []interface{}{
+				proxy1g, s1, s2,
+			},
+			want: listeners(
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "kuard.example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters: []*Cluster{{
+									Upstream: service(s1),
+									Weight:   90,
+									TCPHealthCheckPolicy: &TCPHealthCheckPolicy{
+										Interval: 10 * time.Second,
+									},
+								}, {
+									Upstream: service(s2),
+									Weight:   10,
+									TCPHealthCheckPolicy: &TCPHealthCheckPolicy{
+										Interval:           5 * time.Second,
+										UnhealthyThreshold: 3,
+									},
+								}},
+							},
+						},
+					),
+				},
+			),
+		},
 		// issue 1952
 		"insert proxy with tcp forward without TLS termination w/ passthrough and 301 upgrade of port 80": {
 			objs: []interface{}{
@@ -6144,6 +6333,96 @@ func TestDAGInsert(t *testing.T) {
 				},
 			),
 		},
+		"httpproxy tcpproxy w/ idle timeout, connect attempts and no access log": {
+			objs: []interface{}{
+				s9,
+				&projcontour.HTTPProxy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "nginx",
+						Namespace: "default",
+					},
+					Spec: projcontour.HTTPProxySpec{
+						VirtualHost: &projcontour.VirtualHost{
+							Fqdn: "example.com",
+							TLS: &projcontour.TLS{
+								Passthrough: true,
+							},
+						},
+						TCPProxy: &projcontour.TCPProxy{
+							Services: []projcontour.Service{{
+								Name: s9.Name,
+								Port: 80,
+							}},
+							IdleTimeout:        "30m",
+							MaxConnectAttempts: 3,
+							DisableAccessLog:   true,
+						},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters:           clusters(service(s9)),
+								IdleTimeout:        30 * time.Minute,
+								MaxConnectAttempts: 3,
+								DisableAccessLog:   true,
+							},
+						},
+					),
+				},
+			),
+		},
+		"ingressroute tcpproxy w/ idle timeout disabled": {
+			objs: []interface{}{
+				s9,
+				&ingressroutev1.IngressRoute{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "nginx",
+						Namespace: "default",
+					},
+					Spec: ingressroutev1.IngressRouteSpec{
+						VirtualHost: &ingressroutev1.VirtualHost{
+							Fqdn: "example.com",
+							TLS: &ingressroutev1.TLS{
+								Passthrough: true,
+							},
+						},
+						TCPProxy: &ingressroutev1.TCPProxy{
+							Services: []ingressroutev1.Service{{
+								Name: s9.Name,
+								Port: 80,
+							}},
+							IdleTimeout:        &ingressroutev1.Duration{},
+							MaxConnectAttempts: 2,
+						},
+					},
+				},
+			},
+			want: listeners(
+				&Listener{
+					Port: 443,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters:           clusters(service(s9)),
+								IdleTimeout:        -1,
+								MaxConnectAttempts: 2,
+							},
+						},
+					),
+				},
+			),
+		},
 		// Assert that a route in IngressRoute takes precedence over Ingress.
 		// The service is different in proxy1f so we can tell which one gets priority.
 		"Ingress then IngressRoute with identical details, except referencing s2": {
@@ -6879,6 +7158,7 @@ func TestDAGInsert(t *testing.T) {
 				Source: KubernetesCache{
 					FieldLogger: testLogger(t),
 				},
+				AccessLogPaths: []string{"/var/log/envoy/"},
 			}
 			if adobe.ShouldSkipTest(name) {
 				t.SkipNow()
@@ -7533,6 +7813,542 @@ func TestSplitSecret(t *testing.T) {
 	}
 }
 
+func TestBuilderUpstreamTLS(t *testing.T) {
+	global := &projcontour.UpstreamTLS{
+		MinimumProtocolVersion: "1.2",
+		CipherSuites:           []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+	}
+	tests := map[string]struct {
+		global   *projcontour.UpstreamTLS
+		service  *projcontour.UpstreamTLS
+		protocol string
+		want     *UpstreamTLS
+		wantErr  bool
+	}{
+		"not configured": {
+			protocol: "tls",
+			want:     nil,
+		},
+		"global parameters": {
+			global:   global,
+			protocol: "h2",
+			want: &UpstreamTLS{
+				MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+				CipherSuites:    []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+			},
+		},
+		"global parameters ignored without tls": {
+			global:   global,
+			protocol: "h2c",
+			want:     nil,
+		},
+		"service overrides global parameters": {
+			global: global,
+			service: &projcontour.UpstreamTLS{
+				MinimumProtocolVersion: "1.3",
+				ALPNProtocols:          []string{"http/1.1"},
+			},
+			protocol: "tls",
+			want: &UpstreamTLS{
+				MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_3,
+				CipherSuites:    []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+				ALPNProtocols:   []string{"http/1.1"},
+			},
+		},
+		"minimum greater than maximum": {
+			global: global,
+			service: &projcontour.UpstreamTLS{
+				MinimumProtocolVersion: "1.3",
+				MaximumProtocolVersion: "1.2",
+			},
+			protocol: "tls",
+			wantErr:  true,
+		},
+		"service parameters without tls": {
+			service: &projcontour.UpstreamTLS{
+				MinimumProtocolVersion: "1.2",
+			},
+			protocol: "",
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := Builder{UpstreamTLS: tc.global}
+			got, err := b.upstreamTLS(tc.service, tc.protocol)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tc.wantErr, err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestBuilderAdditionalListeners(t *testing.T) {
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	proxy := func(name, fqdn string, port int) *projcontour.HTTPProxy {
+		return &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn: fqdn,
+					Port: port,
+				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+	}
+	passthrough := func(name, fqdn string, port int) *projcontour.HTTPProxy {
+		p := proxy(name, fqdn, port)
+		p.Spec.VirtualHost.TLS = &projcontour.TLS{Passthrough: true}
+		p.Spec.Routes = nil
+		p.Spec.TCPProxy = &projcontour.TCPProxy{
+			Services: []projcontour.Service{{
+				Name: "kuard",
+				Port: 8080,
+			}},
+		}
+		return p
+	}
+
+	ir := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ingressroute",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "status.example.com",
+				Port: 9080,
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	admin := AdditionalListener{Name: "admin", Address: "127.0.0.1", Port: 9080, Protocol: ListenerProtocolHTTP}
+	mqtt := AdditionalListener{Name: "mqtt", Port: 8883, Protocol: ListenerProtocolHTTPS}
+
+	tests := map[string]struct {
+		objs     []interface{}
+		want     []Vertex
+		statuses map[string]string
+	}{
+		"roots selecting the additional listeners": {
+			objs: []interface{}{
+				s1,
+				proxy("default-port", "www.example.com", 0),
+				proxy("admin", "admin.example.com", 9080),
+				passthrough("mqtt", "mqtt.example.com", 8883),
+				ir,
+			},
+			want: listeners(
+				&Listener{
+					Port: 80,
+					VirtualHosts: virtualhosts(
+						virtualhost("www.example.com", prefixroute("/", service(s1))),
+					),
+				},
+				&Listener{
+					Name:    "admin",
+					Address: "127.0.0.1",
+					Port:    9080,
+					VirtualHosts: virtualhosts(
+						virtualhost("admin.example.com", prefixroute("/", service(s1))),
+						virtualhost("status.example.com", prefixroute("/", service(s1))),
+					),
+				},
+				&Listener{
+					Name: "mqtt",
+					Port: 8883,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "mqtt.example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters: clusters(service(s1)),
+							},
+						},
+					),
+				},
+			),
+			statuses: map[string]string{
+				"default-port": "valid HTTPProxy",
+				"admin":        "valid HTTPProxy",
+				"mqtt":         "valid HTTPProxy",
+				"ingressroute": "valid IngressRoute",
+			},
+		},
+		"port matching no listener": {
+			objs: []interface{}{
+				s1,
+				proxy("unknown", "www.example.com", 9090),
+			},
+			statuses: map[string]string{
+				"unknown": "Spec.VirtualHost.Port 9090 does not match any listener",
+			},
+		},
+		"tls on an http listener": {
+			objs: []interface{}{
+				s1,
+				passthrough("admin", "admin.example.com", 9080),
+			},
+			statuses: map[string]string{
+				"admin": `Spec.VirtualHost.Port 9080: listener "admin" does not support TLS`,
+			},
+		},
+		"wildcard on an additional listener": {
+			objs: []interface{}{
+				s1,
+				proxy("admin", "*.example.com", 9080),
+			},
+			statuses: map[string]string{
+				"admin": `Spec.VirtualHost.Port 9080: wildcard fqdn "*.example.com" can only be served on an additional listener by passing TLS through`,
+			},
+		},
+		"wildcard passed through on an additional listener": {
+			objs: []interface{}{
+				s1,
+				passthrough("mqtt", "*.example.com", 8883),
+			},
+			want: listeners(
+				&Listener{
+					Name: "mqtt",
+					Port: 8883,
+					VirtualHosts: virtualhosts(
+						&SecureVirtualHost{
+							VirtualHost: VirtualHost{
+								Name: "*.example.com",
+							},
+							TCPProxy: &TCPProxy{
+								Clusters: clusters(service(s1)),
+							},
+						},
+					),
+				},
+			),
+			statuses: map[string]string{
+				"mqtt": "valid HTTPProxy",
+			},
+		},
+		"no tls on an https listener": {
+			objs: []interface{}{
+				s1,
+				proxy("mqtt", "mqtt.example.com", 8883),
+			},
+			statuses: map[string]string{
+				"mqtt": `Spec.VirtualHost.Port 8883: listener "mqtt" requires TLS`,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Listeners: []AdditionalListener{admin, mqtt},
+				Source: KubernetesCache{
+					FieldLogger: testLogger(t),
+				},
+			}
+			for _, o := range tc.objs {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+
+			got := make(map[string]*Listener)
+			dag.Visit(func(v Vertex) {
+				if l, ok := v.(*Listener); ok {
+					got[l.Name] = l
+				}
+			})
+			want := make(map[string]*Listener)
+			for _, v := range tc.want {
+				if l, ok := v.(*Listener); ok {
+					want[l.Name] = l
+				}
+			}
+			if diff := cmp.Diff(want, got, cmp.AllowUnexported(VirtualHost{})); diff != "" {
+				t.Fatal(diff)
+			}
+
+			statuses := make(map[string]string)
+			for _, st := range dag.Statuses() {
+				statuses[st.Object.GetObjectMeta().GetName()] = st.Description
+			}
+			if diff := cmp.Diff(tc.statuses, statuses); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestBuilderHostConflicts(t *testing.T) {
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	ingressroute := func(name, fqdn string, aliases ...string) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn:    fqdn,
+					Aliases: aliases,
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+	}
+
+	httpproxy := func(name, fqdn string, aliases ...string) *projcontour.HTTPProxy {
+		return &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn:    fqdn,
+					Aliases: aliases,
+				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+	}
+
+	ingress := func(name string, hosts ...string) *v1beta1.Ingress {
+		ing := &v1beta1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+		}
+		for _, host := range hosts {
+			ing.Spec.Rules = append(ing.Spec.Rules, v1beta1.IngressRule{
+				Host: host,
+				IngressRuleValue: v1beta1.IngressRuleValue{
+					HTTP: &v1beta1.HTTPIngressRuleValue{
+						Paths: []v1beta1.HTTPIngressPath{{
+							Backend: v1beta1.IngressBackend{
+								ServiceName: "kuard",
+								ServicePort: intstr.FromInt(8080),
+							},
+						}},
+					},
+				},
+			})
+		}
+		return ing
+	}
+
+	tests := map[string]struct {
+		objs     []interface{}
+		statuses map[string]string
+		hosts    []string
+	}{
+		"alias duplicated across kinds": {
+			objs: []interface{}{
+				ingressroute("ir-alias", "ir.example.com", "www.example.com"),
+				httpproxy("proxy-alias", "proxy.example.com", "www.example.com"),
+			},
+			statuses: map[string]string{
+				"ir-alias":    `alias "www.example.com" is duplicated in multiple objects: HTTPProxy default/proxy-alias, IngressRoute default/ir-alias`,
+				"proxy-alias": `alias "www.example.com" is duplicated in multiple objects: HTTPProxy default/proxy-alias, IngressRoute default/ir-alias`,
+			},
+		},
+		"alias is the fqdn of a root of another kind": {
+			objs: []interface{}{
+				ingressroute("ir-alias", "ir.example.com", "www.example.com"),
+				httpproxy("proxy-fqdn", "www.example.com"),
+			},
+			statuses: map[string]string{
+				"ir-alias":   `alias "www.example.com" is duplicated with a fqdn in other HTTPProxies: default/proxy-fqdn`,
+				"proxy-fqdn": `fqdn "www.example.com" is duplicated with an alias in other IngressRoutes: default/ir-alias`,
+			},
+		},
+		"alias is the host of an ingress": {
+			objs: []interface{}{
+				httpproxy("proxy-alias", "proxy.example.com", "www.example.com"),
+				ingress("ing", "www.example.com", "other.example.com"),
+			},
+			statuses: map[string]string{
+				"proxy-alias": `alias "www.example.com" is duplicated with a fqdn in other Ingresses: default/ing`,
+			},
+			hosts: []string{"other.example.com"},
+		},
+		"alias duplicated across all kinds": {
+			objs: []interface{}{
+				ingressroute("ir-alias", "ir.example.com", "www.example.com"),
+				httpproxy("proxy-alias", "proxy.example.com", "www.example.com"),
+				ingress("ing", "www.example.com", "other.example.com"),
+			},
+			statuses: map[string]string{
+				"ir-alias":    `alias "www.example.com" is duplicated in multiple objects: HTTPProxy default/proxy-alias, IngressRoute default/ir-alias`,
+				"proxy-alias": `alias "www.example.com" is duplicated in multiple objects: HTTPProxy default/proxy-alias, IngressRoute default/ir-alias`,
+			},
+			hosts: []string{"other.example.com"},
+		},
+		"fqdn shared across kinds": {
+			objs: []interface{}{
+				ingressroute("ir-fqdn", "www.example.com"),
+				httpproxy("proxy-fqdn", "www.example.com"),
+				ingress("ing", "www.example.com"),
+			},
+			statuses: map[string]string{
+				"ir-fqdn":    "valid IngressRoute",
+				"proxy-fqdn": "valid HTTPProxy",
+			},
+			hosts: []string{"www.example.com"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					FieldLogger: testLogger(t),
+				},
+			}
+			for _, o := range append(tc.objs, s1) {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+
+			var hosts []string
+			dag.Visit(func(v Vertex) {
+				v.Visit(func(v Vertex) {
+					if vh, ok := v.(*VirtualHost); ok {
+						hosts = append(hosts, vh.Name)
+					}
+				})
+			})
+			if diff := cmp.Diff(tc.hosts, hosts); diff != "" {
+				t.Fatal(diff)
+			}
+
+			statuses := make(map[string]string)
+			for _, st := range dag.Statuses() {
+				statuses[st.Object.GetObjectMeta().GetName()] = st.Description
+			}
+			if diff := cmp.Diff(tc.statuses, statuses); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestValidListeners(t *testing.T) {
+	tests := map[string]struct {
+		listeners []AdditionalListener
+		want      string
+	}{
+		"valid": {
+			listeners: []AdditionalListener{
+				{Name: "admin", Port: 9080, Protocol: "http"},
+				{Name: "mqtt", Port: 8883, Protocol: "https"},
+			},
+		},
+		"missing name": {
+			listeners: []AdditionalListener{{Port: 9080, Protocol: "http"}},
+			want:      "listener on port 9080: name must be defined",
+		},
+		"duplicate name": {
+			listeners: []AdditionalListener{
+				{Name: "admin", Port: 9080, Protocol: "http"},
+				{Name: "admin", Port: 9081, Protocol: "http"},
+			},
+			want: `listener "admin": name is already used`,
+		},
+		"unknown protocol": {
+			listeners: []AdditionalListener{{Name: "admin", Port: 9080, Protocol: "tcp"}},
+			want:      `listener "admin": protocol "tcp" must be "http" or "https"`,
+		},
+		"port out of range": {
+			listeners: []AdditionalListener{{Name: "admin", Port: 70000, Protocol: "http"}},
+			want:      `listener "admin": port 70000 must be between 1 and 65535`,
+		},
+		"reserved port": {
+			listeners: []AdditionalListener{{Name: "admin", Port: 8080, Protocol: "http"}},
+			want:      `listener "admin": port 8080 is already used by Envoy`,
+		},
+		"duplicate port": {
+			listeners: []AdditionalListener{
+				{Name: "admin", Port: 9080, Protocol: "http"},
+				{Name: "status", Port: 9080, Protocol: "http"},
+			},
+			want: `listener "status": port 9080 is already used by listener "admin"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			if err := ValidListeners(tc.listeners, 8080, 8443, 8002); err != nil {
+				got = err.Error()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
 func TestValidateHeaderAlteration(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -7748,6 +8564,37 @@ func virtualhosts(vx ...Vertex) []Vertex {
 	return vx
 }
 
+// accessLogFilter is the access log filter of ir6a.
+var accessLogFilter = &AccessLog{
+	Filter: &AccessLogFilter{
+		StatusCodes: []StatusCodeRange{{Min: 500, Max: 599}},
+		HeaderConditions: []HeaderCondition{{
+			Name:      "user-agent",
+			Value:     "kube-probe",
+			MatchType: "contains",
+			Invert:    true,
+		}},
+	},
+}
+
+// accessLogFile is the access log of ir6b.
+var accessLogFile = &AccessLog{
+	Path:    "/var/log/envoy/foo.log",
+	Format:  "json",
+	Fields:  []string{"@timestamp", "path", "response_code"},
+	Headers: []string{"X-Adobe-Tenant"},
+}
+
+func accessLogVirtualHost(vh *VirtualHost, al *AccessLog) *VirtualHost {
+	vh.AccessLog = al
+	return vh
+}
+
+func accessLogSecureVirtualHost(svh *SecureVirtualHost, al *AccessLog) *SecureVirtualHost {
+	svh.AccessLog = al
+	return svh
+}
+
 func virtualhost(name string, first *Route, rest ...*Route) *VirtualHost {
 	return &VirtualHost{
 		Name:   name,
@@ -36,6 +36,9 @@ type DAG struct {
 
 	// status computed while building this dag.
 	statuses map[k8s.FullName]Status
+
+	// Adobe - conflicts found while building this dag.
+	conflicts []Conflict
 }
 
 // Visit calls fn on each root of this DAG.
@@ -240,6 +243,9 @@ type VirtualHost struct {
 
 	// Additional Host names the vhost should match on for routing
 	HostNames []string
+
+	// Adobe - AccessLog overrides the access log of this host.
+	AccessLog *AccessLog
 }
 
 func (v *VirtualHost) addRoute(route *Route) {
@@ -281,9 +287,22 @@ type SecureVirtualHost struct {
 	// The cert and key for this host.
 	Secret *Secret
 
+	// SecondarySecret is a second cert and key for this host, of the
+	// other key type than Secret, RSA or ECDSA.
+	SecondarySecret *Secret
+
+	// CipherSuites and ECDHCurves offered by this host. If not set, the
+	// configured ones are used.
+	CipherSuites []string
+	ECDHCurves   []string
+
 	// FallbackCertificate
 	FallbackCertificate *Secret
 
+	// Aliases are the additional server names of this host, which
+	// its certificates cover.
+	Aliases []string
+
 	// Service to TCP proxy all incoming connections.
 	*TCPProxy
 
@@ -299,6 +318,9 @@ func (s *SecureVirtualHost) Visit(f func(Vertex)) {
 	if s.Secret != nil {
 		f(s.Secret) // secret is not required if vhost is using tls passthrough
 	}
+	if s.SecondarySecret != nil {
+		f(s.SecondarySecret)
+	}
 }
 
 func (s *SecureVirtualHost) Valid() bool {
@@ -320,6 +342,10 @@ type Vertex interface {
 // incoming connections.
 type Listener struct {
 
+	// Adobe - Name is the name of an additional listener, empty for the
+	// HTTP and HTTPS listeners.
+	Name string
+
 	// Address is the TCP address to listen on.
 	// If blank 0.0.0.0, or ::/0 for IPv6, is assumed.
 	Address string
@@ -342,6 +368,18 @@ type TCPProxy struct {
 	// Clusters is the, possibly weighted, set
 	// of upstream services to forward decrypted traffic.
 	Clusters []*Cluster
+
+	// Adobe - IdleTimeout is the timeout applied to idle connections.
+	// A timeout of zero implies the default, -1 disables the timeout.
+	IdleTimeout time.Duration
+
+	// MaxConnectAttempts is the number of attempts to connect to the
+	// upstream. Zero implies Envoy's default.
+	MaxConnectAttempts uint32
+
+	// DisableAccessLog, if set, leaves the connections out of the
+	// access log.
+	DisableAccessLog bool
 }
 
 func (t *TCPProxy) Visit(f func(Vertex)) {
@@ -380,6 +418,10 @@ type Service struct {
 
 	// ExternalName is an optional field referencing a dns entry for Service type "ExternalName"
 	ExternalName string
+
+	// LocalityLbPolicy is the locality aware load balancing policy,
+	// "ZoneAware" or "LocalityWeighted", requested for this Service.
+	LocalityLbPolicy string
 }
 
 type servicemeta struct {
@@ -421,6 +463,9 @@ type Cluster struct {
 	// See https://www.envoyproxy.io/docs/envoy/latest/api-v2/api/v2/cds.proto#envoy-api-enum-cluster-lbpolicy
 	LoadBalancerPolicy string
 
+	// LoadBalancerOptions tunes the LoadBalancerPolicy, if set.
+	*LoadBalancerOptions
+
 	// Cluster http health check policy
 	*HTTPHealthCheckPolicy
 
@@ -441,10 +486,54 @@ type Cluster struct {
 	SNI string
 
 	IdleTimeout *duration.Duration
+
+	// Failover lists, in order, the clusters traffic fails over to
+	// when this cluster has no healthy hosts.
+	Failover []*Cluster
+
+	// ClientCertificate is the client certificate presented to the
+	// upstream when speaking TLS.
+	ClientCertificate *Secret
+
+	// UpstreamTLS holds the TLS parameters negotiated with the upstream
+	// when speaking TLS, if any differ from the Envoy defaults.
+	UpstreamTLS *UpstreamTLS
+}
+
+// UpstreamTLS defines the TLS parameters of the connections to the
+// upstream of a Cluster. Zero values leave the Envoy defaults.
+type UpstreamTLS struct {
+	MinProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol
+	MaxProtoVersion envoy_api_v2_auth.TlsParameters_TlsProtocol
+
+	// CipherSuites lists the cipher suites offered for TLS 1.2.
+	CipherSuites []string
+
+	// ALPNProtocols replaces the ALPN protocols of the Cluster's protocol.
+	ALPNProtocols []string
+}
+
+// LoadBalancerOptions defines the strategy-specific settings of the
+// load balancer of a Cluster. Zero values leave the Envoy defaults.
+type LoadBalancerOptions struct {
+	// MinimumRingSize and MaximumRingSize bound the size of the hash ring
+	// of the RingHash policy.
+	MinimumRingSize uint64
+	MaximumRingSize uint64
+
+	// ChoiceCount is the number of hosts the WeightedLeastRequest policy
+	// chooses from.
+	ChoiceCount uint32
 }
 
 func (c Cluster) Visit(f func(Vertex)) {
 	f(c.Upstream)
+	for _, fc := range c.Failover {
+		f(fc)
+	}
+	if c.ClientCertificate != nil {
+		f(c.ClientCertificate)
+	}
 }
 
 // Secret represents a K8s Secret for TLS usage as a DAG Vertex. A Secret is
@@ -480,6 +569,32 @@ type HTTPHealthCheckPolicy struct {
 	Timeout            time.Duration
 	UnhealthyThreshold uint32
 	HealthyThreshold   uint32
+
+	// ExpectedStatuses are the [Start, End) ranges of healthy status
+	// codes, the default range is used if empty.
+	ExpectedStatuses []StatusRange
+
+	// RequestHeaders are added to the health check request.
+	RequestHeaders map[string]string
+
+	// InitialJitter and IntervalJitterPercent override the
+	// default jitter if not nil.
+	InitialJitter         *time.Duration
+	IntervalJitterPercent *uint32
+
+	// NoTrafficInterval is the interval between health checks
+	// of a cluster which has not received traffic yet.
+	NoTrafficInterval time.Duration
+
+	// GRPC selects grpc.health.v1 health checking of GRPCServiceName.
+	GRPC            bool
+	GRPCServiceName string
+}
+
+// StatusRange is a [Start, End) range of HTTP status codes.
+type StatusRange struct {
+	Start int64
+	End   int64
 }
 
 // Cluster tcp health check policy
@@ -0,0 +1,147 @@
+package dag
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Protocols of the additional listeners.
+const (
+	// ListenerProtocolHTTP serves plain HTTP.
+	ListenerProtocolHTTP = "http"
+	// ListenerProtocolHTTPS terminates or passes TLS through per SNI,
+	// like the HTTPS listener.
+	ListenerProtocolHTTPS = "https"
+)
+
+// AdditionalListener is a listener, besides the HTTP and HTTPS ones, which
+// serves the virtual hosts of the roots selecting its port.
+type AdditionalListener struct {
+	// Name is the name of the Envoy listener and of its route
+	// configuration.
+	Name string
+
+	// Address is the TCP address to listen on.
+	// If blank 0.0.0.0 is assumed.
+	Address string
+
+	// Port is the TCP port to listen on, which the roots select.
+	Port int
+
+	// Protocol is either ListenerProtocolHTTP or ListenerProtocolHTTPS.
+	Protocol string
+}
+
+// ValidListeners ensures the additional listeners have a name and a
+// protocol, and that their names and ports collide neither with each other
+// nor with the reserved ports of the other listeners.
+func ValidListeners(listeners []AdditionalListener, reservedPorts ...int) error {
+	names := make(map[string]bool)
+	ports := make(map[int]string)
+	for _, port := range reservedPorts {
+		ports[port] = ""
+	}
+	for _, l := range listeners {
+		if l.Name == "" {
+			return fmt.Errorf("listener on port %d: name must be defined", l.Port)
+		}
+		if names[l.Name] {
+			return fmt.Errorf("listener %q: name is already used", l.Name)
+		}
+		names[l.Name] = true
+
+		switch l.Protocol {
+		case ListenerProtocolHTTP, ListenerProtocolHTTPS:
+		default:
+			return fmt.Errorf("listener %q: protocol %q must be %q or %q", l.Name, l.Protocol, ListenerProtocolHTTP, ListenerProtocolHTTPS)
+		}
+
+		if l.Port < 1 || l.Port > 65535 {
+			return fmt.Errorf("listener %q: port %d must be between 1 and 65535", l.Name, l.Port)
+		}
+		if other, ok := ports[l.Port]; ok {
+			if other == "" {
+				return fmt.Errorf("listener %q: port %d is already used by Envoy", l.Name, l.Port)
+			}
+			return fmt.Errorf("listener %q: port %d is already used by listener %q", l.Name, l.Port, other)
+		}
+		ports[l.Port] = l.Name
+	}
+	return nil
+}
+
+// validListenerPort ensures the port selected by a root, if any, is the port
+// of an additional listener whose protocol matches whether the root uses TLS.
+// Unless TLS is passed through, the fqdn of the root must not be a wildcard:
+// the Host headers sent to a non-default port carry the port, which Envoy
+// can't match against a wildcard domain.
+func (b *Builder) validListenerPort(port int, fqdn string, tls, passthrough bool) error {
+	if port == 0 {
+		return nil
+	}
+	if strings.HasPrefix(fqdn, "*.") && !passthrough {
+		return fmt.Errorf("Spec.VirtualHost.Port %d: wildcard fqdn %q can only be served on an additional listener by passing TLS through", port, fqdn)
+	}
+	for _, l := range b.Listeners {
+		if l.Port != port {
+			continue
+		}
+		switch {
+		case l.Protocol == ListenerProtocolHTTPS && !tls:
+			return fmt.Errorf("Spec.VirtualHost.Port %d: listener %q requires TLS", port, l.Name)
+		case l.Protocol == ListenerProtocolHTTP && tls:
+			return fmt.Errorf("Spec.VirtualHost.Port %d: listener %q does not support TLS", port, l.Name)
+		}
+		return nil
+	}
+	return fmt.Errorf("Spec.VirtualHost.Port %d does not match any listener", port)
+}
+
+// setListenerPort records that the virtual hosts of the root, in namespace,
+// are served by the additional listener of port.
+func (b *Builder) setListenerPort(namespace, fqdn string, port int) {
+	if port != 0 && b.rootAllowed(namespace) {
+		b.listenerPorts[fqdn] = port
+	}
+}
+
+// buildAdditionalListeners builds a *dag.Listener for each additional
+// listener serving virtual hosts: the secure virtual hosts for an https
+// listener and the insecure ones for an http listener. The list of virtual
+// hosts will be sorted by hostname.
+func (b *Builder) buildAdditionalListeners() []Vertex {
+	var listeners []Vertex
+	for _, l := range b.Listeners {
+		var virtualhosts []Vertex
+		switch l.Protocol {
+		case ListenerProtocolHTTPS:
+			for _, svh := range b.securevirtualhosts {
+				if svh.Valid() && b.listenerPorts[svh.Name] == l.Port {
+					virtualhosts = append(virtualhosts, svh)
+				}
+			}
+			sort.SliceStable(virtualhosts, func(i, j int) bool {
+				return virtualhosts[i].(*SecureVirtualHost).Name < virtualhosts[j].(*SecureVirtualHost).Name
+			})
+		default:
+			for _, vh := range b.virtualhosts {
+				if vh.Valid() && b.listenerPorts[vh.Name] == l.Port {
+					virtualhosts = append(virtualhosts, vh)
+				}
+			}
+			sort.SliceStable(virtualhosts, func(i, j int) bool {
+				return virtualhosts[i].(*VirtualHost).Name < virtualhosts[j].(*VirtualHost).Name
+			})
+		}
+		if len(virtualhosts) > 0 {
+			listeners = append(listeners, &Listener{
+				Name:         l.Name,
+				Address:      l.Address,
+				Port:         l.Port,
+				VirtualHosts: virtualhosts,
+			})
+		}
+	}
+	return listeners
+}
@@ -21,6 +21,7 @@ import (
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/annotation"
+	"github.com/projectcontour/contour/internal/protobuf"
 	"k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -153,12 +154,19 @@ func ingressrouteHealthCheckPolicy(hc *ingressroutev1.HealthCheck) *HTTPHealthCh
 		return nil
 	}
 	return &HTTPHealthCheckPolicy{
-		Path:               hc.Path,
-		Host:               hc.Host,
-		Interval:           time.Duration(hc.IntervalSeconds) * time.Second,
-		Timeout:            time.Duration(hc.TimeoutSeconds) * time.Second,
-		UnhealthyThreshold: uint32(hc.UnhealthyThresholdCount),
-		HealthyThreshold:   uint32(hc.HealthyThresholdCount),
+		Path:                  hc.Path,
+		Host:                  hc.Host,
+		Interval:              time.Duration(hc.IntervalSeconds) * time.Second,
+		Timeout:               time.Duration(hc.TimeoutSeconds) * time.Second,
+		UnhealthyThreshold:    uint32(hc.UnhealthyThresholdCount),
+		HealthyThreshold:      uint32(hc.HealthyThresholdCount),
+		ExpectedStatuses:      statusRanges(hc.ExpectedStatuses),
+		RequestHeaders:        healthCheckHeaders(hc.RequestHeaders),
+		InitialJitter:         secondsOrNil(hc.InitialJitterSeconds),
+		IntervalJitterPercent: percentOrNil(hc.IntervalJitterPercent),
+		NoTrafficInterval:     time.Duration(hc.NoTrafficIntervalSeconds) * time.Second,
+		GRPC:                  hc.GRPC != nil,
+		GRPCServiceName:       grpcServiceName(hc.GRPC),
 	}
 }
 
@@ -167,13 +175,65 @@ func httpHealthCheckPolicy(hc *projcontour.HTTPHealthCheckPolicy) *HTTPHealthChe
 		return nil
 	}
 	return &HTTPHealthCheckPolicy{
-		Path:               hc.Path,
-		Host:               hc.Host,
-		Interval:           time.Duration(hc.IntervalSeconds) * time.Second,
-		Timeout:            time.Duration(hc.TimeoutSeconds) * time.Second,
-		UnhealthyThreshold: uint32(hc.UnhealthyThresholdCount),
-		HealthyThreshold:   uint32(hc.HealthyThresholdCount),
+		Path:                  hc.Path,
+		Host:                  hc.Host,
+		Interval:              time.Duration(hc.IntervalSeconds) * time.Second,
+		Timeout:               time.Duration(hc.TimeoutSeconds) * time.Second,
+		UnhealthyThreshold:    uint32(hc.UnhealthyThresholdCount),
+		HealthyThreshold:      uint32(hc.HealthyThresholdCount),
+		ExpectedStatuses:      statusRanges(hc.ExpectedStatuses),
+		RequestHeaders:        healthCheckHeaders(hc.RequestHeaders),
+		InitialJitter:         secondsOrNil(hc.InitialJitterSeconds),
+		IntervalJitterPercent: percentOrNil(hc.IntervalJitterPercent),
+		NoTrafficInterval:     time.Duration(hc.NoTrafficIntervalSeconds) * time.Second,
+		GRPC:                  hc.GRPC != nil,
+		GRPCServiceName:       grpcServiceName(hc.GRPC),
+	}
+}
+
+func statusRanges(ranges []projcontour.HTTPStatusRange) []StatusRange {
+	var srs []StatusRange
+	for _, r := range ranges {
+		srs = append(srs, StatusRange{Start: r.Start, End: r.End})
+	}
+	return srs
+}
+
+func healthCheckHeaders(headers []projcontour.HeaderValue) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	hs := make(map[string]string, len(headers))
+	for _, h := range headers {
+		hs[http.CanonicalHeaderKey(h.Name)] = h.Value
+	}
+	return hs
+}
+
+func secondsOrNil(seconds *int64) *time.Duration {
+	if seconds == nil {
+		return nil
+	}
+	d := time.Duration(*seconds) * time.Second
+	return &d
+}
+
+func percentOrNil(percent *int64) *uint32 {
+	if percent == nil {
+		return nil
+	}
+	var p uint32
+	if *percent > 0 {
+		p = uint32(*percent)
+	}
+	return &p
+}
+
+func grpcServiceName(grpc *projcontour.GRPCHealthCheckPolicy) string {
+	if grpc == nil {
+		return ""
 	}
+	return grpc.ServiceName
 }
 
 func tcpHealthCheckPolicy(hc *projcontour.TCPHealthCheckPolicy) *TCPHealthCheckPolicy {
@@ -201,11 +261,95 @@ func loadBalancerPolicy(lbp *projcontour.LoadBalancerPolicy) string {
 		return "Random"
 	case "Cookie":
 		return "Cookie"
+	case "RingHash":
+		return "RingHash"
+	case "Maglev":
+		return "Maglev"
 	default:
 		return ""
 	}
 }
 
+// loadBalancerOptions returns the strategy-specific settings of the
+// load balancer policy, or nil if none is supplied.
+func loadBalancerOptions(lbp *projcontour.LoadBalancerPolicy) *LoadBalancerOptions {
+	if lbp == nil || (lbp.RingHash == nil && lbp.LeastRequest == nil) {
+		return nil
+	}
+	var lbo LoadBalancerOptions
+	if rh := lbp.RingHash; rh != nil {
+		lbo.MinimumRingSize = rh.MinimumRingSize
+		lbo.MaximumRingSize = rh.MaximumRingSize
+	}
+	if lr := lbp.LeastRequest; lr != nil {
+		lbo.ChoiceCount = lr.ChoiceCount
+	}
+	return &lbo
+}
+
+// ingressrouteLoadBalancerPolicy returns the load balancer strategy of
+// the IngressRoute service, taken from its loadBalancerPolicy if set or
+// from its strategy otherwise, or an error if both are set and differ.
+func ingressrouteLoadBalancerPolicy(service ingressroutev1.Service) (string, error) {
+	lbp := service.LoadBalancerPolicy
+	if lbp == nil || lbp.Strategy == "" {
+		return service.Strategy, nil
+	}
+	if service.Strategy != "" && service.Strategy != lbp.Strategy {
+		return "", fmt.Errorf("strategy %q and load balancer policy strategy %q differ", service.Strategy, lbp.Strategy)
+	}
+	return lbp.Strategy, nil
+}
+
+// hashPolicies returns the hash policies of a route, or an error if
+// any of them is invalid.
+func hashPolicies(policies []projcontour.HashPolicy) ([]ingressroutev1.HashPolicy, error) {
+	var hps []ingressroutev1.HashPolicy
+	for _, p := range policies {
+		hp := ingressroutev1.HashPolicy{
+			Terminal: p.Terminal,
+		}
+		specified := 0
+		if p.Header != nil {
+			specified++
+			if p.Header.HeaderName == "" {
+				return nil, fmt.Errorf("hash policy header name must be specified")
+			}
+			hp.Header = &ingressroutev1.HashPolicyHeader{
+				HeaderName: p.Header.HeaderName,
+			}
+		}
+		if p.Cookie != nil {
+			specified++
+			if p.Cookie.Name == "" {
+				return nil, fmt.Errorf("hash policy cookie name must be specified")
+			}
+			hp.Cookie = &ingressroutev1.HashPolicyCookie{
+				Name: p.Cookie.Name,
+				Path: p.Cookie.Path,
+			}
+			if p.Cookie.TTL != "" {
+				ttl, err := time.ParseDuration(p.Cookie.TTL)
+				if err != nil || ttl < 0 {
+					return nil, fmt.Errorf("hash policy cookie %q has an invalid ttl %q", p.Cookie.Name, p.Cookie.TTL)
+				}
+				hp.Cookie.Ttl = &ingressroutev1.Duration{Duration: *protobuf.Duration(ttl)}
+			}
+		}
+		if p.ConnectionProperties != nil {
+			specified++
+			hp.ConnectionProperties = &ingressroutev1.HashPolicyConnectionProperties{
+				SourceIp: p.ConnectionProperties.SourceIP,
+			}
+		}
+		if specified != 1 {
+			return nil, fmt.Errorf("hash policy must specify exactly one of header, cookie or connectionProperties")
+		}
+		hps = append(hps, hp)
+	}
+	return hps, nil
+}
+
 func max(a, b uint32) uint32 {
 	if a > b {
 		return a
@@ -21,6 +21,7 @@ import (
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	"github.com/projectcontour/contour/internal/annotation"
 	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/protobuf"
 	"k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -372,6 +373,18 @@ func TestLoadBalancerPolicy(t *testing.T) {
 			},
 			want: "Cookie",
 		},
+		"RingHash": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "RingHash",
+			},
+			want: "RingHash",
+		},
+		"Maglev": {
+			lbp: &projcontour.LoadBalancerPolicy{
+				Strategy: "Maglev",
+			},
+			want: "Maglev",
+		},
 		"unknown": {
 			lbp: &projcontour.LoadBalancerPolicy{
 				Strategy: "please",
@@ -388,6 +401,73 @@ func TestLoadBalancerPolicy(t *testing.T) {
 	}
 }
 
+func TestHashPolicies(t *testing.T) {
+	tests := map[string]struct {
+		policies []projcontour.HashPolicy
+		want     []ingressroutev1.HashPolicy
+		wantErr  bool
+	}{
+		"nil": {
+			policies: nil,
+			want:     nil,
+		},
+		"header": {
+			policies: []projcontour.HashPolicy{{
+				Header:   &projcontour.HashPolicyHeader{HeaderName: "x-session"},
+				Terminal: true,
+			}},
+			want: []ingressroutev1.HashPolicy{{
+				Header:   &ingressroutev1.HashPolicyHeader{HeaderName: "x-session"},
+				Terminal: true,
+			}},
+		},
+		"cookie with ttl": {
+			policies: []projcontour.HashPolicy{{
+				Cookie: &projcontour.HashPolicyCookie{Name: "session", TTL: "10s", Path: "/"},
+			}},
+			want: []ingressroutev1.HashPolicy{{
+				Cookie: &ingressroutev1.HashPolicyCookie{
+					Name: "session",
+					Ttl:  &ingressroutev1.Duration{Duration: *protobuf.Duration(10 * time.Second)},
+					Path: "/",
+				},
+			}},
+		},
+		"source ip": {
+			policies: []projcontour.HashPolicy{{
+				ConnectionProperties: &projcontour.HashPolicyConnectionProperties{SourceIP: true},
+			}},
+			want: []ingressroutev1.HashPolicy{{
+				ConnectionProperties: &ingressroutev1.HashPolicyConnectionProperties{SourceIp: true},
+			}},
+		},
+		"cookie with invalid ttl": {
+			policies: []projcontour.HashPolicy{{
+				Cookie: &projcontour.HashPolicyCookie{Name: "session", TTL: "forever"},
+			}},
+			wantErr: true,
+		},
+		"missing header name": {
+			policies: []projcontour.HashPolicy{{
+				Header: &projcontour.HashPolicyHeader{},
+			}},
+			wantErr: true,
+		},
+		"empty": {
+			policies: []projcontour.HashPolicy{{}},
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := hashPolicies(tc.policies)
+			assert.Equal(t, tc.wantErr, err != nil)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestParseTimeout(t *testing.T) {
 	tests := map[string]struct {
 		duration string
@@ -0,0 +1,117 @@
+package dag
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// NotAfter returns the expiry of the earliest expiring certificate of the
+// Secret's certificate chain, and false if it holds no parsable certificate.
+func (s *Secret) NotAfter() (time.Time, bool) {
+	return certificatesNotAfter(s.Cert())
+}
+
+// CANotAfter returns the expiry of the earliest expiring certificate of the
+// Secret's CA bundle, and false if it holds no parsable certificate.
+func (s *Secret) CANotAfter() (time.Time, bool) {
+	return certificatesNotAfter(s.Data()[CACertificateKey])
+}
+
+func certificatesNotAfter(data []byte) (time.Time, bool) {
+	var notAfter time.Time
+	found := false
+	for containsPEMHeader(data) {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		if !found || cert.NotAfter.Before(notAfter) {
+			notAfter = cert.NotAfter
+			found = true
+		}
+	}
+	return notAfter, found
+}
+
+// validKeyPair ensures the private key of the TLS secret matches the leaf
+// certificate of its chain, and that each certificate of the chain is signed
+// by the next one.
+func validKeyPair(s *v1.Secret) error {
+	pair, err := tls.X509KeyPair(s.Data[v1.TLSCertKey], s.Data[v1.TLSPrivateKeyKey])
+	if err != nil {
+		return fmt.Errorf("invalid key pair: %v", err)
+	}
+
+	chain := make([]*x509.Certificate, len(pair.Certificate))
+	for i, der := range pair.Certificate {
+		if chain[i], err = x509.ParseCertificate(der); err != nil {
+			return fmt.Errorf("invalid certificate %d of the chain: %v", i+1, err)
+		}
+	}
+	for i := 0; i < len(chain)-1; i++ {
+		if err := chain[i].CheckSignatureFrom(chain[i+1]); err != nil {
+			return fmt.Errorf("certificate %d of the chain is not signed by certificate %d: %v", i+1, i+2, err)
+		}
+	}
+	return nil
+}
+
+// coverNames ensures the leaf certificate of the Secret is valid for each
+// of the names.
+func (s *Secret) coverNames(names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	pair, err := tls.X509KeyPair(s.Cert(), s.PrivateKey())
+	if err != nil {
+		return err
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if !coversName(leaf, name) {
+			return fmt.Errorf("Secret %q certificate does not cover %q", s.Name(), name)
+		}
+	}
+	return nil
+}
+
+// coversName returns whether the certificate is valid for the name. A
+// wildcard name is only covered by the same wildcard SAN, which covers
+// all the host names it matches.
+func coversName(cert *x509.Certificate, name string) bool {
+	if !isWildcardFQDN(name) {
+		return cert.VerifyHostname(name) == nil
+	}
+	for _, san := range cert.DNSNames {
+		if strings.EqualFold(san, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// coverWildcard ensures the leaf certificate of the Secret is valid for the
+// fqdn when it is a wildcard.
+func (s *Secret) coverWildcard(fqdn string) error {
+	if !isWildcardFQDN(fqdn) {
+		return nil
+	}
+	return s.coverNames([]string{fqdn})
+}
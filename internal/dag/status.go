@@ -27,16 +27,22 @@ type Status struct {
 	Status      string
 	Description string
 	Vhost       string
+	// Adobe - the Valid condition followed by the errors and warnings
+	Conditions []projcontour.StatusCondition
 }
 
 type StatusWriter struct {
 	statuses map[k8s.FullName]Status
+
+	// Adobe - the objects affected by the invalid objects they conflict with
+	conflicts []Conflict
 }
 
 type ObjectStatusWriter struct {
-	sw     *StatusWriter
-	obj    k8s.Object
-	values map[string]string
+	sw         *StatusWriter
+	obj        k8s.Object
+	values     map[string]string
+	conditions []projcontour.StatusCondition
 }
 
 // WithObject returns an ObjectStatusWriter that can be used to set the state of
@@ -67,14 +73,18 @@ func (sw *StatusWriter) commit(osw *ObjectStatusWriter) {
 		Name:      osw.obj.GetObjectMeta().GetName(),
 		Namespace: osw.obj.GetObjectMeta().GetNamespace(),
 	}
-	if _, ok := sw.statuses[m]; !ok {
+	if st, ok := sw.statuses[m]; !ok {
 		// only record the first status event
 		sw.statuses[m] = Status{
 			Object:      osw.obj,
 			Status:      osw.values["status"],
 			Description: osw.values["description"],
 			Vhost:       osw.values["vhost"],
+			Conditions:  osw.statusConditions(),
 		}
+	} else if st.Status == osw.values["status"] {
+		// Adobe - but the errors and warnings of every status event
+		sw.statuses[m] = st.mergeConditions(osw.conditions)
 	}
 }
 func (osw *ObjectStatusWriter) WithValue(key, val string) *ObjectStatusWriter {
@@ -82,16 +92,28 @@ func (osw *ObjectStatusWriter) WithValue(key, val string) *ObjectStatusWriter {
 	return osw
 }
 
-func (osw *ObjectStatusWriter) SetInvalid(format string, args ...interface{}) {
-	osw.WithValue("description", fmt.Sprintf(format, args...)).WithValue("status", k8s.StatusInvalid)
+// Adobe - the reason of every error is recorded in the conditions, the
+// description being the first error
+func (osw *ObjectStatusWriter) SetInvalid(reason, format string, args ...interface{}) {
+	description := fmt.Sprintf(format, args...)
+	osw.addCondition(projcontour.ConditionError, reason, description)
+	if osw.values["status"] == k8s.StatusInvalid {
+		return
+	}
+	osw.WithValue("description", description).WithValue("status", k8s.StatusInvalid)
 }
 
 func (osw *ObjectStatusWriter) SetValid() {
+	// Adobe - an object with errors stays invalid
+	if osw.values["status"] == k8s.StatusInvalid {
+		return
+	}
 	switch osw.obj.(type) {
+	// Adobe - include the warnings in the description
 	case *projcontour.HTTPProxy:
-		osw.WithValue("description", "valid HTTPProxy").WithValue("status", k8s.StatusValid)
+		osw.WithValue("description", osw.withWarnings("valid HTTPProxy")).WithValue("status", k8s.StatusValid)
 	case *ingressroutev1.IngressRoute:
-		osw.WithValue("description", "valid IngressRoute").WithValue("status", k8s.StatusValid)
+		osw.WithValue("description", osw.withWarnings("valid IngressRoute")).WithValue("status", k8s.StatusValid)
 	default:
 		// not a supported type
 	}
@@ -107,9 +129,10 @@ func (osw *ObjectStatusWriter) WithObject(obj k8s.Object) (_ *ObjectStatusWriter
 		m[k] = v
 	}
 	nosw := &ObjectStatusWriter{
-		sw:     osw.sw,
-		obj:    obj,
-		values: m,
+		sw:         osw.sw,
+		obj:        obj,
+		values:     m,
+		conditions: append([]projcontour.StatusCondition(nil), osw.conditions...),
 	}
 	return nosw, func() {
 		osw.sw.commit(nosw)
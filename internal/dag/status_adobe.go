@@ -0,0 +1,131 @@
+package dag
+
+import (
+	"fmt"
+
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/k8s"
+	v1 "k8s.io/api/core/v1"
+)
+
+// Reasons of the Error and Warning conditions.
+const (
+	ReasonRootNotAllowed      = "RootNotAllowed"
+	ReasonVirtualHostInvalid  = "VirtualHostInvalid"
+	ReasonSecretInvalid       = "SecretInvalid"
+	ReasonTLSInvalid          = "TLSInvalid"
+	ReasonIncludeInvalid      = "IncludeInvalid"
+	ReasonRouteInvalid        = "RouteInvalid"
+	ReasonServiceInvalid      = "ServiceInvalid"
+	ReasonTCPProxyInvalid     = "TCPProxyInvalid"
+	ReasonCertificateExpiring = "CertificateExpiring"
+	ReasonHashPolicyIgnored   = "HashPolicyIgnored"
+)
+
+// SetWarning records a warning about the object. Warnings do not change the
+// status of the object but are appended to its description once valid.
+func (osw *ObjectStatusWriter) SetWarning(reason, format string, args ...interface{}) {
+	warning := fmt.Sprintf(format, args...)
+	osw.addCondition(projcontour.ConditionWarning, reason, warning)
+	if w, ok := osw.values["warning"]; ok {
+		warning = w + "; " + warning
+	}
+	osw.WithValue("warning", warning)
+}
+
+// withWarnings appends the warnings recorded about the object to the description.
+func (osw *ObjectStatusWriter) withWarnings(description string) string {
+	if w, ok := osw.values["warning"]; ok {
+		return description + ", warning: " + w
+	}
+	return description
+}
+
+// addCondition records an error or a warning about the object, unless it
+// was already recorded.
+func (osw *ObjectStatusWriter) addCondition(conditionType projcontour.StatusConditionType, reason, message string) {
+	for _, c := range osw.conditions {
+		if c.Type == conditionType && c.Reason == reason && c.Message == message {
+			return
+		}
+	}
+	osw.conditions = append(osw.conditions, projcontour.StatusCondition{
+		Type:    conditionType,
+		Status:  v1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// statusConditions returns the Valid condition of the object, followed by
+// the errors and warnings recorded about it.
+func (osw *ObjectStatusWriter) statusConditions() []projcontour.StatusCondition {
+	valid := projcontour.StatusCondition{
+		Type:    projcontour.ConditionValid,
+		Status:  v1.ConditionUnknown,
+		Message: osw.values["description"],
+	}
+	switch osw.values["status"] {
+	case k8s.StatusValid:
+		valid.Status, valid.Reason = v1.ConditionTrue, "Valid"
+	case k8s.StatusInvalid:
+		valid.Status, valid.Reason = v1.ConditionFalse, "Invalid"
+	case k8s.StatusOrphaned:
+		valid.Status, valid.Reason = v1.ConditionFalse, "Orphaned"
+	}
+
+	conditions := append([]projcontour.StatusCondition{valid}, osw.conditions...)
+	generation := osw.obj.GetObjectMeta().GetGeneration()
+	for i := range conditions {
+		conditions[i].ObservedGeneration = generation
+	}
+	return conditions
+}
+
+// mergeConditions returns the status with the errors and warnings added,
+// unless already recorded.
+func (st Status) mergeConditions(conditions []projcontour.StatusCondition) Status {
+	merged := append([]projcontour.StatusCondition(nil), st.Conditions...)
+	generation := st.Object.GetObjectMeta().GetGeneration()
+	for _, c := range conditions {
+		c.ObservedGeneration = generation
+		seen := false
+		for _, m := range merged {
+			if m.Type == c.Type && m.Reason == c.Reason && m.Message == c.Message {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			merged = append(merged, c)
+		}
+	}
+	st.Conditions = merged
+	return st
+}
+
+// Conflict records that an object is affected by another object, invalid
+// for conflicting with it, the former remaining possibly valid.
+type Conflict struct {
+	Object      k8s.Object
+	With        k8s.Object
+	Description string
+}
+
+// addConflicts records that the objects are affected by with, invalid for
+// the reason described.
+func (sw *StatusWriter) addConflicts(with k8s.Object, description string, objs []k8s.Object) {
+	for _, obj := range objs {
+		sw.conflicts = append(sw.conflicts, Conflict{
+			Object:      obj,
+			With:        with,
+			Description: description,
+		})
+	}
+}
+
+// Conflicts returns the objects affected by the invalid objects they
+// conflict with.
+func (d *DAG) Conflicts() []Conflict {
+	return d.conflicts
+}
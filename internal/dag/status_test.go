@@ -16,6 +16,7 @@ package dag
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/projectcontour/contour/adobe"
 
@@ -799,6 +800,51 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
+	// ir32 is invalid because its access log filter has an empty status code range
+	ir32 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "roots",
+			Name:      "access-log",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				AccessLog: &ingressroutev1.AccessLog{
+					Filter: &ingressroutev1.AccessLogFilter{
+						StatusCodes: []string{"599-500"},
+					},
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/foo",
+				Services: []ingressroutev1.Service{{
+					Name: s4.Name,
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// ir33 is invalid because its access log sets fields in the envoy format
+	ir33 := ir32.DeepCopy()
+	ir33.Spec.VirtualHost.AccessLog = &ingressroutev1.AccessLog{
+		Format: "envoy",
+		Fields: []string{"@timestamp"},
+	}
+
+	// ir34 is invalid because its access log is written to a file not allowed
+	ir34 := ir32.DeepCopy()
+	ir34.Spec.VirtualHost.AccessLog = &ingressroutev1.AccessLog{
+		Path: "/etc/passwd",
+	}
+
+	// ir35 is invalid because its access log logs an unknown field
+	ir35 := ir32.DeepCopy()
+	ir35.Spec.VirtualHost.AccessLog = &ingressroutev1.AccessLog{
+		Path:   "/var/log/envoy/tenant.log",
+		Fields: []string{"@timestamp", "tenant_id"},
+	}
+
 	// proxy1 is a valid proxy
 	proxy1 := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1776,6 +1822,28 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
+	proxy45a := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-tcp-proxy-idle-timeout",
+			Namespace: s1.Namespace,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "tcpproxy.example.com",
+				TLS: &projcontour.TLS{
+					Passthrough: true,
+				},
+			},
+			TCPProxy: &projcontour.TCPProxy{
+				Services: []projcontour.Service{{
+					Name: s1.Name,
+					Port: 8080,
+				}},
+				IdleTimeout: "forever",
+			},
+		},
+	}
+
 	proxy46 := &projcontour.HTTPProxy{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "missing-tls",
@@ -1949,748 +2017,2122 @@ func TestDAGStatus(t *testing.T) {
 		},
 	}
 
-	tests := map[string]struct {
-		objs                []interface{}
-		fallbackCertificate *k8s.FullName
-		want                map[k8s.FullName]Status
-	}{
-		"valid ingressroute": {
-			objs: []interface{}{ir1, s4},
-			want: map[k8s.FullName]Status{
-				{Name: ir1.Name, Namespace: ir1.Namespace}: {Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-			},
+	irDuplicatePriority := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "duplicate-priority",
+			Namespace: "roots",
 		},
-		"invalid port in service": {
-			objs: []interface{}{ir2},
-			want: map[k8s.FullName]Status{
-				{Name: ir2.Name, Namespace: ir2.Namespace}: {Object: ir2, Status: "invalid", Description: `route "/foo": service "home": port must be in the range 1-65535`, Vhost: "example.com"},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{
+					{Name: "kuard", Port: 8080, Priority: 1},
+					{Name: "home", Port: 8080, Priority: 1},
+				},
+			}},
 		},
-		"root ingressroute outside of roots namespace": {
-			objs: []interface{}{ir3},
-			want: map[k8s.FullName]Status{
-				{Name: ir3.Name, Namespace: ir3.Namespace}: {Object: ir3, Status: "invalid", Description: "root IngressRoute cannot be defined in this namespace"},
-			},
+	}
+
+	proxyDuplicatePriority := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "duplicate-priority",
+			Namespace: "roots",
 		},
-		"delegated route's match prefix does not match parent's prefix": {
-			objs: []interface{}{ir1, ir4, s4},
-			want: map[k8s.FullName]Status{
-				{Name: ir1.Name, Namespace: ir1.Namespace}: {Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-				{Name: ir4.Name, Namespace: ir4.Namespace}: {Object: ir4, Status: "invalid", Description: `the path prefix "/doesnotmatch" does not match the parent's path prefix "/prefix"`},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{
+					{Name: "kuard", Port: 8080},
+					{Name: "home", Port: 8080, Priority: 2},
+					{Name: "kuard", Port: 8080, Priority: 2},
+				},
+			}},
 		},
-		"root ingressroute does not specify FQDN": {
-			objs: []interface{}{ir13},
-			want: map[k8s.FullName]Status{
-				{Name: ir13.Name, Namespace: ir13.Namespace}: {Object: ir13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
-			},
+	}
+
+	proxyGRPCHealthCheck := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "grpc-healthcheck",
+			Namespace: "roots",
 		},
-		"self-edge produces a cycle": {
-			objs: []interface{}{ir6},
-			want: map[k8s.FullName]Status{
-				{Name: ir6.Name, Namespace: ir6.Namespace}: {
-					Object:      ir6,
-					Status:      "invalid",
-					Description: "root ingressroute cannot delegate to another root ingressroute",
-					Vhost:       "example.com",
-				},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
-		},
-		"child delegates to parent, producing a cycle": {
-			objs: []interface{}{ir7, ir8},
-			want: map[k8s.FullName]Status{
-				{Name: ir7.Name, Namespace: ir7.Namespace}: {
-					Object:      ir7,
-					Status:      "valid",
-					Description: "valid IngressRoute",
-					Vhost:       "example.com",
+			Routes: []projcontour.Route{{
+				HealthCheckPolicy: &projcontour.HTTPHealthCheckPolicy{
+					GRPC: &projcontour.GRPCHealthCheckPolicy{},
 				},
-				{Name: ir8.Name, Namespace: ir8.Namespace}: {
-					Object:      ir8,
-					Status:      "invalid",
-					Description: "route creates a delegation cycle: roots/parent -> roots/child -> roots/child",
+				Services: []projcontour.Service{
+					{Name: "kuard", Port: 8080},
 				},
-			},
+			}},
 		},
-		"route has a list of services and also delegates": {
-			objs: []interface{}{ir9},
-			want: map[k8s.FullName]Status{
-				{Name: ir9.Name, Namespace: ir9.Namespace}: {Object: ir9, Status: "invalid", Description: `route "/foo": cannot specify services and delegate in the same route`, Vhost: "example.com"},
-			},
+	}
+
+	proxyHashPolicyIgnored := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hash-policy-ignored",
+			Namespace: "roots",
 		},
-		"ingressroute is an orphaned route": {
-			objs: []interface{}{ir8},
-			want: map[k8s.FullName]Status{
-				{Name: ir8.Name, Namespace: ir8.Namespace}: {Object: ir8, Status: "orphaned", Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []projcontour.Route{{
+				LoadBalancerPolicy: &projcontour.LoadBalancerPolicy{
+					Strategy: "Random",
+				},
+				HashPolicy: []projcontour.HashPolicy{{
+					Header: &projcontour.HashPolicyHeader{HeaderName: "x-session"},
+				}},
+				Services: []projcontour.Service{
+					{Name: "kuard", Port: 8080},
+				},
+			}},
 		},
-		"ingressroute delegates to multiple ingressroutes, one is invalid": {
-			objs: []interface{}{ir10, ir11, ir12, s6, s7},
-			want: map[k8s.FullName]Status{
-				{Name: ir11.Name, Namespace: ir11.Namespace}: {Object: ir11, Status: "valid", Description: "valid IngressRoute"},
-				{Name: ir12.Name, Namespace: ir12.Namespace}: {Object: ir12, Status: "invalid", Description: `route "/bar": service "foo3": port must be in the range 1-65535`},
-				{Name: ir10.Name, Namespace: ir10.Namespace}: {Object: ir10, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-			},
+	}
+
+	proxyInvalidHashPolicy := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-hash-policy",
+			Namespace: "roots",
 		},
-		"invalid parent orphans children": {
-			objs: []interface{}{ir14, ir11},
-			want: map[k8s.FullName]Status{
-				{Name: ir14.Name, Namespace: ir14.Namespace}: {Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
-				{Name: ir11.Name, Namespace: ir11.Namespace}: {Object: ir11, Status: "orphaned", Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []projcontour.Route{{
+				HashPolicy: []projcontour.HashPolicy{{
+					Header: &projcontour.HashPolicyHeader{HeaderName: "x-session"},
+					Cookie: &projcontour.HashPolicyCookie{Name: "session"},
+				}},
+				Services: []projcontour.Service{
+					{Name: "kuard", Port: 8080},
+				},
+			}},
 		},
-		"multi-parent children is not orphaned when one of the parents is invalid": {
-			objs: []interface{}{ir14, ir11, ir10, s5, s6},
-			want: map[k8s.FullName]Status{
-				{Name: ir14.Name, Namespace: ir14.Namespace}: {Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
-				{Name: ir11.Name, Namespace: ir11.Namespace}: {Object: ir11, Status: "valid", Description: "valid IngressRoute"},
-				{Name: ir10.Name, Namespace: ir10.Namespace}: {Object: ir10, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-			},
+	}
+
+	proxyRingHashWithoutStrategy := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ring-hash-without-strategy",
+			Namespace: "roots",
 		},
-		"invalid FQDN contains wildcard": {
-			objs: []interface{}{ir15},
-			want: map[k8s.FullName]Status{
-				{Name: ir15.Name, Namespace: ir15.Namespace}: {Object: ir15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`, Vhost: "example.*.com"},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
-		},
-		"missing service shows invalid status": {
-			objs: []interface{}{ir16},
-			want: map[k8s.FullName]Status{
-				{Name: ir16.Name, Namespace: ir16.Namespace}: {
-					Object:      ir16,
-					Status:      "invalid",
-					Description: `Service [invalid:8080] is invalid or missing`,
-					Vhost:       ir16.Spec.VirtualHost.Fqdn,
+			Routes: []projcontour.Route{{
+				LoadBalancerPolicy: &projcontour.LoadBalancerPolicy{
+					Strategy: "Random",
+					RingHash: &projcontour.RingHashLoadBalancerOptions{MinimumRingSize: 1024},
 				},
-			},
-		},
-		"insert ingressroute": {
-			objs: []interface{}{s1, ir17},
-			want: map[k8s.FullName]Status{
-				{Name: ir17.Name, Namespace: ir17.Namespace}: {
-					Object:      ir17,
-					Status:      k8s.StatusValid,
-					Description: "valid IngressRoute",
-					Vhost:       "example.com",
+				Services: []projcontour.Service{
+					{Name: "kuard", Port: 8080},
 				},
-			},
+			}},
 		},
-		"insert conflicting ingressroutes due to fqdn reuse": {
-			objs: []interface{}{ir17, ir18},
-			want: map[k8s.FullName]Status{
-				{Name: ir17.Name, Namespace: ir17.Namespace}: {
-					Object:      ir17,
-					Status:      k8s.StatusInvalid,
-					Description: `fqdn "example.com" is used in multiple IngressRoutes: roots/example-com, roots/other-example`,
-					Vhost:       "example.com",
-				},
-				{Name: ir18.Name, Namespace: ir18.Namespace}: {
-					Object:      ir18,
-					Status:      k8s.StatusInvalid,
-					Description: `fqdn "example.com" is used in multiple IngressRoutes: roots/example-com, roots/other-example`,
-					Vhost:       "example.com",
-				},
-			},
+	}
+
+	irConflictingStrategy := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "conflicting-strategy",
+			Namespace: "roots",
 		},
-		"root ingress delegating to another root": {
-			objs: []interface{}{ir20, ir21},
-			want: map[k8s.FullName]Status{
-				{Name: ir20.Name, Namespace: ir20.Namespace}: {
-					Object:      ir20,
-					Status:      k8s.StatusInvalid,
-					Description: `fqdn "blog.containersteve.com" is used in multiple IngressRoutes: marketing/blog, roots/root-blog`,
-					Vhost:       "blog.containersteve.com",
-				},
-				{Name: ir21.Name, Namespace: ir21.Namespace}: {
-					Object:      ir21,
-					Status:      k8s.StatusInvalid,
-					Description: `fqdn "blog.containersteve.com" is used in multiple IngressRoutes: marketing/blog, roots/root-blog`,
-					Vhost:       "blog.containersteve.com",
-				},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name:     "kuard",
+					Port:     8080,
+					Strategy: "Random",
+					LoadBalancerPolicy: &projcontour.LoadBalancerPolicy{
+						Strategy: "RingHash",
+					},
+				}},
+			}},
 		},
-		"root ingress delegating to another root w/ different hostname": {
-			objs: []interface{}{ir22, ir23, s8},
-			want: map[k8s.FullName]Status{
-				{Name: ir22.Name, Namespace: ir22.Namespace}: {
-					Object:      ir22,
-					Status:      k8s.StatusInvalid,
-					Description: "root ingressroute cannot delegate to another root ingressroute",
-					Vhost:       "blog.containersteve.com",
-				},
-				{Name: ir23.Name, Namespace: ir23.Namespace}: {
-					Object:      ir23,
-					Status:      k8s.StatusValid,
-					Description: `valid IngressRoute`,
-					Vhost:       "www.containersteve.com",
-				},
+	}
+
+	irInvalidRingSize := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-ring-size",
+			Namespace: "roots",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+					LoadBalancerPolicy: &projcontour.LoadBalancerPolicy{
+						Strategy: "RingHash",
+						RingHash: &projcontour.RingHashLoadBalancerOptions{
+							MinimumRingSize: 4096,
+							MaximumRingSize: 1024,
+						},
+					},
+				}},
+			}},
 		},
-		// issue 1399
-		"service shared across ingress and ingressroute tcpproxy": {
-			objs: []interface{}{
-				sec1, s9, i1, ir24,
+	}
+
+	protocolTLS := "tls"
+	proxyClientCertificateNotDelegated := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "client-certificate-not-delegated",
+			Namespace: "roots",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
-			want: map[k8s.FullName]Status{
-				{Name: ir24.Name, Namespace: ir24.Namespace}: {
-					Object:      ir24,
-					Status:      k8s.StatusValid,
-					Description: `valid IngressRoute`,
-					Vhost:       "example.com",
-				},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name:              "kuard",
+					Port:              8080,
+					Protocol:          &protocolTLS,
+					ClientCertificate: sec2.Namespace + "/" + sec2.Name,
+				}},
+			}},
+		},
+	}
+
+	proxyClientCertificateWithoutTLS := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "client-certificate-without-tls",
+			Namespace: "roots",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name:              "kuard",
+					Port:              8080,
+					ClientCertificate: sec1.Name,
+				}},
+			}},
+		},
+	}
+
+	proxyUnsupportedCipher := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unsupported-cipher",
+			Namespace: "roots",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name:     "kuard",
+					Port:     8080,
+					Protocol: &protocolTLS,
+					UpstreamTLS: &projcontour.UpstreamTLS{
+						MinimumProtocolVersion: "1.2",
+						CipherSuites:           []string{"[ECDHE-RSA-AES128-GCM-SHA256|DES-CBC3-SHA]"},
+					},
+				}},
+			}},
 		},
-		// issue 1347
-		"check status set when tcpproxy combined with tls delegation failure": {
-			objs: []interface{}{
-				sec2,
-				ir25,
+	}
+
+	secEC := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ssl-cert-ecdsa",
+			Namespace: "roots",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(EC_CERTIFICATE, EC_PRIVATE_KEY),
+	}
+
+	irSecondaryCertificate := func(name, secondary string, curves ...string) *ingressroutev1.IngressRoute {
+		return &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "roots",
 			},
-			want: map[k8s.FullName]Status{
-				{Name: ir25.Name, Namespace: ir25.Namespace}: {
-					Object:      ir25,
-					Status:      k8s.StatusInvalid,
-					Description: fmt.Sprintf("Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", k8s.ToFullName(sec2)),
-					Vhost:       ir25.Spec.VirtualHost.Fqdn,
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn: "example.com",
+					TLS: &ingressroutev1.TLS{
+						SecretName:          sec1.Name,
+						SecondarySecretName: secondary,
+						ECDHCurves:          curves,
+					},
 				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
 			},
+		}
+	}
+	irSecondaryECDSA := irSecondaryCertificate("secondary-ecdsa", secEC.Name, "X25519", "P-256")
+	irSecondaryRSA := irSecondaryCertificate("secondary-rsa", sec2.Namespace+"/"+sec2.Name)
+	irUnsupportedCurve := irSecondaryCertificate("unsupported-curve", "", "P-224")
+
+	irUpstreamTLSWithoutTLS := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "upstream-tls-without-tls",
+			Namespace: "roots",
 		},
-		// issue 1348
-		"check status set when routes combined with tls delegation failure": {
-			objs: []interface{}{
-				sec2,
-				ir26,
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
 			},
-			want: map[k8s.FullName]Status{
-				{Name: ir26.Name, Namespace: ir26.Namespace}: {
-					Object:      ir26,
-					Status:      k8s.StatusInvalid,
-					Description: fmt.Sprintf("Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", k8s.ToFullName(sec2)),
-					Vhost:       ir26.Spec.VirtualHost.Fqdn,
-				},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+					UpstreamTLS: &projcontour.UpstreamTLS{
+						MinimumProtocolVersion: "1.3",
+					},
+				}},
+			}},
+		},
+	}
+
+	irInvalidExpectedStatus := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid-expected-status",
+			Namespace: "roots",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+					HealthCheck: &ingressroutev1.HealthCheck{
+						Path:             "/healthz",
+						ExpectedStatuses: []projcontour.HTTPStatusRange{{Start: 300, End: 200}},
+					},
+				}},
+			}},
 		},
-		// issue 1348
-		"check status set when httpproxy routes combined with tls delegation failure": {
-			objs: []interface{}{
-				sec2,
-				proxy19,
+	}
+
+	tests := map[string]struct {
+		objs                []interface{}
+		fallbackCertificate *k8s.FullName
+		want                map[k8s.FullName]Status
+	}{
+		"valid ingressroute": {
+			objs: []interface{}{ir1, s4},
+			want: map[k8s.FullName]Status{
+				{Name: ir1.Name, Namespace: ir1.Namespace}: {Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
 			},
+		},
+		"invalid port in service": {
+			objs: []interface{}{ir2},
 			want: map[k8s.FullName]Status{
-				{Name: proxy19.Name, Namespace: proxy19.Namespace}: {
-					Object:      proxy19,
-					Status:      k8s.StatusInvalid,
-					Description: fmt.Sprintf("Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", k8s.ToFullName(sec2)),
-					Vhost:       proxy19.Spec.VirtualHost.Fqdn,
-				},
+				{Name: ir2.Name, Namespace: ir2.Namespace}: {Object: ir2, Status: "invalid", Description: `route "/foo": service "home": port must be in the range 1-65535`, Vhost: "example.com"},
 			},
 		},
-		// issue 910
-		"non tls routes can be combined with tcp proxy": {
-			objs: []interface{}{
-				s10,
-				ir27,
+		"root ingressroute outside of roots namespace": {
+			objs: []interface{}{ir3},
+			want: map[k8s.FullName]Status{
+				{Name: ir3.Name, Namespace: ir3.Namespace}: {Object: ir3, Status: "invalid", Description: "root IngressRoute cannot be defined in this namespace"},
 			},
+		},
+		"delegated route's match prefix does not match parent's prefix": {
+			objs: []interface{}{ir1, ir4, s4},
 			want: map[k8s.FullName]Status{
-				{Name: ir27.Name, Namespace: ir27.Namespace}: {
-					Object:      ir27,
-					Status:      k8s.StatusValid,
-					Description: `valid IngressRoute`,
-					Vhost:       ir27.Spec.VirtualHost.Fqdn,
-				},
+				{Name: ir1.Name, Namespace: ir1.Namespace}: {Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+				{Name: ir4.Name, Namespace: ir4.Namespace}: {Object: ir4, Status: "invalid", Description: `the path prefix "/doesnotmatch" does not match the parent's path prefix "/prefix"`},
 			},
 		},
-		// issue 1452
-		"ingressroute with missing secret delegation should be invalid": {
-			objs: []interface{}{
-				s10,
-				ir28,
+		"root ingressroute does not specify FQDN": {
+			objs: []interface{}{ir13},
+			want: map[k8s.FullName]Status{
+				{Name: ir13.Name, Namespace: ir13.Namespace}: {Object: ir13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
 			},
+		},
+		"self-edge produces a cycle": {
+			objs: []interface{}{ir6},
 			want: map[k8s.FullName]Status{
-				{Name: ir28.Name, Namespace: ir28.Namespace}: {
-					Object:      ir28,
-					Status:      k8s.StatusInvalid,
-					Description: "Spec.VirtualHost.TLS Secret \"heptio-contour/ssl-cert\" is invalid: Secret not found",
-					Vhost:       ir28.Spec.VirtualHost.Fqdn,
+				{Name: ir6.Name, Namespace: ir6.Namespace}: {
+					Object:      ir6,
+					Status:      "invalid",
+					Description: "root ingressroute cannot delegate to another root ingressroute",
+					Vhost:       "example.com",
 				},
 			},
 		},
-		"two root ingressroutes delegated to the same object should not conflict on hostname": {
-			objs: []interface{}{
-				s1, ir29, ir30, ir31,
-			},
+		"child delegates to parent, producing a cycle": {
+			objs: []interface{}{ir7, ir8},
 			want: map[k8s.FullName]Status{
-				{Name: ir29.Name, Namespace: ir29.Namespace}: {
-					Object:      ir29,
+				{Name: ir7.Name, Namespace: ir7.Namespace}: {
+					Object:      ir7,
 					Status:      "valid",
 					Description: "valid IngressRoute",
-					Vhost:       "site1.com",
+					Vhost:       "example.com",
 				},
-				{Name: ir30.Name, Namespace: ir30.Namespace}: {
-					Object:      ir30,
-					Status:      "valid",
-					Description: "valid IngressRoute",
-					Vhost:       "site2.com",
+				{Name: ir8.Name, Namespace: ir8.Namespace}: {
+					Object:      ir8,
+					Status:      "invalid",
+					Description: "route creates a delegation cycle: roots/parent -> roots/child -> roots/child",
 				},
-				{Name: ir31.Name, Namespace: ir31.Namespace}: {
-					Object:      ir31,
-					Status:      "valid",
-					Description: "valid IngressRoute",
+			},
+		},
+		"route has a list of services and also delegates": {
+			objs: []interface{}{ir9},
+			want: map[k8s.FullName]Status{
+				{Name: ir9.Name, Namespace: ir9.Namespace}: {Object: ir9, Status: "invalid", Description: `route "/foo": cannot specify services and delegate in the same route`, Vhost: "example.com"},
+			},
+		},
+		"ingressroute is an orphaned route": {
+			objs: []interface{}{ir8},
+			want: map[k8s.FullName]Status{
+				{Name: ir8.Name, Namespace: ir8.Namespace}: {Object: ir8, Status: "orphaned", Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"},
+			},
+		},
+		"ingressroute delegates to multiple ingressroutes, one is invalid": {
+			objs: []interface{}{ir10, ir11, ir12, s6, s7},
+			want: map[k8s.FullName]Status{
+				{Name: ir11.Name, Namespace: ir11.Namespace}: {Object: ir11, Status: "valid", Description: "valid IngressRoute"},
+				{Name: ir12.Name, Namespace: ir12.Namespace}: {Object: ir12, Status: "invalid", Description: `route "/bar": service "foo3": port must be in the range 1-65535`},
+				{Name: ir10.Name, Namespace: ir10.Namespace}: {Object: ir10, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+			},
+		},
+		"invalid parent orphans children": {
+			objs: []interface{}{ir14, ir11},
+			want: map[k8s.FullName]Status{
+				{Name: ir14.Name, Namespace: ir14.Namespace}: {Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{Name: ir11.Name, Namespace: ir11.Namespace}: {Object: ir11, Status: "orphaned", Description: "this IngressRoute is not part of a delegation chain from a root IngressRoute"},
+			},
+		},
+		"multi-parent children is not orphaned when one of the parents is invalid": {
+			objs: []interface{}{ir14, ir11, ir10, s5, s6},
+			want: map[k8s.FullName]Status{
+				{Name: ir14.Name, Namespace: ir14.Namespace}: {Object: ir14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{Name: ir11.Name, Namespace: ir11.Namespace}: {Object: ir11, Status: "valid", Description: "valid IngressRoute"},
+				{Name: ir10.Name, Namespace: ir10.Namespace}: {Object: ir10, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+			},
+		},
+		"invalid FQDN contains wildcard": {
+			objs: []interface{}{ir15},
+			want: map[k8s.FullName]Status{
+				{Name: ir15.Name, Namespace: ir15.Namespace}: {Object: ir15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards other than a leading "*." label`, Vhost: "example.*.com"},
+			},
+		},
+		"missing service shows invalid status": {
+			objs: []interface{}{ir16},
+			want: map[k8s.FullName]Status{
+				{Name: ir16.Name, Namespace: ir16.Namespace}: {
+					Object:      ir16,
+					Status:      "invalid",
+					Description: `Service [invalid:8080] is invalid or missing`,
+					Vhost:       ir16.Spec.VirtualHost.Fqdn,
+				},
+			},
+		},
+		"insert ingressroute": {
+			objs: []interface{}{s1, ir17},
+			want: map[k8s.FullName]Status{
+				{Name: ir17.Name, Namespace: ir17.Namespace}: {
+					Object:      ir17,
+					Status:      k8s.StatusValid,
+					Description: "valid IngressRoute",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"insert conflicting ingressroutes due to fqdn reuse": {
+			objs: []interface{}{ir17, ir18},
+			want: map[k8s.FullName]Status{
+				{Name: ir17.Name, Namespace: ir17.Namespace}: {
+					Object:      ir17,
+					Status:      k8s.StatusInvalid,
+					Description: `fqdn "example.com" is used in multiple IngressRoutes: roots/example-com, roots/other-example`,
+					Vhost:       "example.com",
+				},
+				{Name: ir18.Name, Namespace: ir18.Namespace}: {
+					Object:      ir18,
+					Status:      k8s.StatusInvalid,
+					Description: `fqdn "example.com" is used in multiple IngressRoutes: roots/example-com, roots/other-example`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"root ingress delegating to another root": {
+			objs: []interface{}{ir20, ir21},
+			want: map[k8s.FullName]Status{
+				{Name: ir20.Name, Namespace: ir20.Namespace}: {
+					Object:      ir20,
+					Status:      k8s.StatusInvalid,
+					Description: `fqdn "blog.containersteve.com" is used in multiple IngressRoutes: marketing/blog, roots/root-blog`,
+					Vhost:       "blog.containersteve.com",
+				},
+				{Name: ir21.Name, Namespace: ir21.Namespace}: {
+					Object:      ir21,
+					Status:      k8s.StatusInvalid,
+					Description: `fqdn "blog.containersteve.com" is used in multiple IngressRoutes: marketing/blog, roots/root-blog`,
+					Vhost:       "blog.containersteve.com",
+				},
+			},
+		},
+		"root ingress delegating to another root w/ different hostname": {
+			objs: []interface{}{ir22, ir23, s8},
+			want: map[k8s.FullName]Status{
+				{Name: ir22.Name, Namespace: ir22.Namespace}: {
+					Object:      ir22,
+					Status:      k8s.StatusInvalid,
+					Description: "root ingressroute cannot delegate to another root ingressroute",
+					Vhost:       "blog.containersteve.com",
+				},
+				{Name: ir23.Name, Namespace: ir23.Namespace}: {
+					Object:      ir23,
+					Status:      k8s.StatusValid,
+					Description: `valid IngressRoute`,
+					Vhost:       "www.containersteve.com",
+				},
+			},
+		},
+		// issue 1399
+		"service shared across ingress and ingressroute tcpproxy": {
+			objs: []interface{}{
+				sec1, s9, i1, ir24,
+			},
+			want: map[k8s.FullName]Status{
+				{Name: ir24.Name, Namespace: ir24.Namespace}: {
+					Object:      ir24,
+					Status:      k8s.StatusValid,
+					Description: `valid IngressRoute`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		// issue 1347
+		"check status set when tcpproxy combined with tls delegation failure": {
+			objs: []interface{}{
+				sec2,
+				ir25,
+			},
+			want: map[k8s.FullName]Status{
+				{Name: ir25.Name, Namespace: ir25.Namespace}: {
+					Object:      ir25,
+					Status:      k8s.StatusInvalid,
+					Description: fmt.Sprintf("Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", k8s.ToFullName(sec2)),
+					Vhost:       ir25.Spec.VirtualHost.Fqdn,
+				},
+			},
+		},
+		// issue 1348
+		"check status set when routes combined with tls delegation failure": {
+			objs: []interface{}{
+				sec2,
+				ir26,
+			},
+			want: map[k8s.FullName]Status{
+				{Name: ir26.Name, Namespace: ir26.Namespace}: {
+					Object:      ir26,
+					Status:      k8s.StatusInvalid,
+					Description: fmt.Sprintf("Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", k8s.ToFullName(sec2)),
+					Vhost:       ir26.Spec.VirtualHost.Fqdn,
+				},
+			},
+		},
+		// issue 1348
+		"check status set when httpproxy routes combined with tls delegation failure": {
+			objs: []interface{}{
+				sec2,
+				proxy19,
+			},
+			want: map[k8s.FullName]Status{
+				{Name: proxy19.Name, Namespace: proxy19.Namespace}: {
+					Object:      proxy19,
+					Status:      k8s.StatusInvalid,
+					Description: fmt.Sprintf("Spec.VirtualHost.TLS Secret %q certificate delegation not permitted", k8s.ToFullName(sec2)),
+					Vhost:       proxy19.Spec.VirtualHost.Fqdn,
+				},
+			},
+		},
+		// issue 910
+		"non tls routes can be combined with tcp proxy": {
+			objs: []interface{}{
+				s10,
+				ir27,
+			},
+			want: map[k8s.FullName]Status{
+				{Name: ir27.Name, Namespace: ir27.Namespace}: {
+					Object:      ir27,
+					Status:      k8s.StatusValid,
+					Description: `valid IngressRoute`,
+					Vhost:       ir27.Spec.VirtualHost.Fqdn,
+				},
+			},
+		},
+		// issue 1452
+		"ingressroute with missing secret delegation should be invalid": {
+			objs: []interface{}{
+				s10,
+				ir28,
+			},
+			want: map[k8s.FullName]Status{
+				{Name: ir28.Name, Namespace: ir28.Namespace}: {
+					Object:      ir28,
+					Status:      k8s.StatusInvalid,
+					Description: "Spec.VirtualHost.TLS Secret \"heptio-contour/ssl-cert\" is invalid: Secret not found",
+					Vhost:       ir28.Spec.VirtualHost.Fqdn,
+				},
+			},
+		},
+		"two root ingressroutes delegated to the same object should not conflict on hostname": {
+			objs: []interface{}{
+				s1, ir29, ir30, ir31,
+			},
+			want: map[k8s.FullName]Status{
+				{Name: ir29.Name, Namespace: ir29.Namespace}: {
+					Object:      ir29,
+					Status:      "valid",
+					Description: "valid IngressRoute",
+					Vhost:       "site1.com",
+				},
+				{Name: ir30.Name, Namespace: ir30.Namespace}: {
+					Object:      ir30,
+					Status:      "valid",
+					Description: "valid IngressRoute",
+					Vhost:       "site2.com",
+				},
+				{Name: ir31.Name, Namespace: ir31.Namespace}: {
+					Object:      ir31,
+					Status:      "valid",
+					Description: "valid IngressRoute",
+				},
+			},
+		},
+		"root ingressroute with invalid access log filter": {
+			objs: []interface{}{ir32, s4},
+			want: map[k8s.FullName]Status{
+				{Name: ir32.Name, Namespace: ir32.Namespace}: {Object: ir32, Status: "invalid", Description: `Spec.VirtualHost.AccessLog filter: status code range "599-500" is empty`, Vhost: "example.com"},
+			},
+		},
+		"root ingressroute with access log fields in the envoy format": {
+			objs: []interface{}{ir33, s4},
+			want: map[k8s.FullName]Status{
+				{Name: ir33.Name, Namespace: ir33.Namespace}: {Object: ir33, Status: "invalid", Description: "Spec.VirtualHost.AccessLog fields require the json format", Vhost: "example.com"},
+			},
+		},
+		"ingressroute access log path not allowed": {
+			objs: []interface{}{ir34, s4},
+			want: map[k8s.FullName]Status{
+				{Name: ir34.Name, Namespace: ir34.Namespace}: {Object: ir34, Status: "invalid", Description: `Spec.VirtualHost.AccessLog path "/etc/passwd" is not allowed`, Vhost: "example.com"},
+			},
+		},
+		"ingressroute access log unknown field": {
+			objs: []interface{}{ir35, s4},
+			want: map[k8s.FullName]Status{
+				{Name: ir35.Name, Namespace: ir35.Namespace}: {Object: ir35, Status: "invalid", Description: `Spec.VirtualHost.AccessLog fields: unknown field "tenant_id"`, Vhost: "example.com"},
+			},
+		},
+		"valid proxy": {
+			objs: []interface{}{proxy1, s4},
+			want: map[k8s.FullName]Status{
+				{Name: proxy1.Name, Namespace: proxy1.Namespace}: {Object: proxy1, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"proxy invalid port in service": {
+			objs: []interface{}{proxy2},
+			want: map[k8s.FullName]Status{
+				{Name: proxy2.Name, Namespace: proxy2.Namespace}: {Object: proxy2, Status: "invalid", Description: `service "home": port must be in the range 1-65535`, Vhost: "example.com"},
+			},
+		},
+		"root proxy outside of roots namespace": {
+			objs: []interface{}{proxy3},
+			want: map[k8s.FullName]Status{
+				{Name: proxy3.Name, Namespace: proxy3.Namespace}: {Object: proxy3, Status: "invalid", Description: "root HTTPProxy cannot be defined in this namespace"},
+			},
+		},
+		"root proxy does not specify FQDN": {
+			objs: []interface{}{proxy13},
+			want: map[k8s.FullName]Status{
+				{Name: proxy13.Name, Namespace: proxy13.Namespace}: {Object: proxy13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+			},
+		},
+		"proxy self-edge produces a cycle": {
+			objs: []interface{}{proxy6, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy6.Name, Namespace: proxy6.Namespace}: {
+					Object:      proxy6,
+					Status:      "invalid",
+					Description: "root httpproxy cannot delegate to another root httpproxy",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy child delegates to parent, producing a cycle": {
+			objs: []interface{}{proxy7, proxy8},
+			want: map[k8s.FullName]Status{
+				{Name: proxy7.Name, Namespace: proxy7.Namespace}: {
+					Object:      proxy7,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Vhost:       "example.com",
+				},
+				{Name: proxy8.Name, Namespace: proxy8.Namespace}: {
+					Object:      proxy8,
+					Status:      "invalid",
+					Description: "include creates a delegation cycle: roots/parent -> roots/child -> roots/child",
+				},
+			},
+		},
+		"proxy orphaned route": {
+			objs: []interface{}{proxy8},
+			want: map[k8s.FullName]Status{
+				{Name: proxy8.Name, Namespace: proxy8.Namespace}: {Object: proxy8, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy"},
+			},
+		},
+		"proxy invalid parent orphans children": {
+			objs: []interface{}{proxy14, proxy11},
+			want: map[k8s.FullName]Status{
+				{Name: proxy14.Name, Namespace: proxy14.Namespace}: {Object: proxy14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{Name: proxy11.Name, Namespace: proxy11.Namespace}: {Object: proxy11, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy"},
+			},
+		},
+		"proxy invalid FQDN contains wildcard": {
+			objs: []interface{}{proxy15},
+			want: map[k8s.FullName]Status{
+				{Name: proxy15.Name, Namespace: proxy15.Namespace}: {Object: proxy15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards other than a leading "*." label`, Vhost: "example.*.com"},
+			},
+		},
+		"proxy missing service shows invalid status": {
+			objs: []interface{}{proxy16},
+			want: map[k8s.FullName]Status{
+				{Name: proxy16.Name, Namespace: proxy16.Namespace}: {
+					Object:      proxy16,
+					Status:      "invalid",
+					Description: `Service [invalid:8080] is invalid or missing`,
+					Vhost:       proxy16.Spec.VirtualHost.Fqdn,
+				},
+			},
+		},
+		"insert conflicting proxies due to fqdn reuse": {
+			objs: []interface{}{proxy17, proxy18},
+			want: map[k8s.FullName]Status{
+				{Name: proxy17.Name, Namespace: proxy17.Namespace}: {
+					Object:      proxy17,
+					Status:      k8s.StatusInvalid,
+					Description: `fqdn "example.com" is used in multiple HTTPProxies: roots/example-com, roots/other-example`,
+					Vhost:       "example.com",
+				},
+				{Name: proxy18.Name, Namespace: proxy18.Namespace}: {
+					Object:      proxy18,
+					Status:      k8s.StatusInvalid,
+					Description: `fqdn "example.com" is used in multiple HTTPProxies: roots/example-com, roots/other-example`,
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"root proxy delegating to another root": {
+			objs: []interface{}{proxy20, proxy21},
+			want: map[k8s.FullName]Status{
+				{Name: proxy20.Name, Namespace: proxy20.Namespace}: {
+					Object:      proxy20,
+					Status:      k8s.StatusInvalid,
+					Description: `fqdn "blog.containersteve.com" is used in multiple HTTPProxies: marketing/blog, roots/root-blog`,
+					Vhost:       "blog.containersteve.com",
+				},
+				{Name: proxy21.Name, Namespace: proxy21.Namespace}: {
+					Object:      proxy21,
+					Status:      k8s.StatusInvalid,
+					Description: `fqdn "blog.containersteve.com" is used in multiple HTTPProxies: marketing/blog, roots/root-blog`,
+					Vhost:       "blog.containersteve.com",
+				},
+			},
+		},
+		"root proxy delegating to another root w/ different hostname": {
+			objs: []interface{}{proxy22, proxy23, s8},
+			want: map[k8s.FullName]Status{
+				{Name: proxy22.Name, Namespace: proxy22.Namespace}: {
+					Object:      proxy22,
+					Status:      k8s.StatusInvalid,
+					Description: "root httpproxy cannot delegate to another root httpproxy",
+					Vhost:       "blog.containersteve.com",
+				},
+				{Name: proxy23.Name, Namespace: proxy23.Namespace}: {
+					Object:      proxy23,
+					Status:      k8s.StatusValid,
+					Description: `valid HTTPProxy`,
+					Vhost:       "www.containersteve.com",
+				},
+			},
+		},
+		"proxy delegate to another": {
+			objs: []interface{}{proxy24, proxy25, s1, s8},
+			want: map[k8s.FullName]Status{
+				{Name: proxy24.Name, Namespace: proxy24.Namespace}: {
+					Object:      proxy24,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+				},
+				{Name: proxy25.Name, Namespace: proxy25.Namespace}: {
+					Object:      proxy25,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with mirror": {
+			objs: []interface{}{proxy26, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy26.Name, Namespace: proxy26.Namespace}: {
+					Object:      proxy26,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with two mirrors": {
+			objs: []interface{}{proxy27, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy27.Name, Namespace: proxy27.Namespace}: {
+					Object:      proxy27,
+					Status:      "invalid",
+					Description: "only one service per route may be nominated as mirror",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with two prefix conditions on route": {
+			objs: []interface{}{proxy32, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy32.Name, Namespace: proxy32.Namespace}: {
+					Object:      proxy32,
+					Status:      "invalid",
+					Description: "route: more than one prefix is not allowed in a condition block",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with two prefix conditions as an include": {
+			objs: []interface{}{proxy33, proxy34, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy33.Name, Namespace: proxy33.Namespace}: {
+					Object:      proxy33,
+					Status:      "invalid",
+					Description: "include: more than one prefix is not allowed in a condition block",
+					Vhost:       "example.com",
+				}, {Name: proxy34.Name, Namespace: proxy34.Namespace}: {
+					Object:      proxy34,
+					Status:      "orphaned",
+					Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy",
+				},
+			},
+		},
+		"proxy with prefix conditions on route that does not start with slash": {
+			objs: []interface{}{proxy35, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy35.Name, Namespace: proxy35.Namespace}: {
+					Object:      proxy35,
+					Status:      "invalid",
+					Description: "route: prefix conditions must start with /, api was supplied",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"proxy with include prefix that does not start with slash": {
+			objs: []interface{}{proxy36, proxy34, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy36.Name, Namespace: proxy36.Namespace}: {
+					Object:      proxy36,
+					Status:      "invalid",
+					Description: "include: prefix conditions must start with /, api was supplied",
+					Vhost:       "example.com",
+				}, {Name: proxy34.Name, Namespace: proxy34.Namespace}: {
+					Object:      proxy34,
+					Status:      "orphaned",
+					Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy",
+				},
+			},
+		},
+		"duplicate route condition headers": {
+			objs: []interface{}{proxy28, s4},
+			want: map[k8s.FullName]Status{
+				{Name: proxy28.Name, Namespace: proxy28.Namespace}: {Object: proxy28, Status: "invalid", Description: "cannot specify duplicate header 'exact match' conditions in the same route", Vhost: "example.com"},
+			},
+		},
+		"duplicate valid route condition headers": {
+			objs: []interface{}{proxy31, s4},
+			want: map[k8s.FullName]Status{
+				{Name: proxy31.Name, Namespace: proxy31.Namespace}: {Object: proxy31, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"duplicate include condition headers": {
+			objs: []interface{}{proxy29, proxy30, s4},
+			want: map[k8s.FullName]Status{
+				{Name: proxy29.Name, Namespace: proxy29.Namespace}: {Object: proxy29, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+				{Name: proxy30.Name, Namespace: proxy30.Namespace}: {Object: proxy30, Status: "invalid", Description: "cannot specify duplicate header 'exact match' conditions in the same route", Vhost: ""},
+			},
+		},
+		"duplicate path conditions on an include": {
+			objs: []interface{}{proxy41, proxy41a, proxy41b, s4, s11, s12},
+			want: map[k8s.FullName]Status{
+				{Name: proxy41.Name, Namespace: proxy41.Namespace}:   {Object: proxy41, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
+				{Name: proxy41a.Name, Namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
+				{Name: proxy41b.Name, Namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
+			},
+		},
+		"duplicate header conditions on an include": {
+			objs: []interface{}{proxy42, proxy41a, proxy41b, s4, s11, s12},
+			want: map[k8s.FullName]Status{
+				{Name: proxy42.Name, Namespace: proxy42.Namespace}:   {Object: proxy42, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
+				{Name: proxy41a.Name, Namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
+				{Name: proxy41b.Name, Namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
+			},
+		},
+		"duplicate header+path conditions on an include": {
+			objs: []interface{}{proxy43, proxy41a, proxy41b, s4, s11, s12},
+			want: map[k8s.FullName]Status{
+				{Name: proxy43.Name, Namespace: proxy43.Namespace}:   {Object: proxy43, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
+				{Name: proxy41a.Name, Namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
+				{Name: proxy41b.Name, Namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
+			},
+		},
+		"httpproxy with invalid tcpproxy": {
+			objs: []interface{}{proxy37, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy37.Name, Namespace: proxy37.Namespace}: {
+					Object:      proxy37,
+					Status:      "invalid",
+					Description: "tcpproxy: cannot specify services and include in the same httpproxy",
+					Vhost:       "passthrough.example.com",
+				},
+			},
+		},
+		"httpproxy with empty tcpproxy": {
+			objs: []interface{}{proxy37a, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy37a.Name, Namespace: proxy37a.Namespace}: {
+					Object:      proxy37a,
+					Status:      "invalid",
+					Description: "tcpproxy: either services or inclusion must be specified",
+					Vhost:       "passthrough.example.com",
+				},
+			},
+		},
+		"httpproxy w/ tcpproxy w/ missing include": {
+			objs: []interface{}{proxy38, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy38.Name, Namespace: proxy38.Namespace}: {
+					Object:      proxy38,
+					Status:      "invalid",
+					Description: "tcpproxy: include roots/foo not found",
+					Vhost:       "passthrough.example.com",
+				},
+			},
+		},
+		"httpproxy w/ tcpproxy w/ includes another root": {
+			objs: []interface{}{proxy38, proxy39, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy38.Name, Namespace: proxy38.Namespace}: {
+					Object:      proxy38,
+					Status:      "invalid",
+					Description: "root httpproxy cannot delegate to another root httpproxy",
+					Vhost:       "passthrough.example.com",
+				},
+				{Name: proxy39.Name, Namespace: proxy39.Namespace}: {
+					Object:      proxy39,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Vhost:       "www.example.com",
+				},
+			},
+		},
+		"httpproxy w/ tcpproxy w/ includes valid child": {
+			objs: []interface{}{proxy38, proxy40, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy38.Name, Namespace: proxy38.Namespace}: {
+					Object:      proxy38,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Vhost:       "passthrough.example.com",
+				},
+				{Name: proxy40.Name, Namespace: proxy40.Namespace}: {
+					Object:      proxy40,
+					Status:      "valid",
+					Description: "valid HTTPProxy",
+					Vhost:       "passthrough.example.com",
+				},
+			},
+		},
+		"httpproxy w/ missing include": {
+			objs: []interface{}{proxy44, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy44.Name, Namespace: proxy44.Namespace}: {
+					Object:      proxy44,
+					Status:      "invalid",
+					Description: "include roots/child not found",
+					Vhost:       "example.com",
+				},
+			},
+		},
+		"httpproxy w/ tcpproxy w/ missing service": {
+			objs: []interface{}{proxy45},
+			want: map[k8s.FullName]Status{
+				{Name: proxy45.Name, Namespace: proxy45.Namespace}: {
+					Object:      proxy45,
+					Status:      "invalid",
+					Description: "tcpproxy: service roots/not-found/8080: not found",
+					Vhost:       "tcpproxy.example.com",
+				},
+			},
+		},
+		"httpproxy w/ tcpproxy w/ invalid idle timeout": {
+			objs: []interface{}{proxy45a, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy45a.Name, Namespace: proxy45a.Namespace}: {
+					Object:      proxy45a,
+					Status:      "invalid",
+					Description: `tcpproxy: idleTimeout "forever" must be a positive duration or "infinity"`,
+					Vhost:       "tcpproxy.example.com",
+				},
+			},
+		},
+		"httpproxy w/ tcpproxy missing tls": {
+			objs: []interface{}{proxy46},
+			want: map[k8s.FullName]Status{
+				{Name: proxy46.Name, Namespace: proxy46.Namespace}: {
+					Object:      proxy46,
+					Status:      "invalid",
+					Description: "tcpproxy: missing tls.passthrough or tls.secretName",
+					Vhost:       "tcpproxy.example.com",
+				},
+			},
+		},
+		"httpproxy w/ tcpproxy missing service": {
+			objs: []interface{}{sec1, s1, proxy47},
+			want: map[k8s.FullName]Status{
+				{Name: proxy47.Name, Namespace: proxy47.Namespace}: {
+					Object:      proxy47,
+					Status:      "invalid",
+					Description: "Service [missing:9000] is invalid or missing",
+					Vhost:       "tcpproxy.example.com",
+				},
+			},
+		},
+		"ingressroute first, then identical httpproxy": {
+			objs: []interface{}{ir1, proxy1, s4},
+			want: map[k8s.FullName]Status{
+				{Name: ir1.Name, Namespace: ir1.Namespace}:       {Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+				{Name: proxy1.Name, Namespace: proxy1.Namespace}: {Object: proxy1, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+			},
+		},
+		"valid HTTPProxy.TCPProxy": {
+			objs: []interface{}{proxy48root, proxy48child, s1, sec1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy48root.Name, Namespace: proxy48root.Namespace}:   {Object: proxy48root, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
+				{Name: proxy48child.Name, Namespace: proxy48child.Namespace}: {Object: proxy48child, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
+			},
+		},
+		"valid HTTPProxy.TCPProxy - plural": {
+			objs: []interface{}{proxy48rootplural, proxy48child, s1, sec1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy48rootplural.Name, Namespace: proxy48rootplural.Namespace}: {Object: proxy48rootplural, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
+				{Name: proxy48child.Name, Namespace: proxy48child.Namespace}:           {Object: proxy48child, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
+			},
+		},
+		// issue 2309, each route must have at least one service
+		"invalid HTTPProxy due to empty route.service": {
+			objs: []interface{}{proxy49, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxy49.Name, Namespace: proxy49.Namespace}: {
+					Object:      proxy49,
+					Status:      "invalid",
+					Description: "route.services must have at least one entry",
+					Vhost:       "missing-service.example.com",
+				},
+			},
+		},
+		"invalid fallback certificate passed to contour": {
+			fallbackCertificate: &k8s.FullName{
+				Name:      "invalid",
+				Namespace: "invalid",
+			},
+			objs: []interface{}{fallbackCertificate, fallbackSecret, sec1, s4},
+			want: map[k8s.FullName]Status{
+				{Name: fallbackCertificate.Name, Namespace: fallbackCertificate.Namespace}: {Object: fallbackCertificate, Status: "invalid", Description: "Spec.Virtualhost.TLS Secret \"invalid/invalid\" fallback certificate is invalid: Secret not found", Vhost: "example.com"},
+			},
+		},
+		"fallback certificate requested but cert not configured in contour": {
+			objs: []interface{}{fallbackCertificate, fallbackSecret, sec1, s4},
+			want: map[k8s.FullName]Status{
+				{Name: fallbackCertificate.Name, Namespace: fallbackCertificate.Namespace}: {Object: fallbackCertificate, Status: "invalid", Description: "Spec.Virtualhost.TLS enabled fallback but the fallback Certificate Secret is not configured in Contour configuration file", Vhost: "example.com"},
+			},
+		},
+		"fallback certificate requested and clientValidation also configured": {
+			objs: []interface{}{fallbackCertificateWithClientValidation, fallbackSecret, sec1, s4},
+			want: map[k8s.FullName]Status{
+				{Name: fallbackCertificateWithClientValidation.Name, Namespace: fallbackCertificateWithClientValidation.Namespace}: {Object: fallbackCertificateWithClientValidation, Status: "invalid", Description: "Spec.Virtualhost.TLS fallback & client validation are incompatible together", Vhost: "example.com"},
+			},
+		},
+		"ingressroute services with the same priority": {
+			objs: []interface{}{irDuplicatePriority, s1, s4},
+			want: map[k8s.FullName]Status{
+				{Name: irDuplicatePriority.Name, Namespace: irDuplicatePriority.Namespace}: {Object: irDuplicatePriority, Status: "invalid", Description: `route "/": services "kuard" and "home" have the same priority 1`, Vhost: "example.com"},
+			},
+		},
+		"httpproxy services with the same priority": {
+			objs: []interface{}{proxyDuplicatePriority, s1, s4},
+			want: map[k8s.FullName]Status{
+				{Name: proxyDuplicatePriority.Name, Namespace: proxyDuplicatePriority.Namespace}: {Object: proxyDuplicatePriority, Status: "invalid", Description: `services "home" and "kuard" have the same priority 2`, Vhost: "example.com"},
+			},
+		},
+		"httpproxy grpc health check on http/1 service": {
+			objs: []interface{}{proxyGRPCHealthCheck, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxyGRPCHealthCheck.Name, Namespace: proxyGRPCHealthCheck.Namespace}: {Object: proxyGRPCHealthCheck, Status: "invalid", Description: `service "kuard": gRPC health check requires the h2 or h2c protocol`, Vhost: "example.com"},
+			},
+		},
+		"httpproxy hash policy ignored by the load balancer strategy": {
+			objs: []interface{}{proxyHashPolicyIgnored, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxyHashPolicyIgnored.Name, Namespace: proxyHashPolicyIgnored.Namespace}: {Object: proxyHashPolicyIgnored, Status: "valid", Description: `valid HTTPProxy, warning: load balancer strategy "Random" ignores the hash policy of route (prefix: /), "RingHash" is used instead`, Vhost: "example.com"},
+			},
+		},
+		"httpproxy hash policy with both header and cookie": {
+			objs: []interface{}{proxyInvalidHashPolicy, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxyInvalidHashPolicy.Name, Namespace: proxyInvalidHashPolicy.Namespace}: {Object: proxyInvalidHashPolicy, Status: "invalid", Description: "hash policy must specify exactly one of header, cookie or connectionProperties", Vhost: "example.com"},
+			},
+		},
+		"httpproxy ring hash options without the ring hash strategy": {
+			objs: []interface{}{proxyRingHashWithoutStrategy, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxyRingHashWithoutStrategy.Name, Namespace: proxyRingHashWithoutStrategy.Namespace}: {Object: proxyRingHashWithoutStrategy, Status: "invalid", Description: `service "kuard": ring hash options require the RingHash load balancer strategy`, Vhost: "example.com"},
+			},
+		},
+		"ingressroute service with conflicting strategies": {
+			objs: []interface{}{irConflictingStrategy, s1},
+			want: map[k8s.FullName]Status{
+				{Name: irConflictingStrategy.Name, Namespace: irConflictingStrategy.Namespace}: {Object: irConflictingStrategy, Status: "invalid", Description: `route "/": service "kuard": strategy "Random" and load balancer policy strategy "RingHash" differ`, Vhost: "example.com"},
+			},
+		},
+		"ingressroute service with invalid ring size": {
+			objs: []interface{}{irInvalidRingSize, s1},
+			want: map[k8s.FullName]Status{
+				{Name: irInvalidRingSize.Name, Namespace: irInvalidRingSize.Namespace}: {Object: irInvalidRingSize, Status: "invalid", Description: `route "/": service "kuard": minimum ring size 4096 is greater than maximum ring size 1024`, Vhost: "example.com"},
+			},
+		},
+		"httpproxy client certificate not delegated": {
+			objs: []interface{}{proxyClientCertificateNotDelegated, s1, sec2},
+			want: map[k8s.FullName]Status{
+				{Name: proxyClientCertificateNotDelegated.Name, Namespace: proxyClientCertificateNotDelegated.Namespace}: {Object: proxyClientCertificateNotDelegated, Status: "invalid", Description: `service "kuard": client certificate Secret "heptio-contour/default-ssl-cert" certificate delegation not permitted`, Vhost: "example.com"},
+			},
+		},
+		"httpproxy client certificate without tls": {
+			objs: []interface{}{proxyClientCertificateWithoutTLS, s1, sec1},
+			want: map[k8s.FullName]Status{
+				{Name: proxyClientCertificateWithoutTLS.Name, Namespace: proxyClientCertificateWithoutTLS.Namespace}: {Object: proxyClientCertificateWithoutTLS, Status: "invalid", Description: `service "kuard": client certificate requires the tls or h2 protocol`, Vhost: "example.com"},
+			},
+		},
+		"httpproxy upstream tls with unsupported cipher suite": {
+			objs: []interface{}{proxyUnsupportedCipher, s1},
+			want: map[k8s.FullName]Status{
+				{Name: proxyUnsupportedCipher.Name, Namespace: proxyUnsupportedCipher.Namespace}: {Object: proxyUnsupportedCipher, Status: "invalid", Description: `service "kuard": upstream TLS cipher suite "[ECDHE-RSA-AES128-GCM-SHA256|DES-CBC3-SHA]" is not supported`, Vhost: "example.com"},
+			},
+		},
+		"ingressroute with ecdsa secondary certificate": {
+			objs: []interface{}{irSecondaryECDSA, s1, sec1, secEC},
+			want: map[k8s.FullName]Status{
+				{Name: irSecondaryECDSA.Name, Namespace: irSecondaryECDSA.Namespace}: {Object: irSecondaryECDSA, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+			},
+		},
+		"ingressroute with rsa secondary certificate": {
+			objs: []interface{}{irSecondaryRSA, s1, sec1, sec2, &ingressroutev1.TLSCertificateDelegation{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "delegation",
+					Namespace: sec2.Namespace,
+				},
+				Spec: ingressroutev1.TLSCertificateDelegationSpec{
+					Delegations: []ingressroutev1.CertificateDelegation{{
+						SecretName:       sec2.Name,
+						TargetNamespaces: []string{"*"},
+					}},
+				},
+			}},
+			want: map[k8s.FullName]Status{
+				{Name: irSecondaryRSA.Name, Namespace: irSecondaryRSA.Namespace}: {Object: irSecondaryRSA, Status: "invalid", Description: `Spec.VirtualHost.TLS secondary Secret "heptio-contour/default-ssl-cert" holds a RSA certificate like the primary Secret`, Vhost: "example.com"},
+			},
+		},
+		"ingressroute with unsupported ecdh curve": {
+			objs: []interface{}{irUnsupportedCurve, s1, sec1},
+			want: map[k8s.FullName]Status{
+				{Name: irUnsupportedCurve.Name, Namespace: irUnsupportedCurve.Namespace}: {Object: irUnsupportedCurve, Status: "invalid", Description: `Spec.VirtualHost.TLS ECDH curve "P-224" is not supported`, Vhost: "example.com"},
+			},
+		},
+		"ingressroute upstream tls without tls": {
+			objs: []interface{}{irUpstreamTLSWithoutTLS, s1},
+			want: map[k8s.FullName]Status{
+				{Name: irUpstreamTLSWithoutTLS.Name, Namespace: irUpstreamTLSWithoutTLS.Namespace}: {Object: irUpstreamTLSWithoutTLS, Status: "invalid", Description: `route "/": service "kuard": upstream TLS parameters require the tls or h2 protocol`, Vhost: "example.com"},
+			},
+		},
+		"ingressroute health check with invalid expected status": {
+			objs: []interface{}{irInvalidExpectedStatus, s1},
+			want: map[k8s.FullName]Status{
+				{Name: irInvalidExpectedStatus.Name, Namespace: irInvalidExpectedStatus.Namespace}: {Object: irInvalidExpectedStatus, Status: "invalid", Description: `route "/": service "kuard": health check expected status range [300, 200) is invalid`, Vhost: "example.com"},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				FallbackCertificate: tc.fallbackCertificate,
+				Source: KubernetesCache{
+					RootNamespaces: []string{"roots", "marketing"},
+					FieldLogger:    testLogger(t),
+				},
+				AccessLogPaths: []string{"/var/log/envoy/"},
+				ValidAccessLogFields: func(fields []string) error {
+					for _, f := range fields {
+						if f == "tenant_id" {
+							return fmt.Errorf("unknown field %q", f)
+						}
+					}
+					return nil
+				},
+			}
+			if adobe.ShouldSkipTest(name) {
+				t.SkipNow()
+			}
+			for _, o := range tc.objs {
+				adobe.AdobefyObject(o)
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+			got := dag.Statuses()
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCertificateExpiryStatus(t *testing.T) {
+	sec1 := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ssl-cert",
+			Namespace: "roots",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+	}
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "roots",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	ir1 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example",
+			Namespace: "roots",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName: sec1.Name,
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	proxy1 := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example",
+			Namespace: "roots",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &projcontour.TLS{
+					SecretName: sec1.Name,
+				},
+			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	// CERTIFICATE expires at 2029-12-02T01:34:33Z.
+	beforeWarning := time.Date(2029, 10, 1, 0, 0, 0, 0, time.UTC)
+	withinWarning := time.Date(2029, 11, 20, 0, 0, 0, 0, time.UTC)
+	afterExpiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		obj           interface{}
+		now           time.Time
+		refuseExpired bool
+		want          Status
+	}{
+		"ingressroute certificate before the warning period": {
+			obj:  ir1,
+			now:  beforeWarning,
+			want: Status{Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+		},
+		"ingressroute certificate within the warning period": {
+			obj:  ir1,
+			now:  withinWarning,
+			want: Status{Object: ir1, Status: "valid", Description: `valid IngressRoute, warning: Secret "ssl-cert" certificate expires at 2029-12-02T01:34:33Z`, Vhost: "example.com"},
+		},
+		"httpproxy certificate expired": {
+			obj:  proxy1,
+			now:  afterExpiry,
+			want: Status{Object: proxy1, Status: "valid", Description: `valid HTTPProxy, warning: Secret "ssl-cert" certificate expired at 2029-12-02T01:34:33Z`, Vhost: "example.com"},
+		},
+		"httpproxy certificate expired and refused": {
+			obj:           proxy1,
+			now:           afterExpiry,
+			refuseExpired: true,
+			want:          Status{Object: proxy1, Status: "invalid", Description: `Spec.VirtualHost.TLS Secret "ssl-cert" certificate expired at 2029-12-02T01:34:33Z`, Vhost: "example.com"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					RootNamespaces: []string{"roots"},
+					FieldLogger:    testLogger(t),
+				},
+				CertificateExpiryWarning:  720 * time.Hour,
+				RefuseExpiredCertificates: tc.refuseExpired,
+				now:                       func() time.Time { return tc.now },
+			}
+			for _, o := range []interface{}{tc.obj, s1, sec1} {
+				builder.Source.Insert(o)
+			}
+			got := builder.Build().Statuses()
+			obj := tc.obj.(k8s.Object).GetObjectMeta()
+			want := map[k8s.FullName]Status{
+				{Name: obj.GetName(), Namespace: obj.GetNamespace()}: tc.want,
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestKeyPairStatus(t *testing.T) {
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "roots",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	ir1 := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "example",
+			Namespace: "roots",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.com",
+				TLS: &ingressroutev1.TLS{
+					SecretName: "ssl-cert",
+				},
+			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{{
+					Name: "kuard",
+					Port: 8080,
+				}},
+			}},
+		},
+	}
+
+	tests := map[string]struct {
+		data map[string][]byte
+		want Status
+	}{
+		"matching key pair": {
+			data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+			want: Status{Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
+		},
+		"private key does not match the certificate": {
+			data: secretdata(CERTIFICATE, EC_PRIVATE_KEY),
+			want: Status{Object: ir1, Status: "invalid", Description: `Spec.VirtualHost.TLS Secret "ssl-cert" is invalid: invalid key pair: tls: private key type does not match public key type`, Vhost: "example.com"},
+		},
+		"chain out of order": {
+			data: secretdata(CERTIFICATE+"\n"+EC_CERTIFICATE, RSA_PRIVATE_KEY),
+			want: Status{Object: ir1, Status: "invalid", Description: `Spec.VirtualHost.TLS Secret "ssl-cert" is invalid: certificate 1 of the chain is not signed by certificate 2: x509: invalid signature: parent certificate cannot sign this kind of certificate`, Vhost: "example.com"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					RootNamespaces: []string{"roots"},
+					FieldLogger:    testLogger(t),
+				},
+			}
+			sec := &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "ssl-cert",
+					Namespace: "roots",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: tc.data,
+			}
+			for _, o := range []interface{}{ir1, s1, sec} {
+				builder.Source.Insert(o)
+			}
+			got := builder.Build().Statuses()
+			want := map[k8s.FullName]Status{
+				{Name: ir1.Name, Namespace: ir1.Namespace}: tc.want,
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestAliasesStatus(t *testing.T) {
+	secEC := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ssl-cert",
+			Namespace: "roots",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(EC_CERTIFICATE, EC_PRIVATE_KEY),
+	}
+
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "roots",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	ingressroute := func(name, fqdn string, tls bool, aliases ...string) *ingressroutev1.IngressRoute {
+		ir := &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "roots",
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn:    fqdn,
+					Aliases: aliases,
+				},
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+		if tls {
+			ir.Spec.VirtualHost.TLS = &ingressroutev1.TLS{SecretName: secEC.Name}
+		}
+		return ir
+	}
+
+	httpproxy := func(name, fqdn string, tls bool, aliases ...string) *projcontour.HTTPProxy {
+		proxy := &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "roots",
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn:    fqdn,
+					Aliases: aliases,
 				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
 			},
-		},
-		"valid proxy": {
-			objs: []interface{}{proxy1, s4},
+		}
+		if tls {
+			proxy.Spec.VirtualHost.TLS = &projcontour.TLS{SecretName: secEC.Name}
+		}
+		return proxy
+	}
+
+	irValid := ingressroute("valid", "example.com", true, "www.example.com")
+	irUncovered := ingressroute("uncovered", "example.com", true, "www.example.com", "api.example.com")
+	irWhitespace := ingressroute("whitespace", "example.com", false, " www.example.com")
+	irFqdn := ingressroute("fqdn", "example.com", false, "example.com")
+	irDuplicateA := ingressroute("duplicate-a", "a.example.com", false, "www.example.com")
+	irDuplicateB := ingressroute("duplicate-b", "b.example.com", false, "www.example.com")
+	irAliasA := ingressroute("alias-a", "a.example.com", false, "b.example.com")
+	irFqdnB := ingressroute("fqdn-b", "b.example.com", false)
+	proxyValid := httpproxy("valid", "example.com", true, "www.example.com")
+	proxyUncovered := httpproxy("uncovered", "example.com", true, "api.example.com")
+	proxyDuplicateA := httpproxy("duplicate-a", "a.example.com", false, "www.example.com")
+	proxyDuplicateB := httpproxy("duplicate-b", "b.example.com", false, "www.example.com")
+
+	tests := map[string]struct {
+		objs []interface{}
+		want map[k8s.FullName]Status
+	}{
+		"ingressroute aliases covered by the certificate": {
+			objs: []interface{}{irValid},
 			want: map[k8s.FullName]Status{
-				{Name: proxy1.Name, Namespace: proxy1.Namespace}: {Object: proxy1, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+				{Name: irValid.Name, Namespace: irValid.Namespace}: {Object: irValid, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
 			},
 		},
-		"proxy invalid port in service": {
-			objs: []interface{}{proxy2},
+		"ingressroute alias not covered by the certificate": {
+			objs: []interface{}{irUncovered},
 			want: map[k8s.FullName]Status{
-				{Name: proxy2.Name, Namespace: proxy2.Namespace}: {Object: proxy2, Status: "invalid", Description: `service "home": port must be in the range 1-65535`, Vhost: "example.com"},
+				{Name: irUncovered.Name, Namespace: irUncovered.Namespace}: {Object: irUncovered, Status: "invalid", Description: `Spec.VirtualHost.Aliases Secret "ssl-cert" certificate does not cover "api.example.com"`, Vhost: "example.com"},
 			},
 		},
-		"root proxy outside of roots namespace": {
-			objs: []interface{}{proxy3},
+		"ingressroute alias with whitespace": {
+			objs: []interface{}{irWhitespace},
 			want: map[k8s.FullName]Status{
-				{Name: proxy3.Name, Namespace: proxy3.Namespace}: {Object: proxy3, Status: "invalid", Description: "root HTTPProxy cannot be defined in this namespace"},
+				{Name: irWhitespace.Name, Namespace: irWhitespace.Namespace}: {Object: irWhitespace, Status: "invalid", Description: `Spec.VirtualHost.Aliases " www.example.com" is invalid: a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`},
 			},
 		},
-		"root proxy does not specify FQDN": {
-			objs: []interface{}{proxy13},
+		"ingressroute alias is its fqdn": {
+			objs: []interface{}{irFqdn},
 			want: map[k8s.FullName]Status{
-				{Name: proxy13.Name, Namespace: proxy13.Namespace}: {Object: proxy13, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
+				{Name: irFqdn.Name, Namespace: irFqdn.Namespace}: {Object: irFqdn, Status: "invalid", Description: `Spec.VirtualHost.Aliases "example.com" is the fqdn of the virtual host`},
 			},
 		},
-		"proxy self-edge produces a cycle": {
-			objs: []interface{}{proxy6, s1},
+		"ingressroute alias duplicated": {
+			objs: []interface{}{irDuplicateA, irDuplicateB},
 			want: map[k8s.FullName]Status{
-				{Name: proxy6.Name, Namespace: proxy6.Namespace}: {
-					Object:      proxy6,
-					Status:      "invalid",
-					Description: "root httpproxy cannot delegate to another root httpproxy",
-					Vhost:       "example.com",
-				},
+				{Name: irDuplicateA.Name, Namespace: irDuplicateA.Namespace}: {Object: irDuplicateA, Status: "invalid", Description: `alias "www.example.com" is duplicated in multiple IngressRoutes: roots/duplicate-a, roots/duplicate-b`},
+				{Name: irDuplicateB.Name, Namespace: irDuplicateB.Namespace}: {Object: irDuplicateB, Status: "invalid", Description: `alias "www.example.com" is duplicated in multiple IngressRoutes: roots/duplicate-a, roots/duplicate-b`},
 			},
 		},
-		"proxy child delegates to parent, producing a cycle": {
-			objs: []interface{}{proxy7, proxy8},
+		"ingressroute alias is the fqdn of another": {
+			objs: []interface{}{irAliasA, irFqdnB},
 			want: map[k8s.FullName]Status{
-				{Name: proxy7.Name, Namespace: proxy7.Namespace}: {
-					Object:      proxy7,
-					Status:      "valid",
-					Description: "valid HTTPProxy",
-					Vhost:       "example.com",
-				},
-				{Name: proxy8.Name, Namespace: proxy8.Namespace}: {
-					Object:      proxy8,
-					Status:      "invalid",
-					Description: "include creates a delegation cycle: roots/parent -> roots/child -> roots/child",
-				},
+				{Name: irAliasA.Name, Namespace: irAliasA.Namespace}: {Object: irAliasA, Status: "invalid", Description: `alias "b.example.com" is duplicated with a fqdn in other IngressRoutes: roots/fqdn-b`},
+				{Name: irFqdnB.Name, Namespace: irFqdnB.Namespace}:   {Object: irFqdnB, Status: "invalid", Description: `fqdn "b.example.com" is duplicated with an alias in other IngressRoutes: roots/alias-a`},
 			},
 		},
-		"proxy orphaned route": {
-			objs: []interface{}{proxy8},
+		"httpproxy aliases covered by the certificate": {
+			objs: []interface{}{proxyValid},
 			want: map[k8s.FullName]Status{
-				{Name: proxy8.Name, Namespace: proxy8.Namespace}: {Object: proxy8, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy"},
+				{Name: proxyValid.Name, Namespace: proxyValid.Namespace}: {Object: proxyValid, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
 			},
 		},
-		"proxy invalid parent orphans children": {
-			objs: []interface{}{proxy14, proxy11},
+		"httpproxy alias not covered by the certificate": {
+			objs: []interface{}{proxyUncovered},
 			want: map[k8s.FullName]Status{
-				{Name: proxy14.Name, Namespace: proxy14.Namespace}: {Object: proxy14, Status: "invalid", Description: "Spec.VirtualHost.Fqdn must be specified"},
-				{Name: proxy11.Name, Namespace: proxy11.Namespace}: {Object: proxy11, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy"},
+				{Name: proxyUncovered.Name, Namespace: proxyUncovered.Namespace}: {Object: proxyUncovered, Status: "invalid", Description: `Spec.VirtualHost.Aliases Secret "ssl-cert" certificate does not cover "api.example.com"`, Vhost: "example.com"},
 			},
 		},
-		"proxy invalid FQDN contains wildcard": {
-			objs: []interface{}{proxy15},
+		"httpproxy alias duplicated": {
+			objs: []interface{}{proxyDuplicateA, proxyDuplicateB},
 			want: map[k8s.FullName]Status{
-				{Name: proxy15.Name, Namespace: proxy15.Namespace}: {Object: proxy15, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "example.*.com" cannot use wildcards`, Vhost: "example.*.com"},
+				{Name: proxyDuplicateA.Name, Namespace: proxyDuplicateA.Namespace}: {Object: proxyDuplicateA, Status: "invalid", Description: `alias "www.example.com" is duplicated in multiple HTTPProxies: roots/duplicate-a, roots/duplicate-b`, Vhost: "a.example.com"},
+				{Name: proxyDuplicateB.Name, Namespace: proxyDuplicateB.Namespace}: {Object: proxyDuplicateB, Status: "invalid", Description: `alias "www.example.com" is duplicated in multiple HTTPProxies: roots/duplicate-a, roots/duplicate-b`, Vhost: "b.example.com"},
 			},
 		},
-		"proxy missing service shows invalid status": {
-			objs: []interface{}{proxy16},
-			want: map[k8s.FullName]Status{
-				{Name: proxy16.Name, Namespace: proxy16.Namespace}: {
-					Object:      proxy16,
-					Status:      "invalid",
-					Description: `Service [invalid:8080] is invalid or missing`,
-					Vhost:       proxy16.Spec.VirtualHost.Fqdn,
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					RootNamespaces: []string{"roots"},
+					FieldLogger:    testLogger(t),
 				},
-			},
+			}
+			for _, o := range append(tc.objs, s1, secEC) {
+				builder.Source.Insert(o)
+			}
+			got := builder.Build().Statuses()
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestWildcardStatus(t *testing.T) {
+	secWildcard := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wildcard-cert",
+			Namespace: "roots",
 		},
-		"insert conflicting proxies due to fqdn reuse": {
-			objs: []interface{}{proxy17, proxy18},
-			want: map[k8s.FullName]Status{
-				{Name: proxy17.Name, Namespace: proxy17.Namespace}: {
-					Object:      proxy17,
-					Status:      k8s.StatusInvalid,
-					Description: `fqdn "example.com" is used in multiple HTTPProxies: roots/example-com, roots/other-example`,
-					Vhost:       "example.com",
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(WILDCARD_CERTIFICATE, WILDCARD_PRIVATE_KEY),
+	}
+
+	secEC := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ssl-cert",
+			Namespace: "roots",
+		},
+		Type: v1.SecretTypeTLS,
+		Data: secretdata(EC_CERTIFICATE, EC_PRIVATE_KEY),
+	}
+
+	service := func(namespace string) *v1.Service {
+		return &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kuard",
+				Namespace: namespace,
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Name:       "http",
+					Protocol:   "TCP",
+					Port:       8080,
+					TargetPort: intstr.FromInt(8080),
+				}},
+			},
+		}
+	}
+
+	ingressroute := func(namespace, name, fqdn, secret string) *ingressroutev1.IngressRoute {
+		ir := &ingressroutev1.IngressRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: ingressroutev1.IngressRouteSpec{
+				VirtualHost: &ingressroutev1.VirtualHost{
+					Fqdn: fqdn,
 				},
-				{Name: proxy18.Name, Namespace: proxy18.Namespace}: {
-					Object:      proxy18,
-					Status:      k8s.StatusInvalid,
-					Description: `fqdn "example.com" is used in multiple HTTPProxies: roots/example-com, roots/other-example`,
-					Vhost:       "example.com",
+				Routes: []ingressroutev1.Route{{
+					Match: "/",
+					Services: []ingressroutev1.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+		if secret != "" {
+			ir.Spec.VirtualHost.TLS = &ingressroutev1.TLS{SecretName: secret}
+		}
+		return ir
+	}
+
+	httpproxy := func(namespace, name, fqdn, secret string) *projcontour.HTTPProxy {
+		proxy := &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: projcontour.HTTPProxySpec{
+				VirtualHost: &projcontour.VirtualHost{
+					Fqdn: fqdn,
 				},
+				Routes: []projcontour.Route{{
+					Services: []projcontour.Service{{
+						Name: "kuard",
+						Port: 8080,
+					}},
+				}},
+			},
+		}
+		if secret != "" {
+			proxy.Spec.VirtualHost.TLS = &projcontour.TLS{SecretName: secret}
+		}
+		return proxy
+	}
+
+	irCovered := ingressroute("roots", "covered", "*.hello.world.com", secWildcard.Name)
+	irUncovered := ingressroute("roots", "uncovered", "*.hello.world.com", secEC.Name)
+	irInvalid := ingressroute("roots", "invalid", "foo.*.world.com", "")
+	proxyCovered := httpproxy("roots", "covered", "*.hello.world.com", secWildcard.Name)
+	proxyUncovered := httpproxy("roots", "uncovered", "*.example.com", secEC.Name)
+	proxyInvalid := httpproxy("roots", "invalid", "*example.com", "")
+	proxyWildcard := httpproxy("roots", "wildcard", "*.hello.world.com", "")
+	proxySameNamespace := httpproxy("roots", "same-namespace", "foo.hello.world.com", "")
+	proxyOtherNamespace := httpproxy("others", "other-namespace", "foo.hello.world.com", "")
+	irOtherNamespace := ingressroute("others", "other-namespace", "*.world.com", "")
+	passthrough := func(name string, aliases ...string) *projcontour.HTTPProxy {
+		proxy := httpproxy("roots", name, "mqtt.hello.com", "")
+		proxy.Spec.VirtualHost.Aliases = aliases
+		proxy.Spec.VirtualHost.TLS = &projcontour.TLS{Passthrough: true}
+		proxy.Spec.Routes = nil
+		proxy.Spec.TCPProxy = &projcontour.TCPProxy{
+			Services: []projcontour.Service{{
+				Name: "kuard",
+				Port: 8080,
+			}},
+		}
+		return proxy
+	}
+	proxyPassthrough := passthrough("passthrough", "*.mqtt.hello.com")
+	proxyPassthroughInvalid := passthrough("passthrough-invalid", "mqtt.*.hello.com")
+	proxyPassthroughOverlap := passthrough("passthrough-overlap", "*.world.com")
+
+	tests := map[string]struct {
+		objs      []interface{}
+		want      map[k8s.FullName]Status
+		conflicts []Conflict
+	}{
+		"ingressroute wildcard covered by the certificate": {
+			objs: []interface{}{irCovered},
+			want: map[k8s.FullName]Status{
+				{Name: irCovered.Name, Namespace: irCovered.Namespace}: {Object: irCovered, Status: "valid", Description: "valid IngressRoute", Vhost: "*.hello.world.com"},
 			},
 		},
-		"root proxy delegating to another root": {
-			objs: []interface{}{proxy20, proxy21},
+		"ingressroute wildcard not covered by the certificate": {
+			objs: []interface{}{irUncovered},
 			want: map[k8s.FullName]Status{
-				{Name: proxy20.Name, Namespace: proxy20.Namespace}: {
-					Object:      proxy20,
-					Status:      k8s.StatusInvalid,
-					Description: `fqdn "blog.containersteve.com" is used in multiple HTTPProxies: marketing/blog, roots/root-blog`,
-					Vhost:       "blog.containersteve.com",
-				},
-				{Name: proxy21.Name, Namespace: proxy21.Namespace}: {
-					Object:      proxy21,
-					Status:      k8s.StatusInvalid,
-					Description: `fqdn "blog.containersteve.com" is used in multiple HTTPProxies: marketing/blog, roots/root-blog`,
-					Vhost:       "blog.containersteve.com",
-				},
+				{Name: irUncovered.Name, Namespace: irUncovered.Namespace}: {Object: irUncovered, Status: "invalid", Description: `Spec.VirtualHost.Fqdn Secret "ssl-cert" certificate does not cover "*.hello.world.com"`, Vhost: "*.hello.world.com"},
 			},
 		},
-		"root proxy delegating to another root w/ different hostname": {
-			objs: []interface{}{proxy22, proxy23, s8},
+		"ingressroute wildcard not leading": {
+			objs: []interface{}{irInvalid},
 			want: map[k8s.FullName]Status{
-				{Name: proxy22.Name, Namespace: proxy22.Namespace}: {
-					Object:      proxy22,
-					Status:      k8s.StatusInvalid,
-					Description: "root httpproxy cannot delegate to another root httpproxy",
-					Vhost:       "blog.containersteve.com",
-				},
-				{Name: proxy23.Name, Namespace: proxy23.Namespace}: {
-					Object:      proxy23,
-					Status:      k8s.StatusValid,
-					Description: `valid HTTPProxy`,
-					Vhost:       "www.containersteve.com",
-				},
+				{Name: irInvalid.Name, Namespace: irInvalid.Namespace}: {Object: irInvalid, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "foo.*.world.com" cannot use wildcards other than a leading "*." label`, Vhost: "foo.*.world.com"},
 			},
 		},
-		"proxy delegate to another": {
-			objs: []interface{}{proxy24, proxy25, s1, s8},
+		"httpproxy wildcard covered by the certificate": {
+			objs: []interface{}{proxyCovered},
 			want: map[k8s.FullName]Status{
-				{Name: proxy24.Name, Namespace: proxy24.Namespace}: {
-					Object:      proxy24,
-					Status:      "valid",
-					Description: "valid HTTPProxy",
-				},
-				{Name: proxy25.Name, Namespace: proxy25.Namespace}: {
-					Object:      proxy25,
-					Status:      "valid",
-					Description: "valid HTTPProxy",
-					Vhost:       "example.com",
-				},
+				{Name: proxyCovered.Name, Namespace: proxyCovered.Namespace}: {Object: proxyCovered, Status: "valid", Description: "valid HTTPProxy", Vhost: "*.hello.world.com"},
 			},
 		},
-		"proxy with mirror": {
-			objs: []interface{}{proxy26, s1},
+		"httpproxy wildcard not covered by the certificate": {
+			objs: []interface{}{proxyUncovered},
 			want: map[k8s.FullName]Status{
-				{Name: proxy26.Name, Namespace: proxy26.Namespace}: {
-					Object:      proxy26,
-					Status:      "valid",
-					Description: "valid HTTPProxy",
-					Vhost:       "example.com",
-				},
+				{Name: proxyUncovered.Name, Namespace: proxyUncovered.Namespace}: {Object: proxyUncovered, Status: "invalid", Description: `Spec.VirtualHost.Fqdn Secret "ssl-cert" certificate does not cover "*.example.com"`, Vhost: "*.example.com"},
 			},
 		},
-		"proxy with two mirrors": {
-			objs: []interface{}{proxy27, s1},
+		"httpproxy wildcard not a label": {
+			objs: []interface{}{proxyInvalid},
 			want: map[k8s.FullName]Status{
-				{Name: proxy27.Name, Namespace: proxy27.Namespace}: {
-					Object:      proxy27,
-					Status:      "invalid",
-					Description: "only one service per route may be nominated as mirror",
-					Vhost:       "example.com",
-				},
+				{Name: proxyInvalid.Name, Namespace: proxyInvalid.Namespace}: {Object: proxyInvalid, Status: "invalid", Description: `Spec.VirtualHost.Fqdn "*example.com" cannot use wildcards other than a leading "*." label`, Vhost: "*example.com"},
 			},
 		},
-		"proxy with two prefix conditions on route": {
-			objs: []interface{}{proxy32, s1},
+		"wildcard overlapping a host of the same namespace": {
+			objs: []interface{}{proxyWildcard, proxySameNamespace},
 			want: map[k8s.FullName]Status{
-				{Name: proxy32.Name, Namespace: proxy32.Namespace}: {
-					Object:      proxy32,
-					Status:      "invalid",
-					Description: "route: more than one prefix is not allowed in a condition block",
-					Vhost:       "example.com",
-				},
+				{Name: proxyWildcard.Name, Namespace: proxyWildcard.Namespace}:           {Object: proxyWildcard, Status: "valid", Description: "valid HTTPProxy", Vhost: "*.hello.world.com"},
+				{Name: proxySameNamespace.Name, Namespace: proxySameNamespace.Namespace}: {Object: proxySameNamespace, Status: "valid", Description: "valid HTTPProxy", Vhost: "foo.hello.world.com"},
 			},
 		},
-		"proxy with two prefix conditions as an include": {
-			objs: []interface{}{proxy33, proxy34, s1},
+		"wildcard overlapping a host of another namespace": {
+			objs: []interface{}{proxyWildcard, proxyOtherNamespace},
 			want: map[k8s.FullName]Status{
-				{Name: proxy33.Name, Namespace: proxy33.Namespace}: {
-					Object:      proxy33,
-					Status:      "invalid",
-					Description: "include: more than one prefix is not allowed in a condition block",
-					Vhost:       "example.com",
-				}, {Name: proxy34.Name, Namespace: proxy34.Namespace}: {
-					Object:      proxy34,
-					Status:      "orphaned",
-					Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy",
-				},
+				{Name: proxyWildcard.Name, Namespace: proxyWildcard.Namespace}:             {Object: proxyWildcard, Status: "invalid", Description: `fqdn "*.hello.world.com" overlaps with the virtual hosts of other namespaces: others/other-namespace`, Vhost: "*.hello.world.com"},
+				{Name: proxyOtherNamespace.Name, Namespace: proxyOtherNamespace.Namespace}: {Object: proxyOtherNamespace, Status: "valid", Description: "valid HTTPProxy", Vhost: "foo.hello.world.com"},
 			},
+			conflicts: []Conflict{{
+				Object:      proxyOtherNamespace,
+				With:        proxyWildcard,
+				Description: `fqdn "*.hello.world.com" overlaps with the virtual hosts of other namespaces: others/other-namespace`,
+			}},
 		},
-		"proxy with prefix conditions on route that does not start with slash": {
-			objs: []interface{}{proxy35, s1},
+		"passthrough wildcard alias": {
+			objs: []interface{}{proxyPassthrough},
 			want: map[k8s.FullName]Status{
-				{Name: proxy35.Name, Namespace: proxy35.Namespace}: {
-					Object:      proxy35,
-					Status:      "invalid",
-					Description: "route: prefix conditions must start with /, api was supplied",
-					Vhost:       "example.com",
-				},
+				{Name: proxyPassthrough.Name, Namespace: proxyPassthrough.Namespace}: {Object: proxyPassthrough, Status: "valid", Description: "valid HTTPProxy", Vhost: "mqtt.hello.com"},
 			},
 		},
-		"proxy with include prefix that does not start with slash": {
-			objs: []interface{}{proxy36, proxy34, s1},
+		"passthrough wildcard alias not a label": {
+			objs: []interface{}{proxyPassthroughInvalid},
 			want: map[k8s.FullName]Status{
-				{Name: proxy36.Name, Namespace: proxy36.Namespace}: {
-					Object:      proxy36,
-					Status:      "invalid",
-					Description: "include: prefix conditions must start with /, api was supplied",
-					Vhost:       "example.com",
-				}, {Name: proxy34.Name, Namespace: proxy34.Namespace}: {
-					Object:      proxy34,
-					Status:      "orphaned",
-					Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy",
-				},
+				{Name: proxyPassthroughInvalid.Name, Namespace: proxyPassthroughInvalid.Namespace}: {Object: proxyPassthroughInvalid, Status: "invalid", Description: `Spec.VirtualHost.Aliases "mqtt.*.hello.com" cannot use wildcards other than a leading "*." label`, Vhost: "mqtt.hello.com"},
 			},
 		},
-		"duplicate route condition headers": {
-			objs: []interface{}{proxy28, s4},
+		"passthrough wildcard alias overlapping a host of another namespace": {
+			objs: []interface{}{proxyPassthroughOverlap, proxyOtherNamespace},
 			want: map[k8s.FullName]Status{
-				{Name: proxy28.Name, Namespace: proxy28.Namespace}: {Object: proxy28, Status: "invalid", Description: "cannot specify duplicate header 'exact match' conditions in the same route", Vhost: "example.com"},
+				{Name: proxyPassthroughOverlap.Name, Namespace: proxyPassthroughOverlap.Namespace}: {Object: proxyPassthroughOverlap, Status: "invalid", Description: `alias "*.world.com" overlaps with the virtual hosts of other namespaces: others/other-namespace`, Vhost: "mqtt.hello.com"},
+				{Name: proxyOtherNamespace.Name, Namespace: proxyOtherNamespace.Namespace}:         {Object: proxyOtherNamespace, Status: "valid", Description: "valid HTTPProxy", Vhost: "foo.hello.world.com"},
+			},
+			conflicts: []Conflict{{
+				Object:      proxyOtherNamespace,
+				With:        proxyPassthroughOverlap,
+				Description: `alias "*.world.com" overlaps with the virtual hosts of other namespaces: others/other-namespace`,
+			}},
+		},
+		"wildcards overlapping across namespaces": {
+			objs: []interface{}{proxyWildcard, irOtherNamespace},
+			want: map[k8s.FullName]Status{
+				{Name: proxyWildcard.Name, Namespace: proxyWildcard.Namespace}:       {Object: proxyWildcard, Status: "invalid", Description: `fqdn "*.hello.world.com" overlaps with the virtual hosts of other namespaces: others/other-namespace`, Vhost: "*.hello.world.com"},
+				{Name: irOtherNamespace.Name, Namespace: irOtherNamespace.Namespace}: {Object: irOtherNamespace, Status: "invalid", Description: `fqdn "*.world.com" overlaps with the virtual hosts of other namespaces: roots/wildcard`},
 			},
 		},
-		"duplicate valid route condition headers": {
-			objs: []interface{}{proxy31, s4},
-			want: map[k8s.FullName]Status{
-				{Name: proxy31.Name, Namespace: proxy31.Namespace}: {Object: proxy31, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			builder := Builder{
+				Source: KubernetesCache{
+					RootNamespaces: []string{"roots", "others"},
+					FieldLogger:    testLogger(t),
+				},
+			}
+			for _, o := range append(tc.objs, service("roots"), service("others"), secWildcard, secEC) {
+				builder.Source.Insert(o)
+			}
+			dag := builder.Build()
+			assert.Equal(t, tc.want, dag.Statuses())
+			assert.Equal(t, tc.conflicts, dag.Conflicts())
+		})
+	}
+}
+
+func TestAdobeStatusConditions(t *testing.T) {
+	s1 := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kuard",
+			Namespace: "roots",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Name:       "http",
+				Protocol:   "TCP",
+				Port:       8080,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	}
+
+	hashPolicyIgnored := projcontour.Route{
+		Conditions: []projcontour.Condition{{
+			Prefix: "/hash",
+		}},
+		LoadBalancerPolicy: &projcontour.LoadBalancerPolicy{
+			Strategy: "Random",
+		},
+		HashPolicy: []projcontour.HashPolicy{{
+			Header: &projcontour.HashPolicyHeader{HeaderName: "x-session"},
+		}},
+		Services: []projcontour.Service{
+			{Name: "kuard", Port: 8080},
+		},
+	}
+
+	proxyValid := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "valid",
+			Namespace:  "roots",
+			Generation: 3,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{
+					{Name: "kuard", Port: 8080},
+				},
+			}},
 		},
-		"duplicate include condition headers": {
-			objs: []interface{}{proxy29, proxy30, s4},
-			want: map[k8s.FullName]Status{
-				{Name: proxy29.Name, Namespace: proxy29.Namespace}: {Object: proxy29, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
-				{Name: proxy30.Name, Namespace: proxy30.Namespace}: {Object: proxy30, Status: "invalid", Description: "cannot specify duplicate header 'exact match' conditions in the same route", Vhost: ""},
-			},
+	}
+
+	proxyWarning := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "warning",
+			Namespace:  "roots",
+			Generation: 2,
 		},
-		"duplicate path conditions on an include": {
-			objs: []interface{}{proxy41, proxy41a, proxy41b, s4, s11, s12},
-			want: map[k8s.FullName]Status{
-				{Name: proxy41.Name, Namespace: proxy41.Namespace}:   {Object: proxy41, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
-				{Name: proxy41a.Name, Namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
-				{Name: proxy41b.Name, Namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
+			Routes: []projcontour.Route{hashPolicyIgnored},
 		},
-		"duplicate header conditions on an include": {
-			objs: []interface{}{proxy42, proxy41a, proxy41b, s4, s11, s12},
-			want: map[k8s.FullName]Status{
-				{Name: proxy42.Name, Namespace: proxy42.Namespace}:   {Object: proxy42, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
-				{Name: proxy41a.Name, Namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
-				{Name: proxy41b.Name, Namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
-			},
+	}
+
+	proxyWarningAndError := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "warning-and-error",
+			Namespace:  "roots",
+			Generation: 5,
 		},
-		"duplicate header+path conditions on an include": {
-			objs: []interface{}{proxy43, proxy41a, proxy41b, s4, s11, s12},
-			want: map[k8s.FullName]Status{
-				{Name: proxy43.Name, Namespace: proxy43.Namespace}:   {Object: proxy43, Status: "invalid", Description: "duplicate conditions defined on an include", Vhost: "example.com"},
-				{Name: proxy41a.Name, Namespace: proxy41a.Namespace}: {Object: proxy41a, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
-				{Name: proxy41b.Name, Namespace: proxy41b.Namespace}: {Object: proxy41b, Status: "orphaned", Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", Vhost: ""},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
 			},
-		},
-		"httpproxy with invalid tcpproxy": {
-			objs: []interface{}{proxy37, s1},
-			want: map[k8s.FullName]Status{
-				{Name: proxy37.Name, Namespace: proxy37.Namespace}: {
-					Object:      proxy37,
-					Status:      "invalid",
-					Description: "tcpproxy: cannot specify services and include in the same httpproxy",
-					Vhost:       "passthrough.example.com",
+			Routes: []projcontour.Route{hashPolicyIgnored, {
+				Services: []projcontour.Service{
+					{Name: "missing", Port: 8080},
 				},
-			},
+			}},
 		},
-		"httpproxy with empty tcpproxy": {
-			objs: []interface{}{proxy37a, s1},
-			want: map[k8s.FullName]Status{
-				{Name: proxy37a.Name, Namespace: proxy37a.Namespace}: {
-					Object:      proxy37a,
-					Status:      "invalid",
-					Description: "tcpproxy: either services or inclusion must be specified",
-					Vhost:       "passthrough.example.com",
-				},
-			},
+	}
+
+	proxyOrphaned := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "orphaned",
+			Namespace:  "roots",
+			Generation: 1,
 		},
-		"httpproxy w/ tcpproxy w/ missing include": {
-			objs: []interface{}{proxy38, s1},
-			want: map[k8s.FullName]Status{
-				{Name: proxy38.Name, Namespace: proxy38.Namespace}: {
-					Object:      proxy38,
-					Status:      "invalid",
-					Description: "tcpproxy: include roots/foo not found",
-					Vhost:       "passthrough.example.com",
+		Spec: projcontour.HTTPProxySpec{
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{
+					{Name: "kuard", Port: 8080},
 				},
-			},
+			}},
 		},
-		"httpproxy w/ tcpproxy w/ includes another root": {
-			objs: []interface{}{proxy38, proxy39, s1},
-			want: map[k8s.FullName]Status{
-				{Name: proxy38.Name, Namespace: proxy38.Namespace}: {
-					Object:      proxy38,
-					Status:      "invalid",
-					Description: "root httpproxy cannot delegate to another root httpproxy",
-					Vhost:       "passthrough.example.com",
+	}
+
+	proxyErrors := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "errors",
+			Namespace:  "roots",
+			Generation: 4,
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{
+				Fqdn: "example.com",
+			},
+			Routes: []projcontour.Route{{
+				Conditions: []projcontour.Condition{{
+					Prefix: "/missing",
+				}},
+				Services: []projcontour.Service{
+					{Name: "missing", Port: 8080},
+					{Name: "kuard", Port: 70000},
 				},
-				{Name: proxy39.Name, Namespace: proxy39.Namespace}: {
-					Object:      proxy39,
-					Status:      "valid",
-					Description: "valid HTTPProxy",
-					Vhost:       "www.example.com",
+			}, {
+				Conditions: []projcontour.Condition{{
+					Prefix: "/empty",
+				}},
+			}, {
+				Services: []projcontour.Service{
+					{Name: "kuard", Port: 8080},
 				},
+			}},
+		},
+	}
+
+	irErrors := &ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "ir-errors",
+			Namespace:  "roots",
+			Generation: 6,
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{
+				Fqdn: "example.org",
 			},
+			Routes: []ingressroutev1.Route{{
+				Match: "/missing",
+				Services: []ingressroutev1.Service{
+					{Name: "missing", Port: 8080},
+				},
+			}, {
+				Match: "/both",
+				Services: []ingressroutev1.Service{
+					{Name: "kuard", Port: 8080},
+				},
+				Delegate: &ingressroutev1.Delegate{Name: "child"},
+			}},
 		},
-		"httpproxy w/ tcpproxy w/ includes valid child": {
-			objs: []interface{}{proxy38, proxy40, s1},
+	}
+
+	warning := `load balancer strategy "Random" ignores the hash policy of route (prefix: /hash), "RingHash" is used instead`
+
+	tests := map[string]struct {
+		objs []interface{}
+		want map[k8s.FullName]Status
+	}{
+		"valid httpproxy": {
+			objs: []interface{}{proxyValid},
 			want: map[k8s.FullName]Status{
-				{Name: proxy38.Name, Namespace: proxy38.Namespace}: {
-					Object:      proxy38,
-					Status:      "valid",
-					Description: "valid HTTPProxy",
-					Vhost:       "passthrough.example.com",
-				},
-				{Name: proxy40.Name, Namespace: proxy40.Namespace}: {
-					Object:      proxy40,
+				{Name: proxyValid.Name, Namespace: proxyValid.Namespace}: {
+					Object:      proxyValid,
 					Status:      "valid",
 					Description: "valid HTTPProxy",
-					Vhost:       "passthrough.example.com",
+					Vhost:       "example.com",
+					Conditions: []projcontour.StatusCondition{
+						{Type: "Valid", Status: "True", Reason: "Valid", Message: "valid HTTPProxy", ObservedGeneration: 3},
+					},
 				},
 			},
 		},
-		"httpproxy w/ missing include": {
-			objs: []interface{}{proxy44, s1},
+		"valid httpproxy with a warning": {
+			objs: []interface{}{proxyWarning},
 			want: map[k8s.FullName]Status{
-				{Name: proxy44.Name, Namespace: proxy44.Namespace}: {
-					Object:      proxy44,
-					Status:      "invalid",
-					Description: "include roots/child not found",
+				{Name: proxyWarning.Name, Namespace: proxyWarning.Namespace}: {
+					Object:      proxyWarning,
+					Status:      "valid",
+					Description: "valid HTTPProxy, warning: " + warning,
 					Vhost:       "example.com",
+					Conditions: []projcontour.StatusCondition{
+						{Type: "Valid", Status: "True", Reason: "Valid", Message: "valid HTTPProxy, warning: " + warning, ObservedGeneration: 2},
+						{Type: "Warning", Status: "True", Reason: "HashPolicyIgnored", Message: warning, ObservedGeneration: 2},
+					},
 				},
 			},
 		},
-		"httpproxy w/ tcpproxy w/ missing service": {
-			objs: []interface{}{proxy45},
+		"invalid httpproxy with a warning": {
+			objs: []interface{}{proxyWarningAndError},
 			want: map[k8s.FullName]Status{
-				{Name: proxy45.Name, Namespace: proxy45.Namespace}: {
-					Object:      proxy45,
+				{Name: proxyWarningAndError.Name, Namespace: proxyWarningAndError.Namespace}: {
+					Object:      proxyWarningAndError,
 					Status:      "invalid",
-					Description: "tcpproxy: service roots/not-found/8080: not found",
-					Vhost:       "tcpproxy.example.com",
+					Description: "Service [missing:8080] is invalid or missing",
+					Vhost:       "example.com",
+					Conditions: []projcontour.StatusCondition{
+						{Type: "Valid", Status: "False", Reason: "Invalid", Message: "Service [missing:8080] is invalid or missing", ObservedGeneration: 5},
+						{Type: "Warning", Status: "True", Reason: "HashPolicyIgnored", Message: warning, ObservedGeneration: 5},
+						{Type: "Error", Status: "True", Reason: "ServiceInvalid", Message: "Service [missing:8080] is invalid or missing", ObservedGeneration: 5},
+					},
 				},
 			},
 		},
-		"httpproxy w/ tcpproxy missing tls": {
-			objs: []interface{}{proxy46},
+		"httpproxy with errors in several routes": {
+			objs: []interface{}{proxyErrors},
 			want: map[k8s.FullName]Status{
-				{Name: proxy46.Name, Namespace: proxy46.Namespace}: {
-					Object:      proxy46,
+				{Name: proxyErrors.Name, Namespace: proxyErrors.Namespace}: {
+					Object:      proxyErrors,
 					Status:      "invalid",
-					Description: "tcpproxy: missing tls.passthrough or tls.secretName",
-					Vhost:       "tcpproxy.example.com",
+					Description: "Service [missing:8080] is invalid or missing",
+					Vhost:       "example.com",
+					Conditions: []projcontour.StatusCondition{
+						{Type: "Valid", Status: "False", Reason: "Invalid", Message: "Service [missing:8080] is invalid or missing", ObservedGeneration: 4},
+						{Type: "Error", Status: "True", Reason: "ServiceInvalid", Message: "Service [missing:8080] is invalid or missing", ObservedGeneration: 4},
+						{Type: "Error", Status: "True", Reason: "ServiceInvalid", Message: `service "kuard": port must be in the range 1-65535`, ObservedGeneration: 4},
+						{Type: "Error", Status: "True", Reason: "RouteInvalid", Message: "route.services must have at least one entry", ObservedGeneration: 4},
+					},
 				},
 			},
 		},
-		"httpproxy w/ tcpproxy missing service": {
-			objs: []interface{}{sec1, s1, proxy47},
+		"ingressroute with errors in several routes": {
+			objs: []interface{}{irErrors},
 			want: map[k8s.FullName]Status{
-				{Name: proxy47.Name, Namespace: proxy47.Namespace}: {
-					Object:      proxy47,
+				{Name: irErrors.Name, Namespace: irErrors.Namespace}: {
+					Object:      irErrors,
 					Status:      "invalid",
-					Description: "Service [missing:9000] is invalid or missing",
-					Vhost:       "tcpproxy.example.com",
+					Description: "Service [missing:8080] is invalid or missing",
+					Vhost:       "example.org",
+					Conditions: []projcontour.StatusCondition{
+						{Type: "Valid", Status: "False", Reason: "Invalid", Message: "Service [missing:8080] is invalid or missing", ObservedGeneration: 6},
+						{Type: "Error", Status: "True", Reason: "ServiceInvalid", Message: "Service [missing:8080] is invalid or missing", ObservedGeneration: 6},
+						{Type: "Error", Status: "True", Reason: "RouteInvalid", Message: `route "/both": cannot specify services and delegate in the same route`, ObservedGeneration: 6},
+					},
 				},
 			},
 		},
-		"ingressroute first, then identical httpproxy": {
-			objs: []interface{}{ir1, proxy1, s4},
-			want: map[k8s.FullName]Status{
-				{Name: ir1.Name, Namespace: ir1.Namespace}:       {Object: ir1, Status: "valid", Description: "valid IngressRoute", Vhost: "example.com"},
-				{Name: proxy1.Name, Namespace: proxy1.Namespace}: {Object: proxy1, Status: "valid", Description: "valid HTTPProxy", Vhost: "example.com"},
-			},
-		},
-		"valid HTTPProxy.TCPProxy": {
-			objs: []interface{}{proxy48root, proxy48child, s1, sec1},
-			want: map[k8s.FullName]Status{
-				{Name: proxy48root.Name, Namespace: proxy48root.Namespace}:   {Object: proxy48root, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
-				{Name: proxy48child.Name, Namespace: proxy48child.Namespace}: {Object: proxy48child, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
-			},
-		},
-		"valid HTTPProxy.TCPProxy - plural": {
-			objs: []interface{}{proxy48rootplural, proxy48child, s1, sec1},
-			want: map[k8s.FullName]Status{
-				{Name: proxy48rootplural.Name, Namespace: proxy48rootplural.Namespace}: {Object: proxy48rootplural, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
-				{Name: proxy48child.Name, Namespace: proxy48child.Namespace}:           {Object: proxy48child, Status: "valid", Description: "valid HTTPProxy", Vhost: "tcpproxy.example.com"},
-			},
-		},
-		// issue 2309, each route must have at least one service
-		"invalid HTTPProxy due to empty route.service": {
-			objs: []interface{}{proxy49, s1},
+		"orphaned httpproxy": {
+			objs: []interface{}{proxyOrphaned},
 			want: map[k8s.FullName]Status{
-				{Name: proxy49.Name, Namespace: proxy49.Namespace}: {
-					Object:      proxy49,
-					Status:      "invalid",
-					Description: "route.services must have at least one entry",
-					Vhost:       "missing-service.example.com",
+				{Name: proxyOrphaned.Name, Namespace: proxyOrphaned.Namespace}: {
+					Object:      proxyOrphaned,
+					Status:      "orphaned",
+					Description: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy",
+					Conditions: []projcontour.StatusCondition{
+						{Type: "Valid", Status: "False", Reason: "Orphaned", Message: "this HTTPProxy is not part of a delegation chain from a root HTTPProxy", ObservedGeneration: 1},
+					},
 				},
 			},
 		},
-		"invalid fallback certificate passed to contour": {
-			fallbackCertificate: &k8s.FullName{
-				Name:      "invalid",
-				Namespace: "invalid",
-			},
-			objs: []interface{}{fallbackCertificate, fallbackSecret, sec1, s4},
-			want: map[k8s.FullName]Status{
-				{Name: fallbackCertificate.Name, Namespace: fallbackCertificate.Namespace}: {Object: fallbackCertificate, Status: "invalid", Description: "Spec.Virtualhost.TLS Secret \"invalid/invalid\" fallback certificate is invalid: Secret not found", Vhost: "example.com"},
-			},
-		},
-		"fallback certificate requested but cert not configured in contour": {
-			objs: []interface{}{fallbackCertificate, fallbackSecret, sec1, s4},
-			want: map[k8s.FullName]Status{
-				{Name: fallbackCertificate.Name, Namespace: fallbackCertificate.Namespace}: {Object: fallbackCertificate, Status: "invalid", Description: "Spec.Virtualhost.TLS enabled fallback but the fallback Certificate Secret is not configured in Contour configuration file", Vhost: "example.com"},
-			},
-		},
-		"fallback certificate requested and clientValidation also configured": {
-			objs: []interface{}{fallbackCertificateWithClientValidation, fallbackSecret, sec1, s4},
-			want: map[k8s.FullName]Status{
-				{Name: fallbackCertificateWithClientValidation.Name, Namespace: fallbackCertificateWithClientValidation.Namespace}: {Object: fallbackCertificateWithClientValidation, Status: "invalid", Description: "Spec.Virtualhost.TLS fallback & client validation are incompatible together", Vhost: "example.com"},
-			},
-		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
 			builder := Builder{
-				FallbackCertificate: tc.fallbackCertificate,
 				Source: KubernetesCache{
-					RootNamespaces: []string{"roots", "marketing"},
+					RootNamespaces: []string{"roots"},
 					FieldLogger:    testLogger(t),
 				},
 			}
-			if adobe.ShouldSkipTest(name) {
-				t.SkipNow()
-			}
-			for _, o := range tc.objs {
-				adobe.AdobefyObject(o)
+			for _, o := range append(tc.objs, s1) {
 				builder.Source.Insert(o)
 			}
-			dag := builder.Build()
-			got := dag.Statuses()
+			got := builder.Build().Statuses()
 			assert.Equal(t, tc.want, got)
 		})
 	}
 }
+
+func TestAdobeStatusMergeConditions(t *testing.T) {
+	proxy := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "child",
+			Namespace:  "roots",
+			Generation: 2,
+		},
+	}
+
+	sw := StatusWriter{
+		statuses: make(map[k8s.FullName]Status),
+	}
+	for _, msg := range []string{"first error", "second error", "first error"} {
+		osw, commit := sw.WithObject(proxy)
+		osw.SetInvalid(ReasonRouteInvalid, msg)
+		commit()
+	}
+	osw, commit := sw.WithObject(proxy)
+	osw.SetValid()
+	commit()
+
+	want := map[k8s.FullName]Status{
+		{Name: proxy.Name, Namespace: proxy.Namespace}: {
+			Object:      proxy,
+			Status:      "invalid",
+			Description: "first error",
+			Conditions: []projcontour.StatusCondition{
+				{Type: "Valid", Status: "False", Reason: "Invalid", Message: "first error", ObservedGeneration: 2},
+				{Type: "Error", Status: "True", Reason: "RouteInvalid", Message: "first error", ObservedGeneration: 2},
+				{Type: "Error", Status: "True", Reason: "RouteInvalid", Message: "second error", ObservedGeneration: 2},
+			},
+		},
+	}
+	assert.Equal(t, want, sw.statuses)
+}
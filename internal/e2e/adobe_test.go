@@ -18,6 +18,7 @@ import (
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoy_api_v2_listener "github.com/envoyproxy/go-control-plane/envoy/api/v2/listener"
 	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	envoy_aggregate "github.com/envoyproxy/go-control-plane/envoy/config/cluster/aggregate/v2alpha"
 	router "github.com/envoyproxy/go-control-plane/envoy/config/filter/http/router/v2"
 	http "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/http_connection_manager/v2"
 	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
@@ -55,6 +56,8 @@ import (
 //
 // == Service
 // IdleTimeout *Duration `json:"idleTimeout,omitempty"`
+// Priority int `json:"priority,omitempty"`
+// LoadBalancerPolicy *projcontour.LoadBalancerPolicy `json:"loadBalancerPolicy,omitempty"`
 //
 // == TLS
 // MaximumProtocolVersion string `json:"maximumProtocolVersion,omitempty"`
@@ -150,6 +153,99 @@ func TestAdobeRouteHashPolicy(t *testing.T) {
 	), nil)
 }
 
+func TestAdobeHTTPProxyHashPolicy(t *testing.T) {
+	rh, cc, done := setup(t)
+	defer done()
+
+	rh.OnAdd(&v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ws",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			Ports: []v1.ServicePort{{
+				Protocol:   "TCP",
+				Port:       80,
+				TargetPort: intstr.FromInt(8080),
+			}},
+		},
+	})
+
+	rh.OnAdd(&projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: projcontour.HTTPProxySpec{
+			VirtualHost: &projcontour.VirtualHost{Fqdn: "hashpolicy.hello.world"},
+			Routes: []projcontour.Route{{
+				Services: []projcontour.Service{{
+					Name: "ws",
+					Port: 80,
+				}},
+				HashPolicy: []projcontour.HashPolicy{
+					{
+						Cookie: &projcontour.HashPolicyCookie{
+							Name: "nom-nom-nom",
+							TTL:  "1h",
+							Path: "/",
+						},
+						Terminal: true,
+					},
+					{
+						ConnectionProperties: &projcontour.HashPolicyConnectionProperties{
+							SourceIP: true,
+						},
+					},
+				},
+			}},
+		},
+	})
+
+	// the cluster is forced to ring hash
+	c := cluster("default/ws/80/40633a6ca9", "default/ws", "default_ws_80")
+	c.CircuitBreakers = adobe.CircuitBreakers
+	c.DrainConnectionsOnHostRemoval = true
+	c.LbPolicy = v2.Cluster_RING_HASH
+	c.CommonHttpProtocolOptions = adobe.CommonHttpProtocolOptions
+
+	protos := []proto.Message{c}
+
+	assert.Equal(t, &v2.DiscoveryResponse{
+		VersionInfo: adobe.Hash(protos),
+		Resources:   resources(t, protos...),
+		TypeUrl:     clusterType,
+		Nonce:       "1",
+	}, streamCDS(t, cc))
+
+	r := routecluster(c.Name)
+	r.Route.HashPolicy = []*envoy_api_v2_route.RouteAction_HashPolicy{{
+		PolicySpecifier: &envoy_api_v2_route.RouteAction_HashPolicy_Cookie_{
+			Cookie: &envoy_api_v2_route.RouteAction_HashPolicy_Cookie{
+				Name: "nom-nom-nom",
+				Ttl:  protobuf.Duration(time.Hour),
+				Path: "/",
+			},
+		},
+		Terminal: true,
+	}, {
+		PolicySpecifier: &envoy_api_v2_route.RouteAction_HashPolicy_ConnectionProperties_{
+			ConnectionProperties: &envoy_api_v2_route.RouteAction_HashPolicy_ConnectionProperties{
+				SourceIp: true,
+			},
+		},
+	}}
+
+	assertRDS(t, cc, "1", virtualhosts(
+		envoy.VirtualHost("hashpolicy.hello.world",
+			&envoy_api_v2_route.Route{
+				Match:  routePrefix("/"),
+				Action: r,
+			},
+		),
+	), nil)
+}
+
 func TestAdobeRoutePerFilterConfigAllowDeny(t *testing.T) {
 	rh, cc, done := setup(t)
 	defer done()
@@ -2227,7 +2323,7 @@ func TestAdobeListenerWildcardFqdn(t *testing.T) {
 			Namespace: "ns",
 		},
 		Type: "kubernetes.io/tls",
-		Data: secretdata(CERTIFICATE, RSA_PRIVATE_KEY),
+		Data: secretdata(WILDCARD_CERTIFICATE, WILDCARD_PRIVATE_KEY),
 	}
 	rh.OnAdd(secret)
 
@@ -2623,6 +2719,87 @@ func TestAdobeClusterLbPolicy(t *testing.T) {
 }
 
 // HealthyPanicThreshold=100
+func TestAdobeClusterFailover(t *testing.T) {
+	rh, cc, done := setup(t)
+	defer done()
+
+	for _, name := range []string{"primary", "secondary"} {
+		rh.OnAdd(&v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+			},
+			Spec: v1.ServiceSpec{
+				Ports: []v1.ServicePort{{
+					Protocol:   "TCP",
+					Port:       80,
+					TargetPort: intstr.FromInt(8080),
+				}},
+			},
+		})
+	}
+
+	rh.OnAdd(&ingressroutev1.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "simple",
+			Namespace: "default",
+		},
+		Spec: ingressroutev1.IngressRouteSpec{
+			VirtualHost: &ingressroutev1.VirtualHost{Fqdn: "failover.hello.world"},
+			Routes: []ingressroutev1.Route{{
+				Match: "/",
+				Services: []ingressroutev1.Service{
+					{Name: "secondary", Port: 80, Priority: 1},
+					{Name: "primary", Port: 80},
+				},
+			}},
+		},
+	})
+
+	cPrimary := cluster("default/primary/80/da39a3ee5e", "default/primary", "default_primary_80")
+	cPrimary.CircuitBreakers = adobe.CircuitBreakers
+	cPrimary.DrainConnectionsOnHostRemoval = true
+	cPrimary.CommonHttpProtocolOptions = adobe.CommonHttpProtocolOptions
+
+	cSecondary := cluster("default/secondary/80/da39a3ee5e", "default/secondary", "default_secondary_80")
+	cSecondary.CircuitBreakers = adobe.CircuitBreakers
+	cSecondary.DrainConnectionsOnHostRemoval = true
+	cSecondary.CommonHttpProtocolOptions = adobe.CommonHttpProtocolOptions
+
+	cAggregate := &v2.Cluster{
+		Name:           "default/primary/80/failover/bc871555fe",
+		AltStatName:    "default_primary_80_aggregate",
+		ConnectTimeout: protobuf.Duration(250 * time.Millisecond),
+		LbPolicy:       v2.Cluster_CLUSTER_PROVIDED,
+		ClusterDiscoveryType: &v2.Cluster_ClusterType{
+			ClusterType: &v2.Cluster_CustomClusterType{
+				Name: "envoy.clusters.aggregate",
+				TypedConfig: protobuf.MustMarshalAny(&envoy_aggregate.ClusterConfig{
+					Clusters: []string{cPrimary.Name, cSecondary.Name},
+				}),
+			},
+		},
+	}
+
+	protos := []proto.Message{cPrimary, cAggregate, cSecondary} //ordered
+
+	assert.Equal(t, &v2.DiscoveryResponse{
+		VersionInfo: adobe.Hash(protos),
+		Resources:   resources(t, protos...),
+		TypeUrl:     clusterType,
+		Nonce:       "1",
+	}, streamCDS(t, cc))
+
+	assertRDS(t, cc, "1", virtualhosts(
+		envoy.VirtualHost("failover.hello.world",
+			&envoy_api_v2_route.Route{
+				Match:  routePrefix("/"),
+				Action: routecluster(cAggregate.Name),
+			},
+		),
+	), nil)
+}
+
 func TestAdobeClusterHealthyPanicThreshold(t *testing.T) {
 	rh, cc, done := setup(t)
 	defer done()
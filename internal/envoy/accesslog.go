@@ -23,7 +23,8 @@ import (
 
 //JSONFields is the canonical translation table for JSON fields to Envoy log template formats,
 //used for specifying fields for Envoy to log when JSON logging is enabled.
-//Only fields specified in this map may be used for JSON logging.
+// Adobe - custom fields of the form name=%COMMAND(param)% may be used as
+// well, see ParseAccessLogField.
 var JSONFields = map[string]string{
 	"@timestamp":                "%START_TIME%",
 	"ts":                        "%START_TIME%",
@@ -103,8 +104,9 @@ func FileAccessLogJSON(path string, keys []string) []*accesslog.AccessLog {
 		// This will silently ignore invalid headers.
 		// TODO(youngnick): this should tell users if a header is not valid
 		// https://github.com/projectcontour/contour/issues/1507
-		if template, ok := JSONFields[k]; ok {
-			jsonformat.Fields[k] = sv(template)
+		// Adobe - custom fields of the form name=%COMMAND(param)%
+		if name, template, err := ParseAccessLogField(k); err == nil {
+			jsonformat.Fields[name] = sv(template)
 		}
 	}
 
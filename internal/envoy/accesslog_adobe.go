@@ -0,0 +1,514 @@
+package envoy
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	accesslogv2 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v2"
+	accesslog "github.com/envoyproxy/go-control-plane/envoy/config/filter/accesslog/v2"
+	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+// AccessLogServiceCluster is the name of the cluster of the gRPC access log
+// service the access logs are sent to, unless sent to Contour itself
+// through its xDS cluster.
+const AccessLogServiceCluster = "accesslog_service"
+
+// tcpGRPCAccessLog is the sink for the TCP gRPC access log service, missing
+// from wellknown.
+const tcpGRPCAccessLog = "envoy.tcp_grpc_access_log"
+
+// requestProperties are the request headers the HTTP access log entries
+// carry, which need not be logged additionally.
+var requestProperties = map[string]bool{
+	":authority":            true,
+	":method":               true,
+	":path":                 true,
+	":scheme":               true,
+	"referer":               true,
+	"user-agent":            true,
+	"x-envoy-original-path": true,
+	"x-forwarded-for":       true,
+	"x-request-id":          true,
+}
+
+// GRPCAccessLog returns a new access log filter sending the HTTP access logs
+// to the gRPC access log service of cluster, along with the request and
+// response headers logged by the JSON fields and the request headers.
+func GRPCAccessLog(cluster, logName string, fields, headers []string) []*accesslog.AccessLog {
+	request, response := AccessLogHeaders(fields)
+	seen := make(map[string]bool)
+	for _, h := range request {
+		seen[h] = true
+	}
+	for _, h := range headers {
+		h = strings.ToLower(h)
+		if !requestProperties[h] && !seen[h] {
+			request = append(request, h)
+			seen[h] = true
+		}
+	}
+	return []*accesslog.AccessLog{{
+		Name: wellknown.HTTPGRPCAccessLog,
+		ConfigType: &accesslog.AccessLog_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&accesslogv2.HttpGrpcAccessLogConfig{
+				CommonConfig:                   commonGRPCAccessLogConfig(cluster, logName),
+				AdditionalRequestHeadersToLog:  request,
+				AdditionalResponseHeadersToLog: response,
+			}),
+		},
+	}}
+}
+
+// TCPGRPCAccessLog returns a new access log filter sending the access logs
+// of the TCP proxies to the gRPC access log service of cluster.
+func TCPGRPCAccessLog(cluster, logName string) []*accesslog.AccessLog {
+	return []*accesslog.AccessLog{{
+		Name: tcpGRPCAccessLog,
+		ConfigType: &accesslog.AccessLog_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&accesslogv2.TcpGrpcAccessLogConfig{
+				CommonConfig: commonGRPCAccessLogConfig(cluster, logName),
+			}),
+		},
+	}}
+}
+
+func commonGRPCAccessLogConfig(cluster, logName string) *accesslogv2.CommonGrpcAccessLogConfig {
+	return &accesslogv2.CommonGrpcAccessLogConfig{
+		LogName: logName,
+		GrpcService: &envoy_api_v2_core.GrpcService{
+			TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+				EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+					ClusterName: cluster,
+				},
+			},
+		},
+	}
+}
+
+// GRPCAccessLogCluster returns the cluster of the gRPC access log service
+// listening on address and port, resolved through DNS.
+func GRPCAccessLogCluster(address string, port int) *v2.Cluster {
+	return &v2.Cluster{
+		Name:                 AccessLogServiceCluster,
+		ConnectTimeout:       protobuf.Duration(5 * time.Second),
+		ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_STRICT_DNS),
+		LbPolicy:             v2.Cluster_ROUND_ROBIN,
+		LoadAssignment: &v2.ClusterLoadAssignment{
+			ClusterName: AccessLogServiceCluster,
+			Endpoints:   Endpoints(SocketAddress(address, port)),
+		},
+		Http2ProtocolOptions: new(envoy_api_v2_core.Http2ProtocolOptions), // enables http2
+	}
+}
+
+// envoyFormat is the default access log format of Envoy.
+const envoyFormat = `[%START_TIME%] "%REQ(:METHOD)% %REQ(X-ENVOY-ORIGINAL-PATH?:PATH)% %PROTOCOL%" ` +
+	`%RESPONSE_CODE% %RESPONSE_FLAGS% %BYTES_RECEIVED% %BYTES_SENT% %DURATION% %RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)% ` +
+	`"%REQ(X-FORWARDED-FOR)%" "%REQ(USER-AGENT)%" "%REQ(X-REQUEST-ID)%" "%REQ(:AUTHORITY)%" "%UPSTREAM_HOST%"`
+
+// FileAccessLogEnvoyHeaders returns a new file based access log filter
+// that will output Envoy's default access logs, followed by the quoted
+// values of the request headers.
+func FileAccessLogEnvoyHeaders(path string, headers []string) []*accesslog.AccessLog {
+	if len(headers) == 0 {
+		return FileAccessLogEnvoy(path)
+	}
+	format := envoyFormat
+	for _, h := range headers {
+		format += ` "%REQ(` + h + `)%"`
+	}
+	return []*accesslog.AccessLog{{
+		Name: wellknown.FileAccessLog,
+		ConfigType: &accesslog.AccessLog_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&accesslogv2.FileAccessLog{
+				Path: path,
+				AccessLogFormat: &accesslogv2.FileAccessLog_Format{
+					Format: format + "\n",
+				},
+			}),
+		},
+	}}
+}
+
+// FileAccessLogJSONHeaders returns a new file based access log filter
+// that will log in JSON format the fields of keys and the request headers,
+// each in a field named after it, such as x_adobe_tenant for X-Adobe-Tenant.
+func FileAccessLogJSONHeaders(path string, keys, headers []string) []*accesslog.AccessLog {
+	jsonformat := &_struct.Struct{
+		Fields: make(map[string]*_struct.Value),
+	}
+	for _, k := range keys {
+		if name, template, err := ParseAccessLogField(k); err == nil {
+			jsonformat.Fields[name] = sv(template)
+		}
+	}
+	for _, h := range headers {
+		jsonformat.Fields[HeaderField(h)] = sv("%REQ(" + h + ")%")
+	}
+
+	return []*accesslog.AccessLog{{
+		Name: wellknown.FileAccessLog,
+		ConfigType: &accesslog.AccessLog_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&accesslogv2.FileAccessLog{
+				Path: path,
+				AccessLogFormat: &accesslogv2.FileAccessLog_JsonFormat{
+					JsonFormat: jsonformat,
+				},
+			}),
+		},
+	}}
+}
+
+// HeaderField returns the name of the JSON field logging a request header.
+func HeaderField(header string) string {
+	return strings.ReplaceAll(strings.ToLower(header), "-", "_")
+}
+
+// AccessLogHeaders returns the request and response headers logged by the
+// JSON fields, other than those the HTTP access log entries carry already.
+// The header names are lower case.
+func AccessLogHeaders(fields []string) (request, response []string) {
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		_, template, err := ParseAccessLogField(f)
+		if err != nil {
+			continue
+		}
+		command, param, _ := AccessLogOperator(template)
+		for _, header := range strings.Split(param, "?") {
+			header = strings.ToLower(header)
+			switch {
+			case header == "" || seen[command+header]:
+			case command == "REQ" && !requestProperties[header]:
+				request = append(request, header)
+			case command == "RESP":
+				response = append(response, header)
+			}
+			seen[command+header] = true
+		}
+	}
+	return request, response
+}
+
+// ParseAccessLogField returns the name and format template of a JSON field,
+// either a key of JSONFields or a custom field of the form name=%COMMAND%
+// or name=%COMMAND(param)%, such as tenant_id=%REQ(X-Adobe-Tenant)%.
+func ParseAccessLogField(field string) (name, template string, err error) {
+	i := strings.IndexByte(field, '=')
+	if i < 0 {
+		template, ok := JSONFields[field]
+		if !ok {
+			return "", "", fmt.Errorf("unknown field %q", field)
+		}
+		return field, template, nil
+	}
+
+	name, template = field[:i], field[i+1:]
+	if name == "" {
+		return "", "", fmt.Errorf("field %q must be named", field)
+	}
+	if err := validAccessLogOperator(template); err != nil {
+		return "", "", fmt.Errorf("field %q: %v", name, err)
+	}
+	return name, template, nil
+}
+
+// accessLogParam is the parameter a format operator takes.
+type accessLogParam int
+
+const (
+	noParam accessLogParam = iota
+	optionalParam
+	requiredParam
+	headerParam
+)
+
+// accessLogCommands are the commands of the format operators of Envoy.
+var accessLogCommands = map[string]accessLogParam{
+	"BYTES_RECEIVED":                   noParam,
+	"BYTES_SENT":                       noParam,
+	"CONNECTION_ID":                    noParam,
+	"DOWNSTREAM_DIRECT_REMOTE_ADDRESS": noParam,
+	"DOWNSTREAM_DIRECT_REMOTE_ADDRESS_WITHOUT_PORT": noParam,
+	"DOWNSTREAM_LOCAL_ADDRESS":                      noParam,
+	"DOWNSTREAM_LOCAL_ADDRESS_WITHOUT_PORT":         noParam,
+	"DOWNSTREAM_LOCAL_SUBJECT":                      noParam,
+	"DOWNSTREAM_LOCAL_URI_SAN":                      noParam,
+	"DOWNSTREAM_PEER_CERT":                          noParam,
+	"DOWNSTREAM_PEER_CERT_V_END":                    optionalParam,
+	"DOWNSTREAM_PEER_CERT_V_START":                  optionalParam,
+	"DOWNSTREAM_PEER_FINGERPRINT_256":               noParam,
+	"DOWNSTREAM_PEER_ISSUER":                        noParam,
+	"DOWNSTREAM_PEER_SERIAL":                        noParam,
+	"DOWNSTREAM_PEER_SUBJECT":                       noParam,
+	"DOWNSTREAM_PEER_URI_SAN":                       noParam,
+	"DOWNSTREAM_REMOTE_ADDRESS":                     noParam,
+	"DOWNSTREAM_REMOTE_ADDRESS_WITHOUT_PORT":        noParam,
+	"DOWNSTREAM_TLS_CIPHER":                         noParam,
+	"DOWNSTREAM_TLS_SESSION_ID":                     noParam,
+	"DOWNSTREAM_TLS_VERSION":                        noParam,
+	"DURATION":                                      noParam,
+	"DYNAMIC_METADATA":                              requiredParam,
+	"FILTER_STATE":                                  requiredParam,
+	"HOSTNAME":                                      noParam,
+	"PROTOCOL":                                      noParam,
+	"REQ":                                           headerParam,
+	"REQUESTED_SERVER_NAME":                         noParam,
+	"REQUEST_DURATION":                              noParam,
+	"RESP":                                          headerParam,
+	"RESPONSE_CODE":                                 noParam,
+	"RESPONSE_CODE_DETAILS":                         noParam,
+	"RESPONSE_DURATION":                             noParam,
+	"RESPONSE_FLAGS":                                noParam,
+	"RESPONSE_TX_DURATION":                          noParam,
+	"ROUTE_NAME":                                    noParam,
+	"START_TIME":                                    optionalParam,
+	"TRAILER":                                       headerParam,
+	"UPSTREAM_CLUSTER":                              noParam,
+	"UPSTREAM_HOST":                                 noParam,
+	"UPSTREAM_LOCAL_ADDRESS":                        noParam,
+	"UPSTREAM_TRANSPORT_FAILURE_REASON":             noParam,
+}
+
+// validAccessLogOperator ensures template is a single format operator of
+// Envoy, with the parameter its command takes.
+func validAccessLogOperator(template string) error {
+	command, param, ok := AccessLogOperator(template)
+	if !ok {
+		return fmt.Errorf("%q must be of the form %%COMMAND%% or %%COMMAND(param)%%", template)
+	}
+	p, ok := accessLogCommands[command]
+	if !ok {
+		return fmt.Errorf("unknown command %q", command)
+	}
+	switch {
+	case p == noParam && param != "":
+		return fmt.Errorf("command %s takes no parameter", command)
+	case p == requiredParam && param == "":
+		return fmt.Errorf("command %s requires a parameter", command)
+	case p == headerParam:
+		headers := strings.Split(param, "?")
+		for _, h := range headers {
+			if h == "" || len(headers) > 2 {
+				return fmt.Errorf("command %s requires a header, or two alternative headers separated by ?", command)
+			}
+		}
+	}
+	return nil
+}
+
+// AccessLogOperator splits a format operator of the form %COMMAND% or
+// %COMMAND(param)% into its command and param.
+func AccessLogOperator(template string) (command, param string, ok bool) {
+	if len(template) < 3 || template[0] != '%' || template[len(template)-1] != '%' {
+		return "", "", false
+	}
+	command = template[1 : len(template)-1]
+	if i := strings.IndexByte(command, '('); i >= 0 {
+		if !strings.HasSuffix(command, ")") {
+			return "", "", false
+		}
+		command, param = command[:i], command[i+1:len(command)-1]
+	}
+	return command, param, command != ""
+}
+
+// Scopes of the access log filters, telling apart the runtime keys
+// overriding the filter configured in Contour and the filters of the
+// virtual hosts.
+const (
+	AccessLogListenerScope    = "listener"
+	AccessLogVirtualHostScope = "vhost"
+)
+
+// AccessLogFilter returns the filter of the requests logged, or nil if
+// they are all logged. Its values are overridden by the runtime keys
+// access_log.<scope>.status_code, status_code_min, status_code_max,
+// min_duration and sample_percent, the status code keys being numbered,
+// as in status_code_2_min, when there are several ranges.
+func AccessLogFilter(f *dag.AccessLogFilter, scope string) *accesslog.AccessLogFilter {
+	if f == nil {
+		return nil
+	}
+	runtimeKey := func(name string) string {
+		return "access_log." + scope + "." + name
+	}
+
+	var statusCodes []*accesslog.AccessLogFilter
+	for i, r := range f.StatusCodes {
+		key := runtimeKey("status_code")
+		if len(f.StatusCodes) > 1 {
+			key += "_" + strconv.Itoa(i+1)
+		}
+		if r.Min == r.Max {
+			statusCodes = append(statusCodes, statusCodeFilter(accesslog.ComparisonFilter_EQ, r.Min, key))
+			continue
+		}
+		statusCodes = append(statusCodes, AndAccessLogFilter(
+			statusCodeFilter(accesslog.ComparisonFilter_GE, r.Min, key+"_min"),
+			statusCodeFilter(accesslog.ComparisonFilter_LE, r.Max, key+"_max"),
+		))
+	}
+	filters := []*accesslog.AccessLogFilter{OrAccessLogFilter(statusCodes...)}
+
+	if f.MinDuration > 0 {
+		filters = append(filters, &accesslog.AccessLogFilter{
+			FilterSpecifier: &accesslog.AccessLogFilter_DurationFilter{
+				DurationFilter: &accesslog.DurationFilter{
+					Comparison: comparisonFilter(accesslog.ComparisonFilter_GE,
+						uint32(f.MinDuration/time.Millisecond), runtimeKey("min_duration")),
+				},
+			},
+		})
+	}
+
+	if f.SamplePercent > 0 {
+		filters = append(filters, &accesslog.AccessLogFilter{
+			FilterSpecifier: &accesslog.AccessLogFilter_RuntimeFilter{
+				RuntimeFilter: &accesslog.RuntimeFilter{
+					RuntimeKey: runtimeKey("sample_percent"),
+					PercentSampled: &envoy_type.FractionalPercent{
+						Numerator:   f.SamplePercent,
+						Denominator: envoy_type.FractionalPercent_HUNDRED,
+					},
+				},
+			},
+		})
+	}
+
+	for _, h := range headerMatcher(f.HeaderConditions) {
+		filters = append(filters, headerFilter(h))
+	}
+
+	return AndAccessLogFilter(filters...)
+}
+
+// AuthorityAccessLogFilter returns the filter of the requests for the given
+// hosts, or of the requests for any other host if invert is set.
+func AuthorityAccessLogFilter(hosts []string, invert bool) *accesslog.AccessLogFilter {
+	var names []string
+	for _, host := range hosts {
+		name := regexp.QuoteMeta(host)
+		if strings.HasPrefix(host, "*.") {
+			name = ".+" + name[len(`\*`):]
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return headerFilter(&envoy_api_v2_route.HeaderMatcher{
+		Name: ":authority",
+		HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_SafeRegexMatch{
+			SafeRegexMatch: SafeRegexMatch("(?i)(?:" + strings.Join(names, "|") + ")(?::[0-9]+)?"),
+		},
+		InvertMatch: invert,
+	})
+}
+
+// FilterAccessLog sets the filter of the access logs.
+func FilterAccessLog(logs []*accesslog.AccessLog, filter *accesslog.AccessLogFilter) []*accesslog.AccessLog {
+	for _, log := range logs {
+		log.Filter = filter
+	}
+	return logs
+}
+
+// AndAccessLogFilter returns the filter of the requests all the filters
+// select, or nil if there are none.
+func AndAccessLogFilter(filters ...*accesslog.AccessLogFilter) *accesslog.AccessLogFilter {
+	filters = accessLogFilters(filters)
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	default:
+		return &accesslog.AccessLogFilter{
+			FilterSpecifier: &accesslog.AccessLogFilter_AndFilter{
+				AndFilter: &accesslog.AndFilter{Filters: filters},
+			},
+		}
+	}
+}
+
+// OrAccessLogFilter returns the filter of the requests any of the filters
+// selects, or nil if there are none.
+func OrAccessLogFilter(filters ...*accesslog.AccessLogFilter) *accesslog.AccessLogFilter {
+	filters = accessLogFilters(filters)
+	switch len(filters) {
+	case 0:
+		return nil
+	case 1:
+		return filters[0]
+	default:
+		return &accesslog.AccessLogFilter{
+			FilterSpecifier: &accesslog.AccessLogFilter_OrFilter{
+				OrFilter: &accesslog.OrFilter{Filters: filters},
+			},
+		}
+	}
+}
+
+// accessLogFilters returns the filters which are not nil.
+func accessLogFilters(filters []*accesslog.AccessLogFilter) []*accesslog.AccessLogFilter {
+	var set []*accesslog.AccessLogFilter
+	for _, f := range filters {
+		if f != nil {
+			set = append(set, f)
+		}
+	}
+	return set
+}
+
+// headerFilter returns the filter of the requests whose headers match. As
+// Envoy never matches an inverted match against a missing header, the
+// requests missing the header are selected as well for inverted matches.
+func headerFilter(h *envoy_api_v2_route.HeaderMatcher) *accesslog.AccessLogFilter {
+	filter := func(h *envoy_api_v2_route.HeaderMatcher) *accesslog.AccessLogFilter {
+		return &accesslog.AccessLogFilter{
+			FilterSpecifier: &accesslog.AccessLogFilter_HeaderFilter{
+				HeaderFilter: &accesslog.HeaderFilter{Header: h},
+			},
+		}
+	}
+	if !h.InvertMatch {
+		return filter(h)
+	}
+	return OrAccessLogFilter(filter(h), filter(&envoy_api_v2_route.HeaderMatcher{
+		Name:                 h.Name,
+		HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true},
+		InvertMatch:          true,
+	}))
+}
+
+func statusCodeFilter(op accesslog.ComparisonFilter_Op, code uint32, runtimeKey string) *accesslog.AccessLogFilter {
+	return &accesslog.AccessLogFilter{
+		FilterSpecifier: &accesslog.AccessLogFilter_StatusCodeFilter{
+			StatusCodeFilter: &accesslog.StatusCodeFilter{
+				Comparison: comparisonFilter(op, code, runtimeKey),
+			},
+		},
+	}
+}
+
+func comparisonFilter(op accesslog.ComparisonFilter_Op, value uint32, runtimeKey string) *accesslog.ComparisonFilter {
+	return &accesslog.ComparisonFilter{
+		Op: op,
+		Value: &envoy_api_v2_core.RuntimeUInt32{
+			DefaultValue: value,
+			RuntimeKey:   runtimeKey,
+		},
+	}
+}
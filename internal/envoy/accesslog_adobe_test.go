@@ -0,0 +1,350 @@
+package envoy
+
+import (
+	"testing"
+	"time"
+
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_api_v2_route "github.com/envoyproxy/go-control-plane/envoy/api/v2/route"
+	accesslog_v2 "github.com/envoyproxy/go-control-plane/envoy/config/accesslog/v2"
+	envoy_accesslog "github.com/envoyproxy/go-control-plane/envoy/config/filter/accesslog/v2"
+	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
+	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+func TestAdobeGRPCAccessLog(t *testing.T) {
+	common := &accesslog_v2.CommonGrpcAccessLogConfig{
+		LogName: "contour",
+		GrpcService: &envoy_api_v2_core.GrpcService{
+			TargetSpecifier: &envoy_api_v2_core.GrpcService_EnvoyGrpc_{
+				EnvoyGrpc: &envoy_api_v2_core.GrpcService_EnvoyGrpc{
+					ClusterName: AccessLogServiceCluster,
+				},
+			},
+		},
+	}
+	tests := map[string]struct {
+		fields  []string
+		headers []string
+		want    []*envoy_accesslog.AccessLog
+	}{
+		"default fields": {
+			fields: DefaultFields,
+			want: []*envoy_accesslog.AccessLog{{
+				Name: wellknown.HTTPGRPCAccessLog,
+				ConfigType: &envoy_accesslog.AccessLog_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&accesslog_v2.HttpGrpcAccessLogConfig{
+						CommonConfig:                   common,
+						AdditionalRequestHeadersToLog:  []string{"uber-trace-id"},
+						AdditionalResponseHeadersToLog: []string{"x-envoy-upstream-service-time"},
+					}),
+				},
+			}},
+		},
+		"request headers": {
+			fields:  DefaultFields,
+			headers: []string{"X-Adobe-Tenant", "Uber-Trace-Id", "User-Agent"},
+			want: []*envoy_accesslog.AccessLog{{
+				Name: wellknown.HTTPGRPCAccessLog,
+				ConfigType: &envoy_accesslog.AccessLog_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&accesslog_v2.HttpGrpcAccessLogConfig{
+						CommonConfig:                   common,
+						AdditionalRequestHeadersToLog:  []string{"uber-trace-id", "x-adobe-tenant"},
+						AdditionalResponseHeadersToLog: []string{"x-envoy-upstream-service-time"},
+					}),
+				},
+			}},
+		},
+		"no headers": {
+			fields: []string{"@timestamp", "method", "path", "response_code", "invalid"},
+			want: []*envoy_accesslog.AccessLog{{
+				Name: wellknown.HTTPGRPCAccessLog,
+				ConfigType: &envoy_accesslog.AccessLog_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&accesslog_v2.HttpGrpcAccessLogConfig{
+						CommonConfig: common,
+					}),
+				},
+			}},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := GRPCAccessLog(AccessLogServiceCluster, "contour", tc.fields, tc.headers)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAdobeFileAccessLogHeaders(t *testing.T) {
+	got := FileAccessLogEnvoyHeaders("/var/log/tenant.log", []string{"X-Adobe-Tenant"})
+	want := []*envoy_accesslog.AccessLog{{
+		Name: wellknown.FileAccessLog,
+		ConfigType: &envoy_accesslog.AccessLog_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&accesslog_v2.FileAccessLog{
+				Path: "/var/log/tenant.log",
+				AccessLogFormat: &accesslog_v2.FileAccessLog_Format{
+					Format: `[%START_TIME%] "%REQ(:METHOD)% %REQ(X-ENVOY-ORIGINAL-PATH?:PATH)% %PROTOCOL%" ` +
+						`%RESPONSE_CODE% %RESPONSE_FLAGS% %BYTES_RECEIVED% %BYTES_SENT% %DURATION% %RESP(X-ENVOY-UPSTREAM-SERVICE-TIME)% ` +
+						`"%REQ(X-FORWARDED-FOR)%" "%REQ(USER-AGENT)%" "%REQ(X-REQUEST-ID)%" "%REQ(:AUTHORITY)%" "%UPSTREAM_HOST%" ` +
+						`"%REQ(X-Adobe-Tenant)%"` + "\n",
+				},
+			}),
+		},
+	}}
+	assert.Equal(t, want, got)
+	assert.Equal(t, FileAccessLogEnvoy("/dev/stdout"), FileAccessLogEnvoyHeaders("/dev/stdout", nil))
+
+	got = FileAccessLogJSONHeaders("/var/log/tenant.log", []string{"@timestamp", "invalid"}, []string{"X-Adobe-Tenant"})
+	want = []*envoy_accesslog.AccessLog{{
+		Name: wellknown.FileAccessLog,
+		ConfigType: &envoy_accesslog.AccessLog_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&accesslog_v2.FileAccessLog{
+				Path: "/var/log/tenant.log",
+				AccessLogFormat: &accesslog_v2.FileAccessLog_JsonFormat{
+					JsonFormat: &_struct.Struct{
+						Fields: map[string]*_struct.Value{
+							"@timestamp":     sv("%START_TIME%"),
+							"x_adobe_tenant": sv("%REQ(X-Adobe-Tenant)%"),
+						},
+					},
+				},
+			}),
+		},
+	}}
+	assert.Equal(t, want, got)
+	assert.Equal(t, FileAccessLogJSON("/dev/stdout", DefaultFields), FileAccessLogJSONHeaders("/dev/stdout", DefaultFields, nil))
+}
+
+func TestAdobeParseAccessLogField(t *testing.T) {
+	type field struct {
+		Name, Template string
+		Error          string
+	}
+	tests := map[string]field{
+		"@timestamp":                              {Name: "@timestamp", Template: "%START_TIME%"},
+		"tenant_id=%REQ(X-Adobe-Tenant)%":         {Name: "tenant_id", Template: "%REQ(X-Adobe-Tenant)%"},
+		"path=%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%": {Name: "path", Template: "%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%"},
+		"start=%START_TIME(%s)%":                  {Name: "start", Template: "%START_TIME(%s)%"},
+		"user=%DYNAMIC_METADATA(authz:user)%":     {Name: "user", Template: "%DYNAMIC_METADATA(authz:user)%"},
+		"tenant_id":                               {Error: `unknown field "tenant_id"`},
+		"=%DURATION%":                             {Error: `field "=%DURATION%" must be named`},
+		"duration=DURATION":                       {Error: `field "duration": "DURATION" must be of the form %COMMAND% or %COMMAND(param)%`},
+		"duration=%DURATION(ms)%":                 {Error: `field "duration": command DURATION takes no parameter`},
+		"user=%DYNAMIC_METADATA%":                 {Error: `field "user": command DYNAMIC_METADATA requires a parameter`},
+		"tenant_id=%REQ(A?B?C)%":                  {Error: `field "tenant_id": command REQ requires a header, or two alternative headers separated by ?`},
+		"tenant_id=%HEADER(X-Adobe-Tenant)%":      {Error: `field "tenant_id": unknown command "HEADER"`},
+	}
+	for f, want := range tests {
+		t.Run(f, func(t *testing.T) {
+			var got field
+			var err error
+			got.Name, got.Template, err = ParseAccessLogField(f)
+			if err != nil {
+				got.Error = err.Error()
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestAdobeAccessLogCustomFields(t *testing.T) {
+	fields := []string{"@timestamp", "tenant_id=%REQ(X-Adobe-Tenant)%", "cache=%RESP(X-Cache)%", "invalid=%REQ%"}
+
+	got := FileAccessLogJSON("/dev/stdout", fields)
+	want := []*envoy_accesslog.AccessLog{{
+		Name: wellknown.FileAccessLog,
+		ConfigType: &envoy_accesslog.AccessLog_TypedConfig{
+			TypedConfig: protobuf.MustMarshalAny(&accesslog_v2.FileAccessLog{
+				Path: "/dev/stdout",
+				AccessLogFormat: &accesslog_v2.FileAccessLog_JsonFormat{
+					JsonFormat: &_struct.Struct{
+						Fields: map[string]*_struct.Value{
+							"@timestamp": sv("%START_TIME%"),
+							"tenant_id":  sv("%REQ(X-Adobe-Tenant)%"),
+							"cache":      sv("%RESP(X-Cache)%"),
+						},
+					},
+				},
+			}),
+		},
+	}}
+	assert.Equal(t, want, got)
+
+	request, response := AccessLogHeaders(fields)
+	assert.Equal(t, []string{"x-adobe-tenant"}, request)
+	assert.Equal(t, []string{"x-cache"}, response)
+}
+
+func TestAdobeAccessLogOperator(t *testing.T) {
+	type operator struct {
+		Command, Param string
+		OK             bool
+	}
+	tests := map[string]operator{
+		"%START_TIME%":                       {Command: "START_TIME", OK: true},
+		"%REQ(X-ENVOY-ORIGINAL-PATH?:PATH)%": {Command: "REQ", Param: "X-ENVOY-ORIGINAL-PATH?:PATH", OK: true},
+		"%REQ(:METHOD%":                      {},
+		"%%":                                 {},
+		"START_TIME":                         {},
+	}
+	for template, want := range tests {
+		t.Run(template, func(t *testing.T) {
+			var got operator
+			got.Command, got.Param, got.OK = AccessLogOperator(template)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestAdobeAccessLogFilter(t *testing.T) {
+	statusCode := func(op envoy_accesslog.ComparisonFilter_Op, code uint32, runtimeKey string) *envoy_accesslog.AccessLogFilter {
+		return &envoy_accesslog.AccessLogFilter{
+			FilterSpecifier: &envoy_accesslog.AccessLogFilter_StatusCodeFilter{
+				StatusCodeFilter: &envoy_accesslog.StatusCodeFilter{
+					Comparison: &envoy_accesslog.ComparisonFilter{
+						Op: op,
+						Value: &envoy_api_v2_core.RuntimeUInt32{
+							DefaultValue: code,
+							RuntimeKey:   runtimeKey,
+						},
+					},
+				},
+			},
+		}
+	}
+	header := func(h *envoy_api_v2_route.HeaderMatcher) *envoy_accesslog.AccessLogFilter {
+		return &envoy_accesslog.AccessLogFilter{
+			FilterSpecifier: &envoy_accesslog.AccessLogFilter_HeaderFilter{
+				HeaderFilter: &envoy_accesslog.HeaderFilter{Header: h},
+			},
+		}
+	}
+	and := func(filters ...*envoy_accesslog.AccessLogFilter) *envoy_accesslog.AccessLogFilter {
+		return &envoy_accesslog.AccessLogFilter{
+			FilterSpecifier: &envoy_accesslog.AccessLogFilter_AndFilter{
+				AndFilter: &envoy_accesslog.AndFilter{Filters: filters},
+			},
+		}
+	}
+	or := func(filters ...*envoy_accesslog.AccessLogFilter) *envoy_accesslog.AccessLogFilter {
+		return &envoy_accesslog.AccessLogFilter{
+			FilterSpecifier: &envoy_accesslog.AccessLogFilter_OrFilter{
+				OrFilter: &envoy_accesslog.OrFilter{Filters: filters},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		filter *dag.AccessLogFilter
+		scope  string
+		want   *envoy_accesslog.AccessLogFilter
+	}{
+		"no filter": {},
+		"single status code": {
+			filter: &dag.AccessLogFilter{
+				StatusCodes: []dag.StatusCodeRange{{Min: 503, Max: 503}},
+			},
+			scope: AccessLogListenerScope,
+			want:  statusCode(envoy_accesslog.ComparisonFilter_EQ, 503, "access_log.listener.status_code"),
+		},
+		"single status code range": {
+			filter: &dag.AccessLogFilter{
+				StatusCodes: []dag.StatusCodeRange{{Min: 500, Max: 599}},
+			},
+			scope: AccessLogListenerScope,
+			want: and(
+				statusCode(envoy_accesslog.ComparisonFilter_GE, 500, "access_log.listener.status_code_min"),
+				statusCode(envoy_accesslog.ComparisonFilter_LE, 599, "access_log.listener.status_code_max"),
+			),
+		},
+		"all conditions": {
+			scope: AccessLogVirtualHostScope,
+			filter: &dag.AccessLogFilter{
+				StatusCodes:   []dag.StatusCodeRange{{Min: 302, Max: 302}, {Min: 500, Max: 599}},
+				MinDuration:   1500 * time.Millisecond,
+				SamplePercent: 10,
+				HeaderConditions: []dag.HeaderCondition{
+					{Name: "user-agent", Value: "kube-probe", MatchType: "contains", Invert: true},
+				},
+			},
+			want: and(
+				or(
+					statusCode(envoy_accesslog.ComparisonFilter_EQ, 302, "access_log.vhost.status_code_1"),
+					and(
+						statusCode(envoy_accesslog.ComparisonFilter_GE, 500, "access_log.vhost.status_code_2_min"),
+						statusCode(envoy_accesslog.ComparisonFilter_LE, 599, "access_log.vhost.status_code_2_max"),
+					),
+				),
+				&envoy_accesslog.AccessLogFilter{
+					FilterSpecifier: &envoy_accesslog.AccessLogFilter_DurationFilter{
+						DurationFilter: &envoy_accesslog.DurationFilter{
+							Comparison: &envoy_accesslog.ComparisonFilter{
+								Op: envoy_accesslog.ComparisonFilter_GE,
+								Value: &envoy_api_v2_core.RuntimeUInt32{
+									DefaultValue: 1500,
+									RuntimeKey:   "access_log.vhost.min_duration",
+								},
+							},
+						},
+					},
+				},
+				&envoy_accesslog.AccessLogFilter{
+					FilterSpecifier: &envoy_accesslog.AccessLogFilter_RuntimeFilter{
+						RuntimeFilter: &envoy_accesslog.RuntimeFilter{
+							RuntimeKey: "access_log.vhost.sample_percent",
+							PercentSampled: &envoy_type.FractionalPercent{
+								Numerator:   10,
+								Denominator: envoy_type.FractionalPercent_HUNDRED,
+							},
+						},
+					},
+				},
+				or(
+					header(&envoy_api_v2_route.HeaderMatcher{
+						Name: "user-agent",
+						HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_SafeRegexMatch{
+							SafeRegexMatch: SafeRegexMatch(".*kube-probe.*"),
+						},
+						InvertMatch: true,
+					}),
+					header(&envoy_api_v2_route.HeaderMatcher{
+						Name:                 "user-agent",
+						HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_PresentMatch{PresentMatch: true},
+						InvertMatch:          true,
+					}),
+				),
+			),
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := AccessLogFilter(tc.filter, tc.scope)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestAdobeAuthorityAccessLogFilter(t *testing.T) {
+	got := AuthorityAccessLogFilter([]string{"www.example.com", "*.example.net"}, false)
+	want := &envoy_accesslog.AccessLogFilter{
+		FilterSpecifier: &envoy_accesslog.AccessLogFilter_HeaderFilter{
+			HeaderFilter: &envoy_accesslog.HeaderFilter{
+				Header: &envoy_api_v2_route.HeaderMatcher{
+					Name: ":authority",
+					HeaderMatchSpecifier: &envoy_api_v2_route.HeaderMatcher_SafeRegexMatch{
+						SafeRegexMatch: SafeRegexMatch(`(?i)(?:www\.example\.com|.+\.example\.net)(?::[0-9]+)?`),
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, got)
+
+	if got := AuthorityAccessLogFilter(nil, true); got != nil {
+		t.Fatalf("expected no filter, got %v", got)
+	}
+}
@@ -14,6 +14,34 @@ func DownstreamTLSContextAdobe(serverSecret *dag.Secret, tlsMinProtoVersion, tls
 	return tls
 }
 
+// DownstreamTLSParams sets the cipher suites and ECDH curves offered by the
+// DownstreamTlsContext. Those left empty keep their current value.
+func DownstreamTLSParams(tls *envoy_api_v2_auth.DownstreamTlsContext, cipherSuites, ecdhCurves []string) *envoy_api_v2_auth.DownstreamTlsContext {
+	if len(cipherSuites) > 0 {
+		tls.CommonTlsContext.TlsParams.CipherSuites = cipherSuites
+	}
+	if len(ecdhCurves) > 0 {
+		tls.CommonTlsContext.TlsParams.EcdhCurves = ecdhCurves
+	}
+	return tls
+}
+
+// DownstreamTLSSecondaryCertificate adds the secondary certificate to the
+// certificates the DownstreamTlsContext presents, if one is supplied. Envoy
+// picks the certificate matching the key types the client supports. As the
+// v2 API delivers a single certificate over SDS, the primary and secondary
+// certificates are then both inlined in the DownstreamTlsContext.
+func DownstreamTLSSecondaryCertificate(tls *envoy_api_v2_auth.DownstreamTlsContext, primary, secondary *dag.Secret) *envoy_api_v2_auth.DownstreamTlsContext {
+	if secondary != nil {
+		tls.CommonTlsContext.TlsCertificateSdsSecretConfigs = nil
+		tls.CommonTlsContext.TlsCertificates = []*envoy_api_v2_auth.TlsCertificate{
+			tlsCertificate(primary),
+			tlsCertificate(secondary),
+		}
+	}
+	return tls
+}
+
 // GetDownstreamTLSContext retrieves the DownstreamTlsContext from a FilterChain
 func GetDownstreamTLSContext(fc *envoy_api_v2_listener.FilterChain) *envoy_api_v2_auth.DownstreamTlsContext {
 	cfg := fc.GetTransportSocket().GetTypedConfig()
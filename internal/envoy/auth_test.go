@@ -114,3 +114,64 @@ func TestUpstreamTLSContext(t *testing.T) {
 		})
 	}
 }
+
+func TestDownstreamTLSSecondaryCertificate(t *testing.T) {
+	secret := func(name string) *dag.Secret {
+		return &dag.Secret{
+			Object: &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: "default",
+				},
+				Type: v1.SecretTypeTLS,
+				Data: map[string][]byte{v1.TLSCertKey: []byte(name + " cert"), v1.TLSPrivateKeyKey: []byte(name + " key")},
+			},
+		}
+	}
+	inline := func(b string) *envoy_api_v2_core.DataSource {
+		return &envoy_api_v2_core.DataSource{
+			Specifier: &envoy_api_v2_core.DataSource_InlineBytes{InlineBytes: []byte(b)},
+		}
+	}
+
+	tests := map[string]struct {
+		secondary *dag.Secret
+		wantSDS   []*envoy_api_v2_auth.SdsSecretConfig
+		want      []*envoy_api_v2_auth.TlsCertificate
+	}{
+		"no secondary certificate": {
+			wantSDS: []*envoy_api_v2_auth.SdsSecretConfig{{
+				Name:      Secretname(secret("rsa")),
+				SdsConfig: ConfigSource("contour"),
+			}},
+		},
+		"secondary certificate": {
+			secondary: secret("ecdsa"),
+			want: []*envoy_api_v2_auth.TlsCertificate{{
+				PrivateKey:       inline("rsa key"),
+				CertificateChain: inline("rsa cert"),
+			}, {
+				PrivateKey:       inline("ecdsa key"),
+				CertificateChain: inline("ecdsa cert"),
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			tls := DownstreamTLSSecondaryCertificate(
+				DownstreamTLSContext(secret("rsa"), envoy_api_v2_auth.TlsParameters_TLSv1_1, nil),
+				secret("rsa"),
+				tc.secondary)
+			if err := tls.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.wantSDS, tls.CommonTlsContext.TlsCertificateSdsSecretConfigs); diff != "" {
+				t.Fatal(diff)
+			}
+			if diff := cmp.Diff(tc.want, tls.CommonTlsContext.TlsCertificates); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
@@ -153,7 +153,7 @@ func bootstrap(c *BootstrapConfig) ([]bootstrapf, error) {
 }
 
 func bootstrapConfig(c *BootstrapConfig) *envoy_api_bootstrap.Bootstrap {
-	return &envoy_api_bootstrap.Bootstrap{
+	b := &envoy_api_bootstrap.Bootstrap{
 		DynamicResources: &envoy_api_bootstrap.Bootstrap_DynamicResources{
 			LdsConfig: ConfigSource("contour"),
 			CdsConfig: ConfigSource("contour"),
@@ -213,6 +213,8 @@ func bootstrapConfig(c *BootstrapConfig) *envoy_api_bootstrap.Bootstrap {
 			Address:       SocketAddress(c.adminAddress(), c.adminPort()),
 		},
 	}
+	localCluster(c, b)
+	return b
 }
 
 func upstreamFileTLSContext(c *BootstrapConfig) *envoy_api_v2_auth.UpstreamTlsContext {
@@ -366,6 +368,11 @@ type BootstrapConfig struct {
 	// ResourcesDir is the directory where out of line Envoy resources can be placed.
 	ResourcesDir string
 
+	// LocalClusterService is the EDS service name (namespace/name[/port]) of
+	// the Envoy Service itself. When set, it is registered as the local
+	// cluster so that zone aware routing can be enabled on upstream clusters.
+	LocalClusterService string
+
 	// SkipFilePathCheck specifies whether to skip checking whether files
 	// referenced in the configuration actually exist. This option is for
 	// testing only.
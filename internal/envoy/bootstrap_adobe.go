@@ -0,0 +1,35 @@
+package envoy
+
+import (
+	"time"
+
+	api "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_bootstrap "github.com/envoyproxy/go-control-plane/envoy/config/bootstrap/v2"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+// localClusterName is the name of the static cluster describing the Envoy
+// fleet itself. Envoy compares its own zone distribution with the upstream
+// one using this cluster when zone aware routing is enabled.
+const localClusterName = "local-cluster"
+
+// localCluster adds the local cluster to the bootstrap if configured.
+// Envoy requires the local cluster to be a static resource, its members are
+// still discovered through EDS.
+func localCluster(c *BootstrapConfig, b *envoy_api_bootstrap.Bootstrap) {
+	if c.LocalClusterService == "" {
+		return
+	}
+	b.StaticResources.Clusters = append(b.StaticResources.Clusters, &api.Cluster{
+		Name:                 localClusterName,
+		ConnectTimeout:       protobuf.Duration(250 * time.Millisecond),
+		ClusterDiscoveryType: ClusterDiscoveryType(api.Cluster_EDS),
+		EdsClusterConfig: &api.Cluster_EdsClusterConfig{
+			EdsConfig:   ConfigSource("contour"),
+			ServiceName: c.LocalClusterService,
+		},
+	})
+	b.ClusterManager = &envoy_api_bootstrap.ClusterManager{
+		LocalClusterName: localClusterName,
+	}
+}
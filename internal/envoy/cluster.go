@@ -47,7 +47,9 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 	cluster.Name = Clustername(c)
 	cluster.AltStatName = altStatName(service)
 	cluster.LbPolicy = lbPolicy(c.LoadBalancerPolicy)
+	lbConfig(cluster, c.LoadBalancerOptions)
 	cluster.HealthChecks = edshealthcheck(c)
+	localityLbConfig(cluster.CommonLbConfig, service.LocalityLbPolicy)
 	cluster.CircuitBreakers = &envoy_cluster.CircuitBreakers{
 		Thresholds: []*envoy_cluster.CircuitBreakers_Thresholds{{
 			MaxConnections: u32nil(1000000),
@@ -82,18 +84,18 @@ func Cluster(c *dag.Cluster) *v2.Cluster {
 	switch c.Protocol {
 	case "tls":
 		cluster.TransportSocket = UpstreamTLSTransportSocket(
-			UpstreamTLSContext(
+			upstreamTLS(clientCertificate(UpstreamTLSContext(
 				c.UpstreamValidation,
 				c.SNI,
-			),
+			), c.ClientCertificate), c.UpstreamTLS),
 		)
 	case "h2":
 		cluster.TransportSocket = UpstreamTLSTransportSocket(
-			UpstreamTLSContext(
+			upstreamTLS(clientCertificate(UpstreamTLSContext(
 				c.UpstreamValidation,
 				service.ExternalName,
 				"h2",
-			),
+			), c.ClientCertificate), c.UpstreamTLS),
 		)
 		fallthrough
 	case "h2c":
@@ -182,6 +184,16 @@ func Clustername(cluster *dag.Cluster) string {
 			buf += strconv.Itoa(int(hc.HealthyThreshold))
 		}
 		buf += hc.Path
+		buf += healthCheckOptions(hc)
+	}
+	if lbo := cluster.LoadBalancerOptions; lbo != nil {
+		buf += loadBalancerOptions(lbo)
+	}
+	if cc := cluster.ClientCertificate; cc != nil {
+		buf += cc.Namespace() + "/" + cc.Name()
+	}
+	if u := cluster.UpstreamTLS; u != nil {
+		buf += upstreamTLSParams(u)
 	}
 	if uv := cluster.UpstreamValidation; uv != nil {
 		buf += uv.CACertificate.Object.ObjectMeta.Name
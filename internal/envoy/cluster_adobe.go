@@ -0,0 +1,202 @@
+package envoy
+
+import (
+	"crypto/sha1" // nolint:gosec
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
+	envoy_aggregate "github.com/envoyproxy/go-control-plane/envoy/config/cluster/aggregate/v2alpha"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/projectcontour/contour/internal/dag"
+	"github.com/projectcontour/contour/internal/protobuf"
+)
+
+// localityLbConfig configures the locality aware load balancing of the
+// cluster according to the policy requested on the Service.
+//
+// "LocalityWeighted" spreads requests across localities in proportion to the
+// load balancing weight EDS assigns to each of them.
+// "ZoneAware" prefers upstream hosts in the same zone as the Envoy; this
+// requires Envoy to know its own zone (--service-zone) and the local cluster
+// to be set in the bootstrap (see BootstrapConfig.LocalClusterService).
+func localityLbConfig(lbc *v2.Cluster_CommonLbConfig, policy string) {
+	switch policy {
+	case "LocalityWeighted":
+		lbc.LocalityConfigSpecifier = &v2.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+			LocalityWeightedLbConfig: &v2.Cluster_CommonLbConfig_LocalityWeightedLbConfig{},
+		}
+	case "ZoneAware":
+		lbc.LocalityConfigSpecifier = &v2.Cluster_CommonLbConfig_ZoneAwareLbConfig_{
+			ZoneAwareLbConfig: &v2.Cluster_CommonLbConfig_ZoneAwareLbConfig{},
+		}
+	}
+}
+
+// lbConfig applies the strategy-specific options of the load balancer
+// policy of the cluster. Options of another policy are ignored.
+func lbConfig(cluster *v2.Cluster, lbo *dag.LoadBalancerOptions) {
+	if lbo == nil {
+		return
+	}
+	switch cluster.LbPolicy {
+	case v2.Cluster_RING_HASH:
+		if lbo.MinimumRingSize == 0 && lbo.MaximumRingSize == 0 {
+			return
+		}
+		cfg := &v2.Cluster_RingHashLbConfig{}
+		if lbo.MinimumRingSize > 0 {
+			cfg.MinimumRingSize = &wrappers.UInt64Value{Value: lbo.MinimumRingSize}
+		}
+		if lbo.MaximumRingSize > 0 {
+			cfg.MaximumRingSize = &wrappers.UInt64Value{Value: lbo.MaximumRingSize}
+		}
+		cluster.LbConfig = &v2.Cluster_RingHashLbConfig_{
+			RingHashLbConfig: cfg,
+		}
+	case v2.Cluster_LEAST_REQUEST:
+		if lbo.ChoiceCount == 0 {
+			return
+		}
+		cluster.LbConfig = &v2.Cluster_LeastRequestLbConfig_{
+			LeastRequestLbConfig: &v2.Cluster_LeastRequestLbConfig{
+				ChoiceCount: protobuf.UInt32(lbo.ChoiceCount),
+			},
+		}
+	}
+}
+
+// loadBalancerOptions returns a string representation of the load balancer
+// options, to be included in the cluster name.
+func loadBalancerOptions(lbo *dag.LoadBalancerOptions) string {
+	var buf string
+	if lbo.MinimumRingSize > 0 || lbo.MaximumRingSize > 0 {
+		buf += fmt.Sprintf("ring%d-%d", lbo.MinimumRingSize, lbo.MaximumRingSize)
+	}
+	if lbo.ChoiceCount > 0 {
+		buf += "choice" + strconv.Itoa(int(lbo.ChoiceCount))
+	}
+	return buf
+}
+
+// clientCertificate configures the upstream TLS context to present the
+// client certificate, delivered over SDS, if one is supplied.
+func clientCertificate(tc *envoy_api_v2_auth.UpstreamTlsContext, secret *dag.Secret) *envoy_api_v2_auth.UpstreamTlsContext {
+	if secret != nil {
+		tc.CommonTlsContext.TlsCertificateSdsSecretConfigs = []*envoy_api_v2_auth.SdsSecretConfig{{
+			Name:      Secretname(secret),
+			SdsConfig: ConfigSource("contour"),
+		}}
+	}
+	return tc
+}
+
+// AggregateCluster creates an aggregate cluster which sends traffic to the
+// cluster and, once it has no healthy hosts left, to its failover clusters
+// in order.
+func AggregateCluster(c *dag.Cluster) *v2.Cluster {
+	clusters := []string{Clustername(c)}
+	for _, fc := range c.Failover {
+		clusters = append(clusters, Clustername(fc))
+	}
+	return &v2.Cluster{
+		Name:           AggregateClustername(c),
+		AltStatName:    altStatName(c.Upstream) + "_aggregate", // distinct from the stats of the primary cluster
+		ConnectTimeout: protobuf.Duration(250 * time.Millisecond),
+		LbPolicy:       v2.Cluster_CLUSTER_PROVIDED,
+		ClusterDiscoveryType: &v2.Cluster_ClusterType{
+			ClusterType: &v2.Cluster_CustomClusterType{
+				Name: "envoy.clusters.aggregate",
+				TypedConfig: protobuf.MustMarshalAny(&envoy_aggregate.ClusterConfig{
+					Clusters: clusters,
+				}),
+			},
+		},
+	}
+}
+
+// AggregateClustername returns the name of the aggregate CDS cluster
+// for the cluster and its failover clusters.
+func AggregateClustername(c *dag.Cluster) string {
+	buf := Clustername(c)
+	for _, fc := range c.Failover {
+		buf += Clustername(fc)
+	}
+
+	// This isn't a crypto hash, we just want a unique name.
+	hash := sha1.Sum([]byte(buf)) // nolint:gosec
+
+	service := c.Upstream
+	return hashname(60, service.Namespace, service.Name, strconv.Itoa(int(service.Port)), "failover", fmt.Sprintf("%x", hash[:5]))
+}
+
+// routeClustername returns the name of the cluster a route sends traffic
+// to, that is the aggregate cluster if failover clusters are configured.
+func routeClustername(c *dag.Cluster) string {
+	if len(c.Failover) > 0 {
+		return AggregateClustername(c)
+	}
+	return Clustername(c)
+}
+
+// healthCheckOptions returns a string representation of the optional
+// settings of the health check policy, to be included in the cluster name.
+// It is empty if none is set so existing cluster names are unchanged.
+func healthCheckOptions(hc *dag.HTTPHealthCheckPolicy) string {
+	var buf string
+	for _, r := range hc.ExpectedStatuses {
+		buf += fmt.Sprintf("%d-%d", r.Start, r.End)
+	}
+	keys := make([]string, 0, len(hc.RequestHeaders))
+	for k := range hc.RequestHeaders {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf += k + "=" + hc.RequestHeaders[k]
+	}
+	if hc.InitialJitter != nil {
+		buf += "jitter" + hc.InitialJitter.String()
+	}
+	if hc.IntervalJitterPercent != nil {
+		buf += "jitter%" + strconv.Itoa(int(*hc.IntervalJitterPercent))
+	}
+	if hc.NoTrafficInterval > 0 {
+		buf += hc.NoTrafficInterval.String()
+	}
+	if hc.GRPC {
+		buf += "grpc" + hc.GRPCServiceName
+	}
+	return buf
+}
+
+// upstreamTLS configures the upstream TLS context with the TLS parameters,
+// if any are supplied. ALPN protocols supplied replace those of the context.
+func upstreamTLS(tc *envoy_api_v2_auth.UpstreamTlsContext, u *dag.UpstreamTLS) *envoy_api_v2_auth.UpstreamTlsContext {
+	if u == nil {
+		return tc
+	}
+	if u.MinProtoVersion != envoy_api_v2_auth.TlsParameters_TLS_AUTO ||
+		u.MaxProtoVersion != envoy_api_v2_auth.TlsParameters_TLS_AUTO || len(u.CipherSuites) > 0 {
+		tc.CommonTlsContext.TlsParams = &envoy_api_v2_auth.TlsParameters{
+			TlsMinimumProtocolVersion: u.MinProtoVersion,
+			TlsMaximumProtocolVersion: u.MaxProtoVersion,
+			CipherSuites:              u.CipherSuites,
+		}
+	}
+	if len(u.ALPNProtocols) > 0 {
+		tc.CommonTlsContext.AlpnProtocols = u.ALPNProtocols
+	}
+	return tc
+}
+
+// upstreamTLSParams returns a string representation of the upstream TLS
+// parameters, to be included in the cluster name.
+func upstreamTLSParams(u *dag.UpstreamTLS) string {
+	return fmt.Sprintf("tls%d-%d", u.MinProtoVersion, u.MaxProtoVersion) +
+		strings.Join(u.CipherSuites, ":") + "alpn" + strings.Join(u.ALPNProtocols, ",")
+}
@@ -18,6 +18,7 @@ import (
 	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
+	envoy_api_v2_auth "github.com/envoyproxy/go-control-plane/envoy/api/v2/auth"
 	envoy_cluster "github.com/envoyproxy/go-control-plane/envoy/api/v2/cluster"
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
 	envoy_type "github.com/envoyproxy/go-control-plane/envoy/type"
@@ -173,6 +174,63 @@ func TestCluster(t *testing.T) {
 				),
 			},
 		},
+		"tls upstream with client certificate": {
+			cluster: &dag.Cluster{
+				Upstream:          service(s1, "tls"),
+				Protocol:          "tls",
+				ClientCertificate: secret,
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/5f9224d734",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					&envoy_api_v2_auth.UpstreamTlsContext{
+						CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
+							TlsCertificateSdsSecretConfigs: []*envoy_api_v2_auth.SdsSecretConfig{{
+								Name:      "default/secret/da39a3ee5e",
+								SdsConfig: ConfigSource("contour"),
+							}},
+						},
+					},
+				),
+			},
+		},
+		"tls upstream with tls parameters": {
+			cluster: &dag.Cluster{
+				Upstream: service(s1, "tls"),
+				Protocol: "tls",
+				UpstreamTLS: &dag.UpstreamTLS{
+					MinProtoVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+					CipherSuites:    []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+					ALPNProtocols:   []string{"http/1.1"},
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/a4e90e9b11",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				TransportSocket: UpstreamTLSTransportSocket(
+					&envoy_api_v2_auth.UpstreamTlsContext{
+						CommonTlsContext: &envoy_api_v2_auth.CommonTlsContext{
+							TlsParams: &envoy_api_v2_auth.TlsParameters{
+								TlsMinimumProtocolVersion: envoy_api_v2_auth.TlsParameters_TLSv1_2,
+								CipherSuites:              []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+							},
+							AlpnProtocols: []string{"http/1.1"},
+						},
+					},
+				),
+			},
+		},
 		"tls upstream - external name": {
 			cluster: &dag.Cluster{
 				Upstream: service(svcExternal, "tls"),
@@ -340,6 +398,104 @@ func TestCluster(t *testing.T) {
 				LbPolicy: v2.Cluster_ROUND_ROBIN,
 			},
 		},
+		"cluster with locality weighted lb policy": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name:             s1.Name,
+					Namespace:        s1.Namespace,
+					ServicePort:      &s1.Spec.Ports[0],
+					LocalityLbPolicy: "LocalityWeighted",
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/da39a3ee5e",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CommonLbConfig: &v2.Cluster_CommonLbConfig{
+					LocalityConfigSpecifier: &v2.Cluster_CommonLbConfig_LocalityWeightedLbConfig_{
+						LocalityWeightedLbConfig: &v2.Cluster_CommonLbConfig_LocalityWeightedLbConfig{},
+					},
+				},
+			},
+		},
+		"cluster with zone aware lb policy": {
+			cluster: &dag.Cluster{
+				Upstream: &dag.Service{
+					Name:             s1.Name,
+					Namespace:        s1.Namespace,
+					ServicePort:      &s1.Spec.Ports[0],
+					LocalityLbPolicy: "ZoneAware",
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/da39a3ee5e",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				CommonLbConfig: &v2.Cluster_CommonLbConfig{
+					LocalityConfigSpecifier: &v2.Cluster_CommonLbConfig_ZoneAwareLbConfig_{
+						ZoneAwareLbConfig: &v2.Cluster_CommonLbConfig_ZoneAwareLbConfig{},
+					},
+				},
+			},
+		},
+		"cluster with ring hash lb options": {
+			cluster: &dag.Cluster{
+				Upstream:           service(s1),
+				LoadBalancerPolicy: "RingHash",
+				LoadBalancerOptions: &dag.LoadBalancerOptions{
+					MinimumRingSize: 1024,
+					MaximumRingSize: 4096,
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/8ce49ca7aa",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				LbPolicy: v2.Cluster_RING_HASH,
+				LbConfig: &v2.Cluster_RingHashLbConfig_{
+					RingHashLbConfig: &v2.Cluster_RingHashLbConfig{
+						MinimumRingSize: &wrappers.UInt64Value{Value: 1024},
+						MaximumRingSize: &wrappers.UInt64Value{Value: 4096},
+					},
+				},
+			},
+		},
+		"cluster with least request lb options": {
+			cluster: &dag.Cluster{
+				Upstream:           service(s1),
+				LoadBalancerPolicy: "WeightedLeastRequest",
+				LoadBalancerOptions: &dag.LoadBalancerOptions{
+					ChoiceCount: 5,
+				},
+			},
+			want: &v2.Cluster{
+				Name:                 "default/kuard/443/e0a2f33b6a",
+				AltStatName:          "default_kuard_443",
+				ClusterDiscoveryType: ClusterDiscoveryType(v2.Cluster_EDS),
+				EdsClusterConfig: &v2.Cluster_EdsClusterConfig{
+					EdsConfig:   ConfigSource("contour"),
+					ServiceName: "default/kuard/http",
+				},
+				LbPolicy: v2.Cluster_LEAST_REQUEST,
+				LbConfig: &v2.Cluster_LeastRequestLbConfig_{
+					LeastRequestLbConfig: &v2.Cluster_LeastRequestLbConfig{
+						ChoiceCount: protobuf.UInt32(5),
+					},
+				},
+			},
+		},
 
 		"tcp service": {
 			cluster: &dag.Cluster{
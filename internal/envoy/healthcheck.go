@@ -56,19 +56,32 @@ func httpHealthCheck(cluster *dag.Cluster) *envoy_api_v2_core.HealthCheck {
 		HealthyThreshold:      countOrDefault(hc.HealthyThreshold, hcHealthyThreshold),
 		HealthChecker: &envoy_api_v2_core.HealthCheck_HttpHealthCheck_{
 			HttpHealthCheck: &envoy_api_v2_core.HealthCheck_HttpHealthCheck{
-				Path: hc.Path,
-				Host: host,
-				// [200, 400) so 200-399 to match K8s probes
-				ExpectedStatuses: []*envoy_types.Int64Range{
-					{
-						Start: 200,
-						End:   400,
-					},
-				},
+				Path:                hc.Path,
+				Host:                host,
+				ExpectedStatuses:    expectedStatuses(hc.ExpectedStatuses),
+				RequestHeadersToAdd: HeaderValueList(hc.RequestHeaders, false),
 			},
 		},
 	}
 
+	if hc.GRPC {
+		healthCheck.HealthChecker = &envoy_api_v2_core.HealthCheck_GrpcHealthCheck_{
+			GrpcHealthCheck: &envoy_api_v2_core.HealthCheck_GrpcHealthCheck{
+				ServiceName: hc.GRPCServiceName,
+				Authority:   host,
+			},
+		}
+	}
+	if hc.InitialJitter != nil {
+		healthCheck.InitialJitter = protobuf.Duration(*hc.InitialJitter)
+	}
+	if hc.IntervalJitterPercent != nil {
+		healthCheck.IntervalJitterPercent = *hc.IntervalJitterPercent
+	}
+	if hc.NoTrafficInterval > 0 {
+		healthCheck.NoTrafficInterval = protobuf.Duration(hc.NoTrafficInterval)
+	}
+
 	if enabled, err := strconv.ParseBool(os.Getenv("HC_FAILURE_LOGGING_ENABLED")); enabled && err == nil {
 		healthCheck.EventLogPath = "/dev/stderr"
 		healthCheck.AlwaysLogHealthCheckFailures = true
@@ -92,6 +105,25 @@ func tcpHealthCheck(cluster *dag.Cluster) *envoy_api_v2_core.HealthCheck {
 	}
 }
 
+// expectedStatuses returns the healthy status ranges,
+// [200, 400) so 200-399 to match K8s probes by default.
+func expectedStatuses(ranges []dag.StatusRange) []*envoy_types.Int64Range {
+	if len(ranges) == 0 {
+		return []*envoy_types.Int64Range{{
+			Start: 200,
+			End:   400,
+		}}
+	}
+	statuses := make([]*envoy_types.Int64Range, 0, len(ranges))
+	for _, r := range ranges {
+		statuses = append(statuses, &envoy_types.Int64Range{
+			Start: r.Start,
+			End:   r.End,
+		})
+	}
+	return statuses
+}
+
 func durationOrDefault(d, def time.Duration) *duration.Duration {
 	if d != 0 {
 		return protobuf.Duration(d)
@@ -20,7 +20,9 @@ import (
 	"github.com/projectcontour/contour/adobe"
 
 	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	envoy_types "github.com/envoyproxy/go-control-plane/envoy/type"
 	"github.com/google/go-cmp/cmp"
+	"github.com/projectcontour/contour/internal/assert"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/protobuf"
 )
@@ -104,3 +106,79 @@ func TestHealthCheck(t *testing.T) {
 		})
 	}
 }
+
+func TestAdobeHealthCheckOptions(t *testing.T) {
+	jitter := 5 * time.Second
+	percent := uint32(10)
+
+	tests := map[string]struct {
+		cluster *dag.Cluster
+		want    *envoy_api_v2_core.HealthCheck
+	}{
+		"http options": {
+			cluster: &dag.Cluster{
+				HTTPHealthCheckPolicy: &dag.HTTPHealthCheckPolicy{
+					Path: "/healthy",
+					ExpectedStatuses: []dag.StatusRange{
+						{Start: 200, End: 300},
+						{Start: 404, End: 405},
+					},
+					RequestHeaders:        map[string]string{"X-Health": "true"},
+					InitialJitter:         &jitter,
+					IntervalJitterPercent: &percent,
+					NoTrafficInterval:     30 * time.Second,
+				},
+			},
+			want: &envoy_api_v2_core.HealthCheck{
+				Timeout:               protobuf.Duration(hcTimeout),
+				Interval:              protobuf.Duration(hcInterval),
+				InitialJitter:         protobuf.Duration(5 * time.Second),
+				IntervalJitterPercent: 10,
+				NoTrafficInterval:     protobuf.Duration(30 * time.Second),
+				UnhealthyThreshold:    protobuf.UInt32(3),
+				HealthyThreshold:      protobuf.UInt32(2),
+				HealthChecker: &envoy_api_v2_core.HealthCheck_HttpHealthCheck_{
+					HttpHealthCheck: &envoy_api_v2_core.HealthCheck_HttpHealthCheck{
+						Path: "/healthy",
+						Host: "contour-envoy-healthcheck",
+						ExpectedStatuses: []*envoy_types.Int64Range{
+							{Start: 200, End: 300},
+							{Start: 404, End: 405},
+						},
+						RequestHeadersToAdd: HeaderValueList(map[string]string{"X-Health": "true"}, false),
+					},
+				},
+			},
+		},
+		"grpc": {
+			cluster: &dag.Cluster{
+				Protocol: "h2c",
+				HTTPHealthCheckPolicy: &dag.HTTPHealthCheckPolicy{
+					Host:            "grpc.example.com",
+					GRPC:            true,
+					GRPCServiceName: "helloworld.Greeter",
+				},
+			},
+			want: &envoy_api_v2_core.HealthCheck{
+				Timeout:               protobuf.Duration(hcTimeout),
+				Interval:              protobuf.Duration(hcInterval),
+				InitialJitter:         protobuf.Duration(hcInitialJitter),
+				IntervalJitterPercent: hcIntervalJitterPercent,
+				UnhealthyThreshold:    protobuf.UInt32(3),
+				HealthyThreshold:      protobuf.UInt32(2),
+				HealthChecker: &envoy_api_v2_core.HealthCheck_GrpcHealthCheck_{
+					GrpcHealthCheck: &envoy_api_v2_core.HealthCheck_GrpcHealthCheck{
+						ServiceName: "helloworld.Greeter",
+						Authority:   "grpc.example.com",
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, httpHealthCheck(tc.cluster))
+		})
+	}
+}
@@ -30,6 +30,7 @@ import (
 	tcp "github.com/envoyproxy/go-control-plane/envoy/config/filter/network/tcp_proxy/v2"
 	"github.com/envoyproxy/go-control-plane/pkg/wellknown"
 	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/golang/protobuf/ptypes/wrappers"
 	"github.com/projectcontour/contour/internal/dag"
 	"github.com/projectcontour/contour/internal/protobuf"
 	"github.com/projectcontour/contour/internal/sorter"
@@ -228,6 +229,22 @@ func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*accesslog.
 	// Set to 9001 because now it's OVER NINE THOUSAND.
 	idleTimeout := protobuf.Duration(9001 * time.Second)
 
+	// Adobe - per proxy idle timeout, connect attempts and access log
+	switch {
+	case proxy.IdleTimeout < 0:
+		// a zero timeout disables it
+		idleTimeout = protobuf.Duration(0)
+	case proxy.IdleTimeout > 0:
+		idleTimeout = protobuf.Duration(proxy.IdleTimeout)
+	}
+	var maxConnectAttempts *wrappers.UInt32Value
+	if proxy.MaxConnectAttempts > 0 {
+		maxConnectAttempts = protobuf.UInt32(proxy.MaxConnectAttempts)
+	}
+	if proxy.DisableAccessLog {
+		accesslogger = nil
+	}
+
 	switch len(proxy.Clusters) {
 	case 1:
 		return &envoy_api_v2_listener.Filter{
@@ -238,8 +255,9 @@ func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*accesslog.
 					ClusterSpecifier: &tcp.TcpProxy_Cluster{
 						Cluster: Clustername(proxy.Clusters[0]),
 					},
-					AccessLog:   accesslogger,
-					IdleTimeout: idleTimeout,
+					AccessLog:          accesslogger,
+					IdleTimeout:        idleTimeout,
+					MaxConnectAttempts: maxConnectAttempts,
 				}),
 			},
 		}
@@ -266,8 +284,9 @@ func TCPProxy(statPrefix string, proxy *dag.TCPProxy, accesslogger []*accesslog.
 							Clusters: clusters,
 						},
 					},
-					AccessLog:   accesslogger,
-					IdleTimeout: idleTimeout,
+					AccessLog:          accesslogger,
+					IdleTimeout:        idleTimeout,
+					MaxConnectAttempts: maxConnectAttempts,
 				}),
 			},
 		}
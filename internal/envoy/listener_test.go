@@ -498,6 +498,46 @@ func TestTCPProxy(t *testing.T) {
 				},
 			},
 		},
+		"idle timeout, connect attempts and no access log": {
+			proxy: &dag.TCPProxy{
+				Clusters:           []*dag.Cluster{c1},
+				IdleTimeout:        30 * time.Minute,
+				MaxConnectAttempts: 3,
+				DisableAccessLog:   true,
+			},
+			want: &envoy_api_v2_listener.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_api_v2_listener.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_config_v2_tcpproxy.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_config_v2_tcpproxy.TcpProxy_Cluster{
+							Cluster: Clustername(c1),
+						},
+						IdleTimeout:        protobuf.Duration(30 * time.Minute),
+						MaxConnectAttempts: protobuf.UInt32(3),
+					}),
+				},
+			},
+		},
+		"idle timeout disabled": {
+			proxy: &dag.TCPProxy{
+				Clusters:    []*dag.Cluster{c1},
+				IdleTimeout: -1,
+			},
+			want: &envoy_api_v2_listener.Filter{
+				Name: wellknown.TCPProxy,
+				ConfigType: &envoy_api_v2_listener.Filter_TypedConfig{
+					TypedConfig: protobuf.MustMarshalAny(&envoy_config_v2_tcpproxy.TcpProxy{
+						StatPrefix: statPrefix,
+						ClusterSpecifier: &envoy_config_v2_tcpproxy.TcpProxy_Cluster{
+							Cluster: Clustername(c1),
+						},
+						AccessLog:   FileAccessLogEnvoy(accessLogPath),
+						IdleTimeout: protobuf.Duration(0),
+					}),
+				},
+			},
+		},
 	}
 
 	for name, tc := range tests {
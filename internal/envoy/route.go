@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 	"time"
 
 	v2 "github.com/envoyproxy/go-control-plane/envoy/api/v2"
@@ -83,7 +84,7 @@ func RouteRoute(r *dag.Route) *envoy_api_v2_route.Route_Route {
 
 	if singleSimpleCluster(r.Clusters) {
 		ra.ClusterSpecifier = &envoy_api_v2_route.RouteAction_Cluster{
-			Cluster: Clustername(r.Clusters[0]),
+			Cluster: routeClustername(r.Clusters[0]),
 		}
 	} else {
 		ra.ClusterSpecifier = &envoy_api_v2_route.RouteAction_WeightedClusters{
@@ -256,7 +257,7 @@ func weightedClusters(clusters []*dag.Cluster) *envoy_api_v2_route.WeightedClust
 		total += cluster.Weight
 
 		c := &envoy_api_v2_route.WeightedCluster_ClusterWeight{
-			Name:   Clustername(cluster),
+			Name:   routeClustername(cluster),
 			Weight: protobuf.UInt32(cluster.Weight),
 		}
 		if cluster.RequestHeadersPolicy != nil {
@@ -285,7 +286,8 @@ func weightedClusters(clusters []*dag.Cluster) *envoy_api_v2_route.WeightedClust
 // VirtualHost creates a new route.VirtualHost.
 func VirtualHost(hostname string, routes ...*envoy_api_v2_route.Route) *envoy_api_v2_route.VirtualHost {
 	domains := []string{hostname}
-	if hostname != "*" {
+	// Adobe - Envoy only allows a single wildcard per domain
+	if hostname != "*" && !strings.HasPrefix(hostname, "*.") {
 		domains = append(domains, hostname+":*")
 	}
 	return &envoy_api_v2_route.VirtualHost{
@@ -36,17 +36,22 @@ func Secret(s *dag.Secret) *envoy_api_v2_auth.Secret {
 	return &envoy_api_v2_auth.Secret{
 		Name: Secretname(s),
 		Type: &envoy_api_v2_auth.Secret_TlsCertificate{
-			TlsCertificate: &envoy_api_v2_auth.TlsCertificate{
-				PrivateKey: &envoy_api_v2_core.DataSource{
-					Specifier: &envoy_api_v2_core.DataSource_InlineBytes{
-						InlineBytes: s.PrivateKey(),
-					},
-				},
-				CertificateChain: &envoy_api_v2_core.DataSource{
-					Specifier: &envoy_api_v2_core.DataSource_InlineBytes{
-						InlineBytes: s.Cert(),
-					},
-				},
+			TlsCertificate: tlsCertificate(s),
+		},
+	}
+}
+
+// Adobe - tlsCertificate returns the inline certificate and key of secret.
+func tlsCertificate(s *dag.Secret) *envoy_api_v2_auth.TlsCertificate {
+	return &envoy_api_v2_auth.TlsCertificate{
+		PrivateKey: &envoy_api_v2_core.DataSource{
+			Specifier: &envoy_api_v2_core.DataSource_InlineBytes{
+				InlineBytes: s.PrivateKey(),
+			},
+		},
+		CertificateChain: &envoy_api_v2_core.DataSource{
+			Specifier: &envoy_api_v2_core.DataSource_InlineBytes{
+				InlineBytes: s.Cert(),
 			},
 		},
 	}
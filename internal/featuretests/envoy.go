@@ -249,7 +249,7 @@ func filterchaintlsfallback(fallbackSecret *v1.Secret, peerValidationContext *da
 		envoy.Filters(
 			envoy.HTTPConnectionManagerBuilder().
 				RouteConfigName(contour.ENVOY_FALLBACK_ROUTECONFIG).
-				MetricsPrefix(contour.ENVOY_HTTPS_LISTENER).
+				MetricsPrefix(contour.ENVOY_FALLBACK_ROUTECONFIG).
 				AccessLoggers(envoy.FileAccessLogEnvoy("/dev/stdout")).
 				RequestTimeout(0).
 				Get(),
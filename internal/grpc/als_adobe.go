@@ -0,0 +1,341 @@
+package grpc
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v2"
+	als "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v2"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/ptypes"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/projectcontour/contour/internal/envoy"
+	"github.com/sirupsen/logrus"
+)
+
+// AccessLogReceiver implements the gRPC access log service, writing the
+// access log entries streamed by Envoy as JSON lines with the given access
+// log fields.
+type AccessLogReceiver struct {
+	fields []accessLogField
+
+	// out receives the entries, one per line.
+	out io.Writer
+
+	logrus.FieldLogger
+
+	// mu serialises the writes of the streams to out.
+	mu sync.Mutex
+}
+
+// accessLogField is a JSON field of the entries and the format operator
+// it logs.
+type accessLogField struct {
+	name, command, param string
+}
+
+// NewAccessLogReceiver returns an AccessLogReceiver writing the entries to
+// out with the given fields, either keys of envoy.JSONFields or custom
+// fields of the form name=%COMMAND%. The fields which are not valid are
+// left out.
+func NewAccessLogReceiver(fields []string, out io.Writer, log logrus.FieldLogger) *AccessLogReceiver {
+	r := &AccessLogReceiver{
+		out:         out,
+		FieldLogger: log,
+	}
+	for _, f := range fields {
+		name, template, err := envoy.ParseAccessLogField(f)
+		if err != nil {
+			log.WithError(err).Warn("ignoring access log field")
+			continue
+		}
+		command, param, _ := envoy.AccessLogOperator(template)
+		r.fields = append(r.fields, accessLogField{name: name, command: command, param: param})
+	}
+	return r
+}
+
+// StreamAccessLogs writes the access log entries of the stream until Envoy
+// closes it.
+func (r *AccessLogReceiver) StreamAccessLogs(st als.AccessLogService_StreamAccessLogsServer) error {
+	var log logrus.FieldLogger = r.FieldLogger
+	for {
+		msg, err := st.Recv()
+		if err == io.EOF {
+			return st.SendAndClose(&als.StreamAccessLogsResponse{})
+		}
+		if err != nil {
+			log.WithError(err).Debug("access log stream closed")
+			return err
+		}
+
+		// the identifier is sent on the first message of the stream only.
+		if id := msg.Identifier; id != nil {
+			log = r.FieldLogger.WithField("log_name", id.LogName)
+			if id.Node != nil {
+				log = log.WithField("node_id", id.Node.Id)
+			}
+		}
+
+		switch entries := msg.LogEntries.(type) {
+		case *als.StreamAccessLogsMessage_HttpLogs:
+			for _, entry := range entries.HttpLogs.GetLogEntry() {
+				r.write(log, httpEntry{entry})
+			}
+		case *als.StreamAccessLogsMessage_TcpLogs:
+			for _, entry := range entries.TcpLogs.GetLogEntry() {
+				r.write(log, tcpEntry{entry})
+			}
+		}
+	}
+}
+
+// write writes the entry as a JSON line, leaving out the empty fields.
+func (r *AccessLogReceiver) write(log logrus.FieldLogger, e accessLogEntry) {
+	record := make(map[string]string, len(r.fields))
+	for _, f := range r.fields {
+		if v := e.value(f.command, f.param); v != "" {
+			record[f.name] = v
+		}
+	}
+	buf, err := json.Marshal(record)
+	if err != nil {
+		log.WithError(err).Error("failed to marshal access log entry")
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.out.Write(append(buf, '\n')); err != nil {
+		log.WithError(err).Error("failed to write access log entry")
+	}
+}
+
+// accessLogEntry evaluates the format operators against an access log entry.
+type accessLogEntry interface {
+	value(command, param string) string
+}
+
+type httpEntry struct {
+	*data.HTTPAccessLogEntry
+}
+
+func (e httpEntry) value(command, param string) string {
+	switch command {
+	case "REQ":
+		return firstHeader(param, e.requestHeader)
+	case "RESP":
+		return firstHeader(param, func(name string) string {
+			return e.GetResponse().GetResponseHeaders()[name]
+		})
+	case "BYTES_RECEIVED":
+		return uintValue(e.GetRequest().GetRequestBodyBytes())
+	case "BYTES_SENT":
+		return uintValue(e.GetResponse().GetResponseBodyBytes())
+	case "PROTOCOL":
+		return httpVersions[e.GetProtocolVersion()]
+	case "RESPONSE_CODE":
+		if code := e.GetResponse().GetResponseCode(); code != nil {
+			return strconv.FormatUint(uint64(code.Value), 10)
+		}
+		return ""
+	case "RESPONSE_CODE_DETAILS":
+		return e.GetResponse().GetResponseCodeDetails()
+	default:
+		return commonValue(e.GetCommonProperties(), command, param)
+	}
+}
+
+// requestHeader returns the request header name, or the request property
+// the entry carries in its place.
+func (e httpEntry) requestHeader(name string) string {
+	req := e.GetRequest()
+	switch name {
+	case ":authority":
+		return req.GetAuthority()
+	case ":method":
+		if req.GetRequestMethod() == envoy_api_v2_core.RequestMethod_METHOD_UNSPECIFIED {
+			return ""
+		}
+		return req.GetRequestMethod().String()
+	case ":path":
+		return req.GetPath()
+	case ":scheme":
+		return req.GetScheme()
+	case "referer":
+		return req.GetReferer()
+	case "user-agent":
+		return req.GetUserAgent()
+	case "x-envoy-original-path":
+		return req.GetOriginalPath()
+	case "x-forwarded-for":
+		return req.GetForwardedFor()
+	case "x-request-id":
+		return req.GetRequestId()
+	default:
+		return req.GetRequestHeaders()[name]
+	}
+}
+
+type tcpEntry struct {
+	*data.TCPAccessLogEntry
+}
+
+func (e tcpEntry) value(command, param string) string {
+	switch command {
+	case "BYTES_RECEIVED":
+		return uintValue(e.GetConnectionProperties().GetReceivedBytes())
+	case "BYTES_SENT":
+		return uintValue(e.GetConnectionProperties().GetSentBytes())
+	default:
+		return commonValue(e.GetCommonProperties(), command, param)
+	}
+}
+
+var httpVersions = map[data.HTTPAccessLogEntry_HTTPVersion]string{
+	data.HTTPAccessLogEntry_HTTP10: "HTTP/1.0",
+	data.HTTPAccessLogEntry_HTTP11: "HTTP/1.1",
+	data.HTTPAccessLogEntry_HTTP2:  "HTTP/2",
+	data.HTTPAccessLogEntry_HTTP3:  "HTTP/3",
+}
+
+// commonValue evaluates the format operators the HTTP and TCP entries share.
+func commonValue(c *data.AccessLogCommon, command, param string) string {
+	switch command {
+	case "START_TIME":
+		if c.GetStartTime() == nil {
+			return ""
+		}
+		start, err := ptypes.Timestamp(c.GetStartTime())
+		if err != nil {
+			return ""
+		}
+		return start.UTC().Format("2006-01-02T15:04:05.000Z")
+	case "DURATION":
+		if c.GetTimeToLastDownstreamTxByte() == nil {
+			return ""
+		}
+		d, err := ptypes.Duration(c.GetTimeToLastDownstreamTxByte())
+		if err != nil {
+			return ""
+		}
+		return strconv.FormatInt(int64(d/time.Millisecond), 10)
+	case "DOWNSTREAM_LOCAL_ADDRESS":
+		return address(c.GetDownstreamLocalAddress())
+	case "DOWNSTREAM_REMOTE_ADDRESS":
+		return address(c.GetDownstreamRemoteAddress())
+	case "DYNAMIC_METADATA":
+		return metadataValue(c.GetMetadata(), param)
+	case "REQUESTED_SERVER_NAME":
+		return c.GetTlsProperties().GetTlsSniHostname()
+	case "RESPONSE_FLAGS":
+		return responseFlags(c.GetResponseFlags())
+	case "ROUTE_NAME":
+		return c.GetRouteName()
+	case "UPSTREAM_CLUSTER":
+		return c.GetUpstreamCluster()
+	case "UPSTREAM_HOST":
+		return address(c.GetUpstreamRemoteAddress())
+	case "UPSTREAM_LOCAL_ADDRESS":
+		return address(c.GetUpstreamLocalAddress())
+	default:
+		return ""
+	}
+}
+
+// firstHeader returns the first of the alternative headers of param, of
+// the form NAME or NAME?ALTERNATIVE, which is set.
+func firstHeader(param string, header func(name string) string) string {
+	for _, name := range strings.Split(param, "?") {
+		if v := header(strings.ToLower(name)); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// metadataValue returns the dynamic metadata of param, of the form
+// NAMESPACE:KEY:..., as Envoy logs it: strings as they are and other
+// values as JSON.
+func metadataValue(md *envoy_api_v2_core.Metadata, param string) string {
+	path := strings.Split(param, ":")
+	s, ok := md.GetFilterMetadata()[path[0]]
+	if !ok {
+		return ""
+	}
+	v := &_struct.Value{Kind: &_struct.Value_StructValue{StructValue: s}}
+	for _, key := range path[1:] {
+		v, ok = v.GetStructValue().GetFields()[key]
+		if !ok {
+			return ""
+		}
+	}
+	if s, ok := v.GetKind().(*_struct.Value_StringValue); ok {
+		return s.StringValue
+	}
+	buf, err := new(jsonpb.Marshaler).MarshalToString(v)
+	if err != nil {
+		return ""
+	}
+	return buf
+}
+
+func uintValue(v uint64) string {
+	return strconv.FormatUint(v, 10)
+}
+
+// address formats a socket address as Envoy does, as ip:port.
+func address(a *envoy_api_v2_core.Address) string {
+	sa := a.GetSocketAddress()
+	if sa == nil {
+		return ""
+	}
+	return net.JoinHostPort(sa.GetAddress(), strconv.FormatUint(uint64(sa.GetPortValue()), 10))
+}
+
+// responseFlags returns the short codes of the response flags, separated
+// by commas, or "-" if none is set.
+func responseFlags(f *data.ResponseFlags) string {
+	if f == nil {
+		return ""
+	}
+	var flags []string
+	for _, flag := range []struct {
+		set  bool
+		code string
+	}{
+		{f.FailedLocalHealthcheck, "LH"},
+		{f.NoHealthyUpstream, "UH"},
+		{f.UpstreamRequestTimeout, "UT"},
+		{f.LocalReset, "LR"},
+		{f.UpstreamRemoteReset, "UR"},
+		{f.UpstreamConnectionFailure, "UF"},
+		{f.UpstreamConnectionTermination, "UC"},
+		{f.UpstreamOverflow, "UO"},
+		{f.NoRouteFound, "NR"},
+		{f.DelayInjected, "DI"},
+		{f.FaultInjected, "FI"},
+		{f.RateLimited, "RL"},
+		{f.UnauthorizedDetails != nil, "UAEX"},
+		{f.RateLimitServiceError, "RLSE"},
+		{f.DownstreamConnectionTermination, "DC"},
+		{f.UpstreamRetryLimitExceeded, "URX"},
+		{f.StreamIdleTimeout, "SI"},
+		{f.InvalidEnvoyRequestHeaders, "IH"},
+		{f.DownstreamProtocolError, "DPE"},
+	} {
+		if flag.set {
+			flags = append(flags, flag.code)
+		}
+	}
+	if len(flags) == 0 {
+		return "-"
+	}
+	return strings.Join(flags, ",")
+}
@@ -0,0 +1,212 @@
+package grpc
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+	"time"
+
+	envoy_api_v2_core "github.com/envoyproxy/go-control-plane/envoy/api/v2/core"
+	data "github.com/envoyproxy/go-control-plane/envoy/data/accesslog/v2"
+	als "github.com/envoyproxy/go-control-plane/envoy/service/accesslog/v2"
+	"github.com/golang/protobuf/ptypes"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	"github.com/projectcontour/contour/internal/assert"
+	"github.com/projectcontour/contour/internal/protobuf"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+func TestAdobeAccessLogReceiver(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	start, _ := ptypes.TimestampProto(time.Date(2020, 6, 1, 12, 30, 0, 0, time.UTC))
+	common := func(flags *data.ResponseFlags) *data.AccessLogCommon {
+		return &data.AccessLogCommon{
+			StartTime:                  start,
+			TimeToLastDownstreamTxByte: ptypes.DurationProto(42 * time.Millisecond),
+			DownstreamRemoteAddress:    socketAddress("10.0.0.1", 53211),
+			UpstreamRemoteAddress:      socketAddress("192.168.0.7", 8080),
+			UpstreamCluster:            "default/kuard/80/da39a3ee5e",
+			TlsProperties:              &data.TLSProperties{TlsSniHostname: "kuard.example.com"},
+			ResponseFlags:              flags,
+		}
+	}
+
+	tests := map[string]struct {
+		fields   []string
+		messages []*als.StreamAccessLogsMessage
+		want     string
+	}{
+		"http entries": {
+			fields: []string{"@timestamp", "authority", "method", "path", "protocol", "response_code", "response_flags",
+				"duration", "bytes_sent", "upstream_host", "upstream_service_time", "x_trace_id", "requested_server_name"},
+			messages: []*als.StreamAccessLogsMessage{{
+				Identifier: &als.StreamAccessLogsMessage_Identifier{LogName: "contour"},
+				LogEntries: &als.StreamAccessLogsMessage_HttpLogs{
+					HttpLogs: &als.StreamAccessLogsMessage_HTTPAccessLogEntries{
+						LogEntry: []*data.HTTPAccessLogEntry{{
+							CommonProperties: common(&data.ResponseFlags{}),
+							ProtocolVersion:  data.HTTPAccessLogEntry_HTTP2,
+							Request: &data.HTTPRequestProperties{
+								RequestMethod:  envoy_api_v2_core.RequestMethod_GET,
+								Authority:      "kuard.example.com",
+								Path:           "/",
+								OriginalPath:   "/kuard/",
+								RequestHeaders: map[string]string{"x-trace-id": "abc"},
+							},
+							Response: &data.HTTPResponseProperties{
+								ResponseCode:      protobuf.UInt32(200),
+								ResponseBodyBytes: 1024,
+								ResponseHeaders:   map[string]string{"x-envoy-upstream-service-time": "40"},
+							},
+						}},
+					},
+				},
+			}, {
+				LogEntries: &als.StreamAccessLogsMessage_HttpLogs{
+					HttpLogs: &als.StreamAccessLogsMessage_HTTPAccessLogEntries{
+						LogEntry: []*data.HTTPAccessLogEntry{{
+							CommonProperties: common(&data.ResponseFlags{NoHealthyUpstream: true, UpstreamOverflow: true}),
+							ProtocolVersion:  data.HTTPAccessLogEntry_HTTP11,
+							Request: &data.HTTPRequestProperties{
+								RequestMethod: envoy_api_v2_core.RequestMethod_POST,
+								Authority:     "kuard.example.com",
+								Path:          "/upload",
+							},
+							Response: &data.HTTPResponseProperties{
+								ResponseCode: protobuf.UInt32(503),
+							},
+						}},
+					},
+				},
+			}},
+			want: `{"@timestamp":"2020-06-01T12:30:00.000Z","authority":"kuard.example.com","bytes_sent":"1024","duration":"42","method":"GET","path":"/kuard/","protocol":"HTTP/2","requested_server_name":"kuard.example.com","response_code":"200","response_flags":"-","upstream_host":"192.168.0.7:8080","upstream_service_time":"40","x_trace_id":"abc"}
+{"@timestamp":"2020-06-01T12:30:00.000Z","authority":"kuard.example.com","bytes_sent":"0","duration":"42","method":"POST","path":"/upload","protocol":"HTTP/1.1","requested_server_name":"kuard.example.com","response_code":"503","response_flags":"UH,UO","upstream_host":"192.168.0.7:8080"}
+`,
+		},
+		"custom fields": {
+			fields: []string{"@timestamp", "tenant_id=%REQ(X-Adobe-Tenant)%", "cache=%RESP(X-Cache)%",
+				"user=%DYNAMIC_METADATA(authz:user:name)%", "groups=%DYNAMIC_METADATA(authz:user:groups)%", "missing=%DYNAMIC_METADATA(authz:tenant)%", "route=%ROUTE_NAME%"},
+			messages: []*als.StreamAccessLogsMessage{{
+				LogEntries: &als.StreamAccessLogsMessage_HttpLogs{
+					HttpLogs: &als.StreamAccessLogsMessage_HTTPAccessLogEntries{
+						LogEntry: []*data.HTTPAccessLogEntry{{
+							CommonProperties: func() *data.AccessLogCommon {
+								c := common(nil)
+								c.RouteName = "kuard"
+								c.Metadata = &envoy_api_v2_core.Metadata{
+									FilterMetadata: map[string]*_struct.Struct{
+										"authz": {Fields: map[string]*_struct.Value{
+											"user": {Kind: &_struct.Value_StructValue{StructValue: &_struct.Struct{
+												Fields: map[string]*_struct.Value{
+													"name": {Kind: &_struct.Value_StringValue{StringValue: "alice"}},
+													"groups": {Kind: &_struct.Value_ListValue{ListValue: &_struct.ListValue{
+														Values: []*_struct.Value{{Kind: &_struct.Value_StringValue{StringValue: "admin"}}},
+													}}},
+												},
+											}}},
+										}},
+									},
+								}
+								return c
+							}(),
+							Request: &data.HTTPRequestProperties{
+								RequestHeaders: map[string]string{"x-adobe-tenant": "acme"},
+							},
+							Response: &data.HTTPResponseProperties{
+								ResponseHeaders: map[string]string{"x-cache": "HIT"},
+							},
+						}},
+					},
+				},
+			}},
+			want: `{"@timestamp":"2020-06-01T12:30:00.000Z","cache":"HIT","groups":"[\"admin\"]","route":"kuard","tenant_id":"acme","user":"alice"}
+`,
+		},
+		"tcp entries": {
+			fields: []string{"@timestamp", "authority", "bytes_received", "bytes_sent", "downstream_remote_address", "upstream_cluster"},
+			messages: []*als.StreamAccessLogsMessage{{
+				Identifier: &als.StreamAccessLogsMessage_Identifier{LogName: "contour"},
+				LogEntries: &als.StreamAccessLogsMessage_TcpLogs{
+					TcpLogs: &als.StreamAccessLogsMessage_TCPAccessLogEntries{
+						LogEntry: []*data.TCPAccessLogEntry{{
+							CommonProperties: common(nil),
+							ConnectionProperties: &data.ConnectionProperties{
+								ReceivedBytes: 512,
+								SentBytes:     2048,
+							},
+						}},
+					},
+				},
+			}},
+			want: `{"@timestamp":"2020-06-01T12:30:00.000Z","bytes_received":"512","bytes_sent":"2048","downstream_remote_address":"10.0.0.1:53211","upstream_cluster":"default/kuard/80/da39a3ee5e"}
+`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			r := NewAccessLogReceiver(tc.fields, &out, log)
+			st := &mockAccessLogStream{messages: tc.messages}
+			if err := r.StreamAccessLogs(st); err != nil {
+				t.Fatal(err)
+			}
+			if !st.closed {
+				t.Fatal("expected the stream to be closed")
+			}
+			assert.Equal(t, tc.want, out.String())
+		})
+	}
+}
+
+func TestAdobeNewAccessLogReceiver(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	r := NewAccessLogReceiver([]string{"@timestamp", "tenant", "tenant_id=%REQ(X-Adobe-Tenant?X-Tenant)%", "=%ROUTE_NAME%"}, ioutil.Discard, log)
+	want := []accessLogField{
+		{name: "@timestamp", command: "START_TIME"},
+		{name: "tenant_id", command: "REQ", param: "X-Adobe-Tenant?X-Tenant"},
+	}
+	if !reflect.DeepEqual(want, r.fields) {
+		t.Fatalf("expected fields %+v, got %+v", want, r.fields)
+	}
+}
+
+func socketAddress(address string, port uint32) *envoy_api_v2_core.Address {
+	return &envoy_api_v2_core.Address{
+		Address: &envoy_api_v2_core.Address_SocketAddress{
+			SocketAddress: &envoy_api_v2_core.SocketAddress{
+				Address: address,
+				PortSpecifier: &envoy_api_v2_core.SocketAddress_PortValue{
+					PortValue: port,
+				},
+			},
+		},
+	}
+}
+
+type mockAccessLogStream struct {
+	grpc.ServerStream
+	messages []*als.StreamAccessLogsMessage
+	closed   bool
+}
+
+func (m *mockAccessLogStream) Recv() (*als.StreamAccessLogsMessage, error) {
+	if len(m.messages) == 0 {
+		return nil, io.EOF
+	}
+	msg := m.messages[0]
+	m.messages = m.messages[1:]
+	return msg, nil
+}
+
+func (m *mockAccessLogStream) SendAndClose(*als.StreamAccessLogsResponse) error {
+	m.closed = true
+	return nil
+}
@@ -0,0 +1,46 @@
+package k8s
+
+import (
+	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// eventQPS and eventBurst limit the rate of the events recorded on
+	// each object.
+	eventQPS   = 1.0 / 30
+	eventBurst = 5
+)
+
+// NewEventRecorder returns an EventRecorder recording the events on
+// IngressRoutes and HTTPProxies through the API server, at a limited rate,
+// and a function stopping it.
+func (c *Clients) NewEventRecorder(log logrus.FieldLogger) (record.EventRecorder, func(), error) {
+	s := runtime.NewScheme()
+	for _, addToScheme := range []func(*runtime.Scheme) error{
+		scheme.AddToScheme,
+		ingressroutev1.AddToScheme,
+		projcontour.AddToScheme,
+	} {
+		if err := addToScheme(s); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	broadcaster := record.NewBroadcasterWithCorrelatorOptions(record.CorrelatorOptions{
+		QPS:       eventQPS,
+		BurstSize: eventBurst,
+	})
+	broadcaster.StartLogging(log.Debugf)
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{
+		Interface: c.ClientSet().CoreV1().Events(""),
+	})
+	recorder := broadcaster.NewRecorder(s, v1.EventSource{Component: "contour"})
+	return recorder, broadcaster.Shutdown, nil
+}
@@ -38,7 +38,7 @@ const (
 
 // StatusClient updates the Status on a Kubernetes object.
 type StatusClient interface {
-	SetStatus(status string, desc string, obj interface{}) error
+	SetStatus(status string, desc string, conditions []projcontour.StatusCondition, obj interface{}) error
 	GetStatus(obj interface{}) (*projcontour.Status, error)
 }
 
@@ -97,7 +97,7 @@ func (c *StatusCacher) GetStatus(obj interface{}) (*projcontour.Status, error) {
 }
 
 // SetStatus sets the IngressRoute status field to an Valid or Invalid status
-func (c *StatusCacher) SetStatus(status, desc string, obj interface{}) error {
+func (c *StatusCacher) SetStatus(status, desc string, conditions []projcontour.StatusCondition, obj interface{}) error {
 	if c.objectStatus == nil {
 		c.objectStatus = make(map[string]projcontour.Status)
 	}
@@ -105,6 +105,7 @@ func (c *StatusCacher) SetStatus(status, desc string, obj interface{}) error {
 	c.objectStatus[objectKey(obj)] = projcontour.Status{
 		CurrentStatus: status,
 		Description:   desc,
+		Conditions:    conditions,
 	}
 
 	return nil
@@ -122,25 +123,27 @@ func (irs *StatusWriter) GetStatus(obj interface{}) (*projcontour.Status, error)
 }
 
 // SetStatus sets the IngressRoute status field to an Valid or Invalid status
-func (irs *StatusWriter) SetStatus(status, desc string, existing interface{}) error {
+func (irs *StatusWriter) SetStatus(status, desc string, conditions []projcontour.StatusCondition, existing interface{}) error {
 	switch exist := existing.(type) {
 	case *ingressroutev1.IngressRoute:
 		// Check if update needed by comparing status & desc
-		if irs.updateNeeded(status, desc, exist.Status) {
+		if irs.updateNeeded(status, desc, conditions, exist.Status) {
 			updated := exist.DeepCopy()
 			updated.Status = projcontour.Status{
 				CurrentStatus: status,
 				Description:   desc,
+				Conditions:    conditions,
 			}
 			return irs.setIngressRouteStatusAdobe(updated)
 		}
 	case *projcontour.HTTPProxy:
 		// Check if update needed by comparing status & desc
-		if irs.updateNeeded(status, desc, exist.Status) {
+		if irs.updateNeeded(status, desc, conditions, exist.Status) {
 			updated := exist.DeepCopy()
 			updated.Status = projcontour.Status{
 				CurrentStatus: status,
 				Description:   desc,
+				Conditions:    conditions,
 			}
 			return irs.setHTTPProxyStatus(updated)
 		}
@@ -148,11 +151,12 @@ func (irs *StatusWriter) SetStatus(status, desc string, existing interface{}) er
 	return nil
 }
 
-func (irs *StatusWriter) updateNeeded(status, desc string, existing projcontour.Status) bool {
+func (irs *StatusWriter) updateNeeded(status, desc string, conditions []projcontour.StatusCondition, existing projcontour.Status) bool {
 	if existing.CurrentStatus != status || existing.Description != desc {
 		return true
 	}
-	return false
+	// Adobe - conditions
+	return !conditionsEqual(existing.Conditions, conditions)
 }
 
 func (irs *StatusWriter) setIngressRouteStatus(existing, updated *ingressroutev1.IngressRoute) error {
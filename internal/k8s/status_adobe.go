@@ -5,39 +5,16 @@ import (
 	"fmt"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 )
 
+// setIngressRouteStatusAdobe writes the status of the IngressRoute. A
+// conflict is returned as is, the StatusQueue reading the object again
+// before retrying.
 func (irs *StatusWriter) setIngressRouteStatusAdobe(updated *ingressroutev1.IngressRoute) error {
-	err := irs.setIngressRouteStatusAdobeImpl(updated)
-
-	if err != nil {
-		usLatest, err1 := irs.Client.Resource(ingressroutev1.IngressRouteGVR).Namespace(updated.Namespace).
-			Get(context.TODO(), updated.Name, metav1.GetOptions{})
-		if err1 != nil {
-			// error while retrieving the current one - abort
-			return err
-		}
-		intLatest, err2 := irs.Converter.FromUnstructured(usLatest)
-		if err2 != nil {
-			// same as before - abort
-			return err
-		}
-		irLatest := intLatest.(*ingressroutev1.IngressRoute)
-		if irs.updateNeeded(updated.CurrentStatus, updated.Description, irLatest.Status) {
-			irLatest.Status = projcontour.Status{
-				CurrentStatus: updated.CurrentStatus,
-				Description:   updated.Description,
-			}
-			err = irs.setIngressRouteStatusAdobeImpl(irLatest)
-		}
-	}
-	return err
-}
-
-func (irs *StatusWriter) setIngressRouteStatusAdobeImpl(updated *ingressroutev1.IngressRoute) error {
 	usUpdated, err := irs.Converter.ToUnstructured(updated)
 	if err != nil {
 		return fmt.Errorf("unable to convert status update to IngressRoute: %s", err)
@@ -48,3 +25,38 @@ func (irs *StatusWriter) setIngressRouteStatusAdobeImpl(updated *ingressroutev1.
 
 	return err
 }
+
+// latest reads the current version of the object from the API server.
+func (irs *StatusWriter) latest(obj interface{}) (interface{}, error) {
+	var gvr schema.GroupVersionResource
+	switch obj.(type) {
+	case *ingressroutev1.IngressRoute:
+		gvr = ingressroutev1.IngressRouteGVR
+	case *projcontour.HTTPProxy:
+		gvr = projcontour.HTTPProxyGVR
+	default:
+		return nil, fmt.Errorf("status not supported for object type %T", obj)
+	}
+
+	meta := obj.(metav1.ObjectMetaAccessor).GetObjectMeta()
+	usLatest, err := irs.Client.Resource(gvr).Namespace(meta.GetNamespace()).
+		Get(context.TODO(), meta.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return irs.Converter.FromUnstructured(usLatest)
+}
+
+// conditionsEqual returns whether both lists hold the same conditions in
+// the same order.
+func conditionsEqual(a, b []projcontour.StatusCondition) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -14,11 +14,13 @@
 package k8s
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/projectcontour/contour/internal/assert"
+	"github.com/sirupsen/logrus"
 
 	"k8s.io/client-go/dynamic/fake"
 
@@ -26,6 +28,7 @@ import (
 	ingressroutev1beta1 "github.com/projectcontour/contour/apis/contour/v1beta1"
 	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
 	projectcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	k8stesting "k8s.io/client-go/testing"
@@ -68,7 +71,7 @@ func TestSetIngressRouteStatus(t *testing.T) {
 			irs := StatusWriter{
 				Client: client,
 			}
-			if err := irs.SetStatus(tc.msg, tc.desc, tc.existing); err != nil {
+			if err := irs.SetStatus(tc.msg, tc.desc, nil, tc.existing); err != nil {
 				t.Fatal(err)
 			}
 
@@ -176,7 +179,7 @@ func TestSetHTTPProxyStatus(t *testing.T) {
 				Converter: usc,
 			}
 
-			if err := proxysw.SetStatus(tc.msg, tc.desc, tc.existing); err != nil {
+			if err := proxysw.SetStatus(tc.msg, tc.desc, nil, tc.existing); err != nil {
 				t.Fatal(fmt.Errorf("unable to set proxy status: %s", err))
 			}
 
@@ -294,3 +297,254 @@ func TestGetStatus(t *testing.T) {
 		expectedError:  errors.New("not implemented"),
 	})
 }
+
+func TestAdobeSetHTTPProxyStatusConditions(t *testing.T) {
+	valid := []projcontour.StatusCondition{
+		{Type: "Valid", Status: "True", Reason: "Valid", Message: "valid HTTPProxy", ObservedGeneration: 2},
+	}
+	warning := []projcontour.StatusCondition{
+		{Type: "Valid", Status: "True", Reason: "Valid", Message: "valid HTTPProxy", ObservedGeneration: 2},
+		{Type: "Warning", Status: "True", Reason: "CertificateExpiring", Message: "expiring", ObservedGeneration: 2},
+	}
+
+	tests := map[string]struct {
+		conditions []projcontour.StatusCondition
+		existing   *projcontour.HTTPProxy
+		expected   *projcontour.HTTPProxy
+	}{
+		"conditions added": {
+			conditions: valid,
+			existing: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status: projcontour.Status{
+					CurrentStatus: "valid",
+					Description:   "valid HTTPProxy",
+				},
+			},
+			expected: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status: projcontour.Status{
+					CurrentStatus: "valid",
+					Description:   "valid HTTPProxy",
+					Conditions:    valid,
+				},
+			},
+		},
+		"conditions changed": {
+			conditions: warning,
+			existing: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status: projcontour.Status{
+					CurrentStatus: "valid",
+					Description:   "valid HTTPProxy",
+					Conditions:    valid,
+				},
+			},
+			expected: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status: projcontour.Status{
+					CurrentStatus: "valid",
+					Description:   "valid HTTPProxy",
+					Conditions:    warning,
+				},
+			},
+		},
+		"conditions unchanged": {
+			conditions: valid,
+			existing: &projcontour.HTTPProxy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+				Status: projcontour.Status{
+					CurrentStatus: "valid",
+					Description:   "valid HTTPProxy",
+					Conditions:    valid,
+				},
+			},
+			expected: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			if err := projcontour.AddToScheme(s); err != nil {
+				t.Fatalf("adding to scheme: %s", err)
+			}
+
+			usc, err := NewUnstructuredConverter()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got *projcontour.HTTPProxy
+			client := fake.NewSimpleDynamicClient(s, tc.existing)
+			client.PrependReactor("*", "httpproxies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				switch updateAction := action.(type) {
+				default:
+					return true, nil, fmt.Errorf("got unexpected action of type: %T", action)
+				case k8stesting.UpdateActionImpl:
+					obj, err := usc.FromUnstructured(updateAction.GetObject())
+					if err != nil {
+						return true, nil, err
+					}
+					got = obj.(*projcontour.HTTPProxy)
+					return true, tc.existing, nil
+				}
+			})
+
+			proxysw := StatusWriter{
+				Client:    client,
+				Converter: usc,
+			}
+			if err := proxysw.SetStatus("valid", "valid HTTPProxy", tc.conditions, tc.existing); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestAdobeStatusQueue(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := projcontour.AddToScheme(s); err != nil {
+		t.Fatalf("adding to scheme: %s", err)
+	}
+
+	usc, err := NewUnstructuredConverter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &projcontour.HTTPProxy{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "uid"},
+	}
+
+	var (
+		verbs     []string
+		got       []projcontour.Status
+		conflicts = 1
+	)
+	client := fake.NewSimpleDynamicClient(s, existing)
+	client.PrependReactor("*", "httpproxies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		verbs = append(verbs, action.GetVerb())
+		switch action := action.(type) {
+		case k8stesting.UpdateActionImpl:
+			if conflicts > 0 {
+				conflicts--
+				return true, nil, apierrors.NewConflict(projcontour.HTTPProxyGVR.GroupResource(), "test", errors.New("stale"))
+			}
+			obj, err := usc.FromUnstructured(action.GetObject())
+			if err != nil {
+				return true, nil, err
+			}
+			got = append(got, obj.(*projcontour.HTTPProxy).Status)
+			return true, action.GetObject(), nil
+		case k8stesting.GetActionImpl:
+			us, err := usc.ToUnstructured(existing)
+			return true, us, err
+		default:
+			return true, nil, fmt.Errorf("got unexpected action of type: %T", action)
+		}
+	})
+
+	q := NewStatusQueue(&StatusWriter{Client: client, Converter: usc}, nil, logrus.New())
+
+	// the latest status queued is written
+	if err := q.SetStatus("invalid", "boo hiss", nil, existing); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.SetStatus("valid", "valid HTTPProxy", nil, existing); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, q.queue.Len())
+
+	// the conflict is retried once the object is read again
+	for i := 0; i < 2; i++ {
+		if !q.processNext(context.Background()) {
+			t.Fatal("queue shut down")
+		}
+	}
+	assert.Equal(t, []string{"update", "get", "update"}, verbs)
+	assert.Equal(t, []projcontour.Status{{CurrentStatus: "valid", Description: "valid HTTPProxy"}}, got)
+	assert.Equal(t, 0, q.queue.Len())
+
+	// the status already written is not queued again
+	if err := q.SetStatus("valid", "valid HTTPProxy", nil, existing); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, q.queue.Len())
+}
+
+func TestAdobeStatusQueueWritten(t *testing.T) {
+	s := runtime.NewScheme()
+	if err := projcontour.AddToScheme(s); err != nil {
+		t.Fatalf("adding to scheme: %s", err)
+	}
+
+	usc, err := NewUnstructuredConverter()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxy := func(resourceVersion, status string) *projcontour.HTTPProxy {
+		return &projcontour.HTTPProxy{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default", UID: "uid", ResourceVersion: resourceVersion},
+			Status:     projcontour.Status{CurrentStatus: status},
+		}
+	}
+
+	var (
+		got     []string
+		current = proxy("1", "valid")
+	)
+	client := fake.NewSimpleDynamicClient(s, current)
+	client.PrependReactor("*", "httpproxies", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		switch action := action.(type) {
+		case k8stesting.UpdateActionImpl:
+			obj, err := usc.FromUnstructured(action.GetObject())
+			if err != nil {
+				return true, nil, err
+			}
+			current = obj.(*projcontour.HTTPProxy)
+			got = append(got, current.Status.CurrentStatus)
+			return true, action.GetObject(), nil
+		case k8stesting.GetActionImpl:
+			us, err := usc.ToUnstructured(current)
+			return true, us, err
+		default:
+			return true, nil, fmt.Errorf("got unexpected action of type: %T", action)
+		}
+	})
+
+	q := NewStatusQueue(&StatusWriter{Client: client, Converter: usc}, nil, logrus.New())
+	set := func(status string, obj *projcontour.HTTPProxy) {
+		t.Helper()
+		if err := q.SetStatus(status, "", nil, obj); err != nil {
+			t.Fatal(err)
+		}
+		for q.queue.Len() > 0 {
+			q.processNext(context.Background())
+		}
+	}
+
+	// the object already has the status
+	set("valid", proxy("1", "valid"))
+	assert.Equal(t, []string(nil), got)
+
+	set("invalid", proxy("1", "valid"))
+	assert.Equal(t, []string{"invalid"}, got)
+
+	// the object held by the caller predates the write
+	set("invalid", proxy("1", "valid"))
+	assert.Equal(t, []string{"invalid"}, got)
+	set("valid", proxy("1", "valid"))
+	assert.Equal(t, []string{"invalid", "valid"}, got)
+
+	// the status was changed since the write
+	set("valid", proxy("3", "invalid"))
+	assert.Equal(t, []string{"invalid", "valid", "valid"}, got)
+
+	// the status of the deleted object is forgotten
+	q.Delete(proxy("4", "valid"))
+	assert.Equal(t, 0, len(q.written))
+	assert.Equal(t, 0, len(q.pending))
+}
@@ -0,0 +1,301 @@
+package k8s
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ingressroutev1 "github.com/projectcontour/contour/apis/contour/v1beta1"
+	projcontour "github.com/projectcontour/contour/apis/projectcontour/v1"
+	"github.com/projectcontour/contour/internal/metrics"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// statusQPS and statusBurst limit the rate of the status writes.
+	statusQPS   = 20
+	statusBurst = 100
+
+	// statusRetryDelay and statusMaxRetryDelay bound the exponential
+	// back-off of the failed status writes, which are retried up to
+	// statusMaxRetries times.
+	statusRetryDelay    = 100 * time.Millisecond
+	statusMaxRetryDelay = time.Minute
+	statusMaxRetries    = 10
+)
+
+// StatusQueue is a StatusClient writing the status of IngressRoutes and
+// HTTPProxies asynchronously through a rate limited workqueue, so that the
+// DAG rebuilds are not held up by the API server. The updates of an object
+// are deduplicated, only its latest status being written, and the failed
+// writes are retried with an exponential back-off, reading the object again
+// after a conflict.
+type StatusQueue struct {
+	writer  *StatusWriter
+	metrics *metrics.Metrics
+	log     logrus.FieldLogger
+	limiter *rate.Limiter
+	queue   workqueue.RateLimitingInterface
+
+	mu sync.Mutex
+	// pending holds the latest status of each object waiting to be written.
+	pending map[string]*statusUpdate
+	// inflight holds the status of each object being written.
+	inflight map[string]*statusUpdate
+	// written holds the last status written for each object, until the
+	// object is deleted.
+	written map[string]*statusUpdate
+}
+
+type statusUpdate struct {
+	status     string
+	desc       string
+	conditions []projcontour.StatusCondition
+	obj        interface{}
+	uid        types.UID
+
+	// resourceVersion is the version of the object the status was set
+	// on by the caller.
+	resourceVersion string
+
+	// stale is set once the write of the object conflicted, the object
+	// being read again before the next attempt.
+	stale bool
+}
+
+// sameStatus returns whether both updates set the same status on the same object.
+func (u *statusUpdate) sameStatus(other *statusUpdate) bool {
+	return u.uid == other.uid && u.status == other.status && u.desc == other.desc &&
+		conditionsEqual(u.conditions, other.conditions)
+}
+
+// NewStatusQueue returns a StatusQueue writing the status through writer.
+func NewStatusQueue(writer *StatusWriter, m *metrics.Metrics, log logrus.FieldLogger) *StatusQueue {
+	return &StatusQueue{
+		writer:  writer,
+		metrics: m,
+		log:     log,
+		limiter: rate.NewLimiter(statusQPS, statusBurst),
+		queue: workqueue.NewRateLimitingQueue(
+			workqueue.NewItemExponentialFailureRateLimiter(statusRetryDelay, statusMaxRetryDelay)),
+		pending:  make(map[string]*statusUpdate),
+		inflight: make(map[string]*statusUpdate),
+		written:  make(map[string]*statusUpdate),
+	}
+}
+
+// GetStatus returns the status from the underlying StatusWriter.
+func (q *StatusQueue) GetStatus(obj interface{}) (*projcontour.Status, error) {
+	return q.writer.GetStatus(obj)
+}
+
+// SetStatus queues the write of the status of the object, replacing the
+// status already queued for it if any. Nothing is written if the object
+// already has the status, or if it predates the write of the status.
+func (q *StatusQueue) SetStatus(status, desc string, conditions []projcontour.StatusCondition, obj interface{}) error {
+	key := objectKey(obj)
+	meta := obj.(metav1.ObjectMetaAccessor).GetObjectMeta()
+	upd := &statusUpdate{
+		status:          status,
+		desc:            desc,
+		conditions:      conditions,
+		obj:             obj,
+		uid:             meta.GetUID(),
+		resourceVersion: meta.GetResourceVersion(),
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.upToDate(key, upd) {
+		delete(q.pending, key)
+		q.setDepth()
+		return nil
+	}
+	if pending, ok := q.pending[key]; ok {
+		upd.stale = pending.stale
+	}
+	// the object held by the caller does not have the status written
+	// last, it is read again before the write
+	upd.stale = upd.stale || q.predatesWrite(key, upd)
+	q.pending[key] = upd
+	q.queue.Add(key)
+	q.setDepth()
+	return nil
+}
+
+// upToDate returns whether the object already has the status of upd, q.mu
+// being held.
+func (q *StatusQueue) upToDate(key string, upd *statusUpdate) bool {
+	if _, ok := q.inflight[key]; ok {
+		// the status being written may differ
+		return false
+	}
+	if q.predatesWrite(key, upd) {
+		return q.written[key].sameStatus(upd)
+	}
+	existing, ok := objectStatus(upd.obj)
+	return ok && !q.writer.updateNeeded(upd.status, upd.desc, upd.conditions, existing)
+}
+
+// predatesWrite returns whether the object of upd was read before the last
+// write of its status, which is yet to be observed, q.mu being held.
+func (q *StatusQueue) predatesWrite(key string, upd *statusUpdate) bool {
+	written, ok := q.written[key]
+	return ok && written.uid == upd.uid && written.resourceVersion == upd.resourceVersion
+}
+
+// Delete forgets the status queued or written for the deleted object.
+func (q *StatusQueue) Delete(obj interface{}) {
+	switch obj.(type) {
+	case *ingressroutev1.IngressRoute, *projcontour.HTTPProxy:
+	default:
+		return
+	}
+	key := objectKey(obj)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	delete(q.pending, key)
+	delete(q.inflight, key)
+	delete(q.written, key)
+	q.setDepth()
+}
+
+// Start writes the queued statuses until stop is closed.
+func (q *StatusQueue) Start(stop <-chan struct{}) error {
+	q.log.Info("started status queue")
+	defer q.log.Info("stopped status queue")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stop
+		cancel()
+		q.queue.ShutDown()
+	}()
+
+	for q.processNext(ctx) {
+	}
+	return nil
+}
+
+// processNext writes the status of the next object of the queue. It
+// returns false once the queue is shut down.
+func (q *StatusQueue) processNext(ctx context.Context) bool {
+	item, shutdown := q.queue.Get()
+	if shutdown {
+		return false
+	}
+	key := item.(string)
+	defer q.queue.Done(key)
+
+	q.mu.Lock()
+	upd, ok := q.pending[key]
+	delete(q.pending, key)
+	if ok {
+		q.inflight[key] = upd
+	}
+	q.setDepth()
+	q.mu.Unlock()
+	if !ok {
+		// already written, or no longer needed
+		q.queue.Forget(key)
+		return true
+	}
+
+	if err := q.limiter.Wait(ctx); err != nil {
+		// shutting down
+		return false
+	}
+
+	err := q.write(upd)
+
+	q.mu.Lock()
+	// the object may have been deleted during the write
+	deleted := q.inflight[key] != upd
+	if !deleted {
+		delete(q.inflight, key)
+	}
+	q.mu.Unlock()
+
+	switch {
+	case err == nil:
+		q.queue.Forget(key)
+		if !deleted {
+			q.mu.Lock()
+			q.written[key] = upd
+			q.mu.Unlock()
+		}
+		return true
+	case deleted || apierrors.IsNotFound(err):
+		q.queue.Forget(key)
+		q.mu.Lock()
+		delete(q.written, key)
+		q.mu.Unlock()
+		return true
+	}
+
+	reason := "error"
+	if apierrors.IsConflict(err) {
+		reason = "conflict"
+	}
+	if q.metrics != nil {
+		q.metrics.IncStatusUpdateFailure(KindOf(upd.obj), reason)
+	}
+
+	log := q.log.WithError(err).WithField("key", key).WithField("reason", reason)
+	if q.queue.NumRequeues(key) >= statusMaxRetries {
+		log.Error("failed to set status, giving up")
+		q.queue.Forget(key)
+		return true
+	}
+	log.Warn("failed to set status, retrying")
+
+	q.mu.Lock()
+	if _, ok := q.pending[key]; !ok {
+		// retry unless a newer status was queued meanwhile
+		upd.stale = upd.stale || reason == "conflict"
+		q.pending[key] = upd
+	}
+	q.setDepth()
+	q.mu.Unlock()
+	q.queue.AddRateLimited(key)
+	return true
+}
+
+// objectStatus returns the status of the object.
+func objectStatus(obj interface{}) (projcontour.Status, bool) {
+	switch obj := obj.(type) {
+	case *ingressroutev1.IngressRoute:
+		return obj.Status, true
+	case *projcontour.HTTPProxy:
+		return obj.Status, true
+	default:
+		return projcontour.Status{}, false
+	}
+}
+
+// write writes the status, reading the object again if it is stale.
+func (q *StatusQueue) write(upd *statusUpdate) error {
+	obj := upd.obj
+	if upd.stale {
+		latest, err := q.writer.latest(obj)
+		if err != nil {
+			return err
+		}
+		obj = latest
+	}
+	return q.writer.SetStatus(upd.status, upd.desc, upd.conditions, obj)
+}
+
+// setDepth records the number of objects waiting to be written, q.mu
+// being held.
+func (q *StatusQueue) setDepth() {
+	if q.metrics != nil {
+		q.metrics.SetStatusQueueDepth(len(q.pending))
+	}
+}
@@ -42,9 +42,19 @@ type Metrics struct {
 	CacheHandlerOnUpdateSummary prometheus.Summary
 	EventHandlerOperations      *prometheus.CounterVec
 
+	// Adobe - expiry of the certificates served
+	certificateExpiryGauge *prometheus.GaugeVec
+
+	// Adobe - status update queue
+	statusQueueDepthGauge    *prometheus.GaugeVec
+	statusUpdateFailureCount *prometheus.CounterVec
+
 	// Keep a local cache of metrics for comparison on updates
 	ingressRouteMetricCache *RouteMetric
 	proxyMetricCache        *RouteMetric
+
+	// Adobe - certificate expiry cache
+	certificateExpiryCache map[CertificateMeta]time.Time
 }
 
 // RouteMetric stores various metrics for IngressRoute objects
@@ -78,6 +88,11 @@ const (
 	DAGRebuildGauge             = "contour_dagrebuild_timestamp"
 	cacheHandlerOnUpdateSummary = "contour_cachehandler_onupdate_duration_seconds"
 	eventHandlerOperations      = "contour_eventhandler_operation_total"
+
+	CertificateExpiryGauge = "contour_certificate_expiry_timestamp_seconds"
+
+	StatusQueueDepthGauge    = "contour_status_queue_depth"
+	StatusUpdateFailureCount = "contour_status_update_failures_total"
 )
 
 // NewMetrics creates a new set of metrics and registers them with
@@ -186,6 +201,27 @@ func NewMetrics(registry *prometheus.Registry) *Metrics {
 			},
 			[]string{"op", "kind"},
 		),
+		certificateExpiryGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: CertificateExpiryGauge,
+				Help: "Timestamp of the expiry of the earliest expiring certificate of each Secret served.",
+			},
+			[]string{"namespace", "name"},
+		),
+		statusQueueDepthGauge: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: StatusQueueDepthGauge,
+				Help: "Number of objects whose status is waiting to be written.",
+			},
+			[]string{},
+		),
+		statusUpdateFailureCount: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: StatusUpdateFailureCount,
+				Help: "Total number of failed status writes by object kind and reason, either conflict or error.",
+			},
+			[]string{"kind", "reason"},
+		),
 	}
 	m.buildInfoGauge.WithLabelValues(build.Branch, build.Sha, build.Version).Set(1)
 	m.register(registry)
@@ -209,6 +245,9 @@ func (m *Metrics) register(registry *prometheus.Registry) {
 		m.dagRebuildGauge,
 		m.CacheHandlerOnUpdateSummary,
 		m.EventHandlerOperations,
+		m.certificateExpiryGauge,
+		m.statusQueueDepthGauge,
+		m.statusUpdateFailureCount,
 	)
 }
 
@@ -232,6 +271,9 @@ func (m *Metrics) Zero() {
 	m.SetDAGLastRebuilt(time.Now())
 	m.SetIngressRouteMetric(zeroes)
 	m.SetHTTPProxyMetric(zeroes)
+	m.SetCertificateExpiry(map[CertificateMeta]time.Time{{}: time.Now()})
+	m.SetStatusQueueDepth(0)
+	m.IncStatusUpdateFailure("HTTPProxy", "conflict")
 
 	m.EventHandlerOperations.WithLabelValues("add", "Secret").Inc()
 
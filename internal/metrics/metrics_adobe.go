@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"time"
+)
+
+// CertificateMeta holds the namespace and name of the Secret of a
+// certificate metric.
+type CertificateMeta struct {
+	Namespace, Name string
+}
+
+// SetCertificateExpiry sets the expiry of the certificates of each Secret
+// served, removing the Secrets no longer served.
+func (m *Metrics) SetCertificateExpiry(expiries map[CertificateMeta]time.Time) {
+	for meta, notAfter := range expiries {
+		m.certificateExpiryGauge.WithLabelValues(meta.Namespace, meta.Name).Set(float64(notAfter.Unix()))
+		delete(m.certificateExpiryCache, meta)
+	}
+
+	// All metrics processed, now remove what's left as they are not needed
+	for meta := range m.certificateExpiryCache {
+		m.certificateExpiryGauge.DeleteLabelValues(meta.Namespace, meta.Name)
+	}
+
+	m.certificateExpiryCache = expiries
+}
+
+// SetStatusQueueDepth sets the number of objects whose status is waiting
+// to be written.
+func (m *Metrics) SetStatusQueueDepth(depth int) {
+	m.statusQueueDepthGauge.WithLabelValues().Set(float64(depth))
+}
+
+// IncStatusUpdateFailure counts a failed status write of an object of the
+// kind, the reason being either conflict or error.
+func (m *Metrics) IncStatusUpdateFailure(kind, reason string) {
+	m.statusUpdateFailureCount.WithLabelValues(kind, reason).Inc()
+}
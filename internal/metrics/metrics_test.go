@@ -1367,3 +1367,48 @@ func TestRemoveProxyMetric(t *testing.T) {
 		})
 	}
 }
+
+func TestSetCertificateExpiry(t *testing.T) {
+	gauge := func(namespace, name string, value float64) *io_prometheus_client.Metric {
+		return &io_prometheus_client.Metric{
+			Label: []*io_prometheus_client.LabelPair{{
+				Name:  func() *string { n := "name"; return &n }(),
+				Value: &name,
+			}, {
+				Name:  func() *string { n := "namespace"; return &n }(),
+				Value: &namespace,
+			}},
+			Gauge: &io_prometheus_client.Gauge{
+				Value: &value,
+			},
+		}
+	}
+
+	r := prometheus.NewRegistry()
+	m := NewMetrics(r)
+	m.SetCertificateExpiry(map[CertificateMeta]time.Time{
+		{Namespace: "default", Name: "cert"}: time.Unix(1600000000, 0),
+		{Namespace: "default", Name: "ca"}:   time.Unix(1500000000, 0),
+	})
+	m.SetCertificateExpiry(map[CertificateMeta]time.Time{
+		{Namespace: "default", Name: "cert"}: time.Unix(1700000000, 0),
+	})
+
+	gathering, err := r.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []*io_prometheus_client.Metric{}
+	for _, mf := range gathering {
+		if mf.GetName() == CertificateExpiryGauge {
+			got = mf.Metric
+		}
+	}
+
+	want := []*io_prometheus_client.Metric{
+		gauge("default", "cert", 1700000000),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("write certificate expiry metric failed, want: %v got: %v", want, got)
+	}
+}